@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+
+	"git-issues/application"
+	"git-issues/domain"
+	"git-issues/features/conf"
+)
+
+const strAliasUsage = `usage:
+  ghissues alias set <name> '<command line>'
+  ghissues alias list
+  ghissues alias delete <name>`
+
+// expandAlias replaces an alias in the first argument by its command line.
+// The result is not expanded again, so aliases cannot loop.
+func expandAlias(args []string) ([]string, error) {
+	if len(args) == 0 || isCommand(args[0]) {
+		return args, nil
+	}
+	config, err := application.LoadConfig(domain.ConfigFile)
+	if err != nil {
+		return args, nil
+	}
+	expansion, ok := config.Aliases[args[0]]
+	if !ok {
+		return args, nil
+	}
+	return conf.ExpandAlias(expansion, args[1:])
+}
+
+func runAlias(featureConfig *conf.Feature, args []string) {
+	if len(args) < 1 {
+		fmt.Println(strAliasUsage)
+		return
+	}
+
+	switch args[0] {
+	case "set":
+		if len(args) != 3 {
+			fmt.Println(strAliasUsage)
+			return
+		}
+		if err := featureConfig.SetAlias(args[1], args[2], commandNames()); err != nil {
+			fmt.Printf("error on set alias: %v\n", err)
+			return
+		}
+		fmt.Printf("alias %s set\n", args[1])
+
+	case "list":
+		aliases, names, err := featureConfig.Aliases()
+		if err != nil {
+			fmt.Printf("error on list aliases: %v\n", err)
+			return
+		}
+		for _, name := range names {
+			fmt.Printf("%s\t%s\n", name, aliases[name])
+		}
+
+	case "delete":
+		if len(args) != 2 {
+			fmt.Println(strAliasUsage)
+			return
+		}
+		if err := featureConfig.DeleteAlias(args[1]); err != nil {
+			fmt.Printf("error on delete alias: %v\n", err)
+			return
+		}
+		fmt.Printf("alias %s deleted\n", args[1])
+
+	default:
+		fmt.Println(strAliasUsage)
+	}
+}
@@ -2,11 +2,20 @@ package application
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 
 	"git-issues/domain"
+	"git-issues/service/host"
 )
 
+// DefaultProfile names the top level values of the config, unless a profile
+// is named like it.
+const DefaultProfile = "default"
+
+var ErrProfileNotFound = errors.New("profile not found")
+
 func LoadConfig(filePath string) (*domain.Config, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
@@ -21,3 +30,98 @@ func LoadConfig(filePath string) (*domain.Config, error) {
 
 	return &config, nil
 }
+
+// LoadProfile loads the config with the values of the named profile, or of
+// the current profile when name is empty, applied over the top level ones.
+func LoadProfile(filePath string, name string) (*domain.Config, error) {
+	config, err := LoadConfig(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if err = ApplyProfile(config, name); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// ApplyProfile copies the values set by the profile into the config. Without
+// a name the current profile is used, and DefaultProfile keeps the top level
+// values. The token and TLS files of the
+// resulting host apply next, and the values of the profile win over them.
+// The top level token, GitHub App and TLS files only apply to the top level
+// host, so they are not sent to another one.
+func ApplyProfile(config *domain.Config, name string) error {
+	if name == "" {
+		name = config.CurrentProfile
+	}
+
+	var profile domain.Profile
+	if name != "" {
+		var ok bool
+		profile, ok = config.Profiles[name]
+		switch {
+		case ok:
+			config.CurrentProfile = name
+		case name == DefaultProfile:
+			config.CurrentProfile = ""
+		default:
+			return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+		}
+	}
+
+	top := resolveHost(config)
+	override(&config.Host, profile.Host)
+	if profile.Host != "" {
+		config.APIBaseURL = ""
+	}
+	override(&config.APIBaseURL, profile.APIBaseURL)
+	if profile.APIBaseURL != "" && profile.Host == "" {
+		config.Host = ""
+	}
+	h := resolveHost(config)
+	if config.Host != "" {
+		config.Host = h.Name
+	}
+	config.APIBaseURL = h.REST
+	if h.Name != top.Name {
+		config.Token = ""
+		config.AppID, config.AppInstallationID, config.AppPrivateKey = 0, 0, ""
+		config.CACert, config.ClientCert, config.ClientKey = "", "", ""
+	}
+
+	if settings, ok := host.Settings(config, h); ok {
+		override(&config.Token, settings.Token)
+		override(&config.CACert, settings.CACert)
+		override(&config.ClientCert, settings.ClientCert)
+		override(&config.ClientKey, settings.ClientKey)
+	}
+
+	override(&config.Token, profile.Token)
+	override(&config.Owner, profile.Owner)
+	override(&config.Repo, profile.Repo)
+	override(&config.Editor, profile.Editor)
+	override(&config.Pager, profile.Pager)
+	override(&config.CACert, profile.CACert)
+	override(&config.ClientCert, profile.ClientCert)
+	override(&config.ClientKey, profile.ClientKey)
+	override(&config.OAuthClientID, profile.OAuthClientID)
+	if profile.AppID != 0 {
+		config.AppID, config.AppInstallationID, config.AppPrivateKey = profile.AppID, profile.AppInstallationID, profile.AppPrivateKey
+	}
+	return nil
+}
+
+// resolveHost is the host of the config: the one of Host when set, else the
+// one of the api url.
+func resolveHost(config *domain.Config) host.Host {
+	if config.Host != "" {
+		return host.New(config.Host)
+	}
+	return host.FromAPIBaseURL(config.APIBaseURL)
+}
+
+func override(value *string, with string) {
+	if with != "" {
+		*value = with
+	}
+}
@@ -2,8 +2,10 @@ package application
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"git-issues/domain"
@@ -98,3 +100,147 @@ func TestLoadConfig_FileNotFound(t *testing.T) {
 		t.Fatalf("expected file-not-found error, got %T: %v", err, err)
 	}
 }
+
+func TestApplyProfile(t *testing.T) {
+	base := func() *domain.Config {
+		return &domain.Config{
+			Token:      "top-token",
+			Owner:      "top-owner",
+			Repo:       "top-repo",
+			Editor:     "vim",
+			APIBaseURL: domain.ApiBaseUrl,
+			Profiles: map[string]domain.Profile{
+				"work":  {Host: "ghe.example.com", Token: "work-token", Repo: "work-repo"},
+				"oss":   {Owner: "octo", Repo: "tools", APIBaseURL: "http://localhost:8080"},
+				"ghe":   {Host: "https://GIT.corp.example/"},
+				"bot":   {AppID: 12, AppInstallationID: 34, AppPrivateKey: "/etc/bot.pem"},
+				"bare":  {Host: "ghe.example.com"},
+				"api":   {APIBaseURL: "https://git.corp.example/api/v3"},
+				"tls":   {Host: "git.corp.example", CACert: "/etc/own-ca.pem", ClientCert: "/etc/own.pem", ClientKey: "/etc/own.key"},
+				"oauth": {OAuthClientID: "Iv1.profile"},
+			},
+			Hosts: map[string]domain.HostConfig{
+				"git.corp.example": {Token: "ghe-token", CACert: "/etc/ghe-ca.pem", ClientCert: "/etc/me.pem", ClientKey: "/etc/me.key"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		current string
+		profile string
+		topApp  bool
+		topTLS  bool
+		want    domain.Config
+		wantErr error
+	}{
+		{
+			name: "no profile keeps top level values",
+			want: domain.Config{Token: "top-token", Owner: "top-owner", Repo: "top-repo", Editor: "vim", APIBaseURL: domain.ApiBaseUrl},
+		},
+		{
+			name:    "named profile with host",
+			profile: "work",
+			want: domain.Config{Token: "work-token", Owner: "top-owner", Repo: "work-repo", Editor: "vim",
+				Host: "ghe.example.com", APIBaseURL: "https://ghe.example.com/api/v3", CurrentProfile: "work"},
+		},
+		{
+			name:    "current profile",
+			current: "oss",
+			want: domain.Config{Owner: "octo", Repo: "tools", Editor: "vim",
+				APIBaseURL: "http://localhost:8080", CurrentProfile: "oss"},
+		},
+		{
+			name:    "flag wins over current profile",
+			current: "oss",
+			profile: "work",
+			want: domain.Config{Token: "work-token", Owner: "top-owner", Repo: "work-repo", Editor: "vim",
+				Host: "ghe.example.com", APIBaseURL: "https://ghe.example.com/api/v3", CurrentProfile: "work"},
+		},
+		{
+			name:    "host token and tls files",
+			profile: "ghe",
+			want: domain.Config{Token: "ghe-token", Owner: "top-owner", Repo: "top-repo", Editor: "vim",
+				Host: "git.corp.example", APIBaseURL: "https://git.corp.example/api/v3", CurrentProfile: "ghe",
+				CACert: "/etc/ghe-ca.pem", ClientCert: "/etc/me.pem", ClientKey: "/etc/me.key"},
+		},
+		{
+			name:    "github app",
+			profile: "bot",
+			want: domain.Config{Token: "top-token", Owner: "top-owner", Repo: "top-repo", Editor: "vim", APIBaseURL: domain.ApiBaseUrl,
+				CurrentProfile: "bot", AppID: 12, AppInstallationID: 34, AppPrivateKey: "/etc/bot.pem"},
+		},
+		{
+			name:    "other host drops the top level token and app",
+			profile: "bare",
+			topApp:  true,
+			want: domain.Config{Owner: "top-owner", Repo: "top-repo", Editor: "vim",
+				Host: "ghe.example.com", APIBaseURL: "https://ghe.example.com/api/v3", CurrentProfile: "bare"},
+		},
+		{
+			name:    "other host without tls settings drops the top level tls files",
+			profile: "bare",
+			topTLS:  true,
+			want: domain.Config{Owner: "top-owner", Repo: "top-repo", Editor: "vim",
+				Host: "ghe.example.com", APIBaseURL: "https://ghe.example.com/api/v3", CurrentProfile: "bare"},
+		},
+		{
+			name:    "same host keeps the top level tls files",
+			profile: "oauth",
+			topTLS:  true,
+			want: domain.Config{Token: "top-token", Owner: "top-owner", Repo: "top-repo", Editor: "vim", APIBaseURL: domain.ApiBaseUrl,
+				CurrentProfile: "oauth", OAuthClientID: "Iv1.profile", CACert: "/etc/corp-ca.pem", ClientCert: "/etc/corp.pem", ClientKey: "/etc/corp.key"},
+		},
+		{
+			name:    "api url picks the host settings",
+			profile: "api",
+			want: domain.Config{Token: "ghe-token", Owner: "top-owner", Repo: "top-repo", Editor: "vim",
+				APIBaseURL: "https://git.corp.example/api/v3", CurrentProfile: "api",
+				CACert: "/etc/ghe-ca.pem", ClientCert: "/etc/me.pem", ClientKey: "/etc/me.key"},
+		},
+		{
+			name:    "profile tls files win over the host ones",
+			profile: "tls",
+			want: domain.Config{Token: "ghe-token", Owner: "top-owner", Repo: "top-repo", Editor: "vim",
+				Host: "git.corp.example", APIBaseURL: "https://git.corp.example/api/v3", CurrentProfile: "tls",
+				CACert: "/etc/own-ca.pem", ClientCert: "/etc/own.pem", ClientKey: "/etc/own.key"},
+		},
+		{
+			name:    "oauth client id",
+			profile: "oauth",
+			want: domain.Config{Token: "top-token", Owner: "top-owner", Repo: "top-repo", Editor: "vim", APIBaseURL: domain.ApiBaseUrl,
+				CurrentProfile: "oauth", OAuthClientID: "Iv1.profile"},
+		},
+		{
+			name:    "default profile is the top level",
+			current: "oss",
+			profile: DefaultProfile,
+			want:    domain.Config{Token: "top-token", Owner: "top-owner", Repo: "top-repo", Editor: "vim", APIBaseURL: domain.ApiBaseUrl},
+		},
+		{name: "unknown profile", profile: "home", wantErr: ErrProfileNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base()
+			cfg.CurrentProfile = tt.current
+			if tt.topApp {
+				cfg.AppID, cfg.AppInstallationID, cfg.AppPrivateKey = 1, 2, "/etc/top.pem"
+			}
+			if tt.topTLS {
+				cfg.CACert, cfg.ClientCert, cfg.ClientKey = "/etc/corp-ca.pem", "/etc/corp.pem", "/etc/corp.key"
+			}
+			err := ApplyProfile(cfg, tt.profile)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			cfg.Profiles, cfg.Hosts = nil, nil
+			if !reflect.DeepEqual(*cfg, tt.want) {
+				t.Errorf("got %+v\nwant %+v", *cfg, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// issueNumber reads the issue number from the first positional argument.
+func issueNumber(args []string) (int, bool) {
+	if len(args) < 1 {
+		fmt.Println("please provide an issue number")
+		return 0, false
+	}
+	number, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Println("please provide a valid issue number")
+		return 0, false
+	}
+	return number, true
+}
+
+// parseFlags parses flags placed before, between or after the positional
+// arguments and returns the positional ones. Everything after -- is
+// positional, so `search -- -label:bug` keeps its query.
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := flags.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		args = rest
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// stringList collects a flag that may be given more than once.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// splitList splits a comma separated flag value, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// profileFlag removes --profile <name> or --profile=<name> from the
+// arguments, wherever it is before --, and returns the profile name.
+func profileFlag(args []string) (string, []string) {
+	var profile string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return profile, append(rest, args[i:]...)
+		case arg == "--profile" || arg == "-profile":
+			if i+1 < len(args) {
+				profile = args[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--profile="):
+			profile = strings.TrimPrefix(arg, "--profile=")
+		case strings.HasPrefix(arg, "-profile="):
+			profile = strings.TrimPrefix(arg, "-profile=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return profile, rest
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		wantPositional []string
+		wantLabel      string
+		wantWeb        bool
+	}{
+		{name: "flags first", args: []string{"--label", "bug", "crash"}, wantPositional: []string{"crash"}, wantLabel: "bug"},
+		{name: "flags between", args: []string{"crash", "--web", "report"}, wantPositional: []string{"crash", "report"}, wantWeb: true},
+		{name: "flags last", args: []string{"crash", "--label=bug"}, wantPositional: []string{"crash"}, wantLabel: "bug"},
+		{name: "after the terminator", args: []string{"--", "-label:bug"}, wantPositional: []string{"-label:bug"}},
+		{
+			name:           "flags before the terminator",
+			args:           []string{"crash", "--web", "--", "--label", "bug"},
+			wantPositional: []string{"crash", "--label", "bug"},
+			wantWeb:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := flag.NewFlagSet("search", flag.ContinueOnError)
+			label := flags.String("label", "", "")
+			web := flags.Bool("web", false, "")
+
+			got, err := parseFlags(flags, tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.wantPositional) || *label != tt.wantLabel || *web != tt.wantWeb {
+				t.Errorf("got %q, label %q, web %v", got, *label, *web)
+			}
+		})
+	}
+}
+
+func TestParseFlagsUnknown(t *testing.T) {
+	flags := flag.NewFlagSet("search", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	if _, err := parseFlags(flags, []string{"crash", "-label:bug"}); err == nil {
+		t.Error("want an error for an unknown flag")
+	}
+}
+
+func TestProfileFlag(t *testing.T) {
+	profile, rest := profileFlag([]string{"--profile", "work", "search", "--", "--profile=other"})
+	if profile != "work" || !reflect.DeepEqual(rest, []string{"search", "--", "--profile=other"}) {
+		t.Errorf("got %q and %q", profile, rest)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"git-issues/application"
+	"git-issues/domain"
+	"git-issues/features/auth"
+	"git-issues/features/conf"
+	"git-issues/service/client"
+)
+
+const strAuthUsage = `usage:
+  ghissues auth login [--scopes repo,project] [--client-id <id>]
+  ghissues auth status
+  ghissues auth logout`
+
+// runAuth works without a config file, so login can be the first command a
+// new user runs.
+func runAuth(featureConfig *conf.Feature, profile string, args []string) {
+	if len(args) < 1 {
+		fmt.Println(strAuthUsage)
+		return
+	}
+
+	config, err := application.LoadProfile(domain.ConfigFile, profile)
+	if errors.Is(err, os.ErrNotExist) {
+		config, err = &domain.Config{APIBaseURL: domain.ApiBaseUrl}, nil
+	}
+	if err != nil {
+		fmt.Printf("could not load conf: %v\n", err)
+		return
+	}
+
+	flags := flag.NewFlagSet("auth "+args[0], flag.ContinueOnError)
+	scopes := flags.String("scopes", "", "comma separated token scopes to request")
+	clientID := flags.String("client-id", "", "client id of the OAuth app")
+	if _, err = parseFlags(flags, args[1:]); err != nil {
+		return
+	}
+
+	authFeature := auth.New(config, client.New(config))
+	switch args[0] {
+	case "login":
+		authFeature.SetClientID(*clientID)
+		token, err := authFeature.Login(splitList(*scopes))
+		if err != nil {
+			fmt.Printf("error on login: %v\n", err)
+			return
+		}
+		if err = featureConfig.SaveToken(profile, token); err != nil {
+			fmt.Printf("error on save token: %v\n", err)
+			return
+		}
+		fmt.Println("logged in, token saved")
+
+	case "status":
+		if config.AppID != 0 {
+			fmt.Printf("authenticated as GitHub App %d, installation %d\n", config.AppID, config.AppInstallationID)
+			return
+		}
+		if config.Token == "" {
+			fmt.Println("not logged in, run 'ghissues auth login'")
+			return
+		}
+		status, err := authFeature.Status()
+		if err != nil {
+			fmt.Printf("error on auth status: %v\n", err)
+			return
+		}
+		if err = auth.PrintStatus(os.Stdout, status); err != nil {
+			fmt.Printf("error on print status: %v\n", err)
+		}
+
+	case "logout":
+		removed, err := featureConfig.RemoveToken(profile)
+		if err != nil {
+			fmt.Printf("error on logout: %v\n", err)
+			return
+		}
+		if len(removed) == 0 {
+			fmt.Println("not logged in, the config has no token for this profile and host")
+			return
+		}
+		fmt.Printf("logged out, the token was removed from the config (%s); revoke it in the GitHub settings to invalidate it\n", strings.Join(removed, ", "))
+
+	default:
+		fmt.Println(strAuthUsage)
+	}
+}
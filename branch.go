@@ -0,0 +1,190 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"git-issues/application"
+	"git-issues/domain"
+	"git-issues/features/issue"
+	"git-issues/service/client"
+	"git-issues/service/git"
+)
+
+const strHooksUsage = `usage:
+  ghissues hooks install [--force]`
+
+func runStart(config *domain.Config, githubClient client.GitHubClient, args []string) {
+	flags := flag.NewFlagSet("start", flag.ContinueOnError)
+	inProgress := flags.Bool("in-progress", false, "add the in progress label")
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return
+	}
+	number, ok := issueNumber(positional)
+	if !ok {
+		return
+	}
+
+	started, err := issue.NewStart(config, githubClient, git.New("")).Start(number, *inProgress)
+	if started != nil {
+		if started.Created {
+			fmt.Printf("switched to a new branch '%s'\n", started.Branch)
+		} else {
+			fmt.Printf("switched to branch '%s'\n", started.Branch)
+		}
+	}
+	if err != nil {
+		fmt.Printf("error on start: %v\n", err)
+		return
+	}
+	fmt.Printf("issue #%d assigned to %s\n", number, started.Login)
+	if started.Label != "" {
+		fmt.Printf("label '%s' added\n", started.Label)
+	}
+}
+
+func runCurrent(config *domain.Config, githubClient client.GitHubClient) {
+	current, branch, err := issue.NewStart(config, githubClient, git.New("")).Current()
+	if err != nil {
+		fmt.Printf("error on current: %v\n", err)
+		return
+	}
+	fmt.Printf("branch %s\n", branch)
+	if err = issue.PrintIssues(os.Stdout, []domain.Issue{*current}); err != nil {
+		fmt.Printf("error on print issue: %v\n", err)
+	}
+}
+
+// runHooks works without a config file, as the hook runs on every commit and
+// must not get in the way.
+func runHooks(profile string, args []string) {
+	if len(args) < 1 {
+		fmt.Println(strHooksUsage)
+		return
+	}
+
+	config, err := application.LoadProfile(domain.ConfigFile, profile)
+	if errors.Is(err, os.ErrNotExist) {
+		config, err = &domain.Config{}, nil
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not load conf: %v\n", err)
+		return
+	}
+	hook := issue.NewHook(config, git.New(""))
+
+	switch args[0] {
+	case "install":
+		flags := flag.NewFlagSet("hooks install", flag.ContinueOnError)
+		force := flags.Bool("force", false, "replace a hook ghissues did not install")
+		if _, err = parseFlags(flags, args[1:]); err != nil {
+			return
+		}
+		executable, err := os.Executable()
+		if err != nil {
+			fmt.Printf("error on install hook: %v\n", err)
+			return
+		}
+		path, err := hook.Install(executable, *force)
+		if err != nil {
+			fmt.Printf("error on install hook: %v\n", err)
+			return
+		}
+		fmt.Printf("hook installed at %s\n", path)
+
+	case issue.HookName:
+		// run by git with the message file, and the source when there is one
+		if len(args) < 2 {
+			return
+		}
+		source := ""
+		if len(args) > 2 {
+			source = args[2]
+		}
+		if err = hook.PrepareCommitMsg(args[1], source); err != nil {
+			fmt.Fprintf(os.Stderr, "ghissues hook: %v\n", err)
+		}
+
+	default:
+		fmt.Println(strHooksUsage)
+	}
+}
+
+func runCommits(config *domain.Config, args []string) {
+	flags := flag.NewFlagSet("commits", flag.ContinueOnError)
+	all := flags.Bool("all", false, "scan every branch, not only the current one")
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return
+	}
+	number, ok := issueNumber(positional)
+	if !ok {
+		return
+	}
+
+	mentions, err := issue.NewCommits(config, git.New("")).Commits(number, *all)
+	if err != nil {
+		fmt.Printf("error on commits: %v\n", err)
+		return
+	}
+	if len(mentions) == 0 {
+		fmt.Printf("no commits reference #%d\n", number)
+		return
+	}
+	if err = issue.PrintMentions(os.Stdout, mentions); err != nil {
+		fmt.Printf("error on print commits: %v\n", err)
+	}
+}
+
+func runVerifyCloses(config *domain.Config, args []string) {
+	flags := flag.NewFlagSet("verify-closes", flag.ContinueOnError)
+	base := flags.String("base", "", "branch the current one is merged into, the default branch if empty")
+	if _, err := parseFlags(flags, args); err != nil {
+		return
+	}
+
+	branch, err := git.New("").CurrentBranch()
+	if err != nil {
+		fmt.Printf("error on verify-closes: %v\n", err)
+		return
+	}
+	into, closing, err := issue.NewCommits(config, git.New("")).VerifyCloses(*base)
+	if err != nil {
+		fmt.Printf("error on verify-closes: %v\n", err)
+		return
+	}
+	if len(closing) == 0 {
+		fmt.Printf("merging %s into %s closes no issues\n", branch, into)
+		return
+	}
+	fmt.Printf("merging %s into %s closes:\n", branch, into)
+	if err = issue.PrintMentions(os.Stdout, closing); err != nil {
+		fmt.Printf("error on print issues: %v\n", err)
+	}
+}
+
+func runChangelog(config *domain.Config, githubClient client.GitHubClient, args []string) {
+	flags := flag.NewFlagSet("changelog", flag.ContinueOnError)
+	since := flags.String("since", "", "tag or date (2006-01-02) the changelog starts after")
+	until := flags.String("until", "", "tag or date the changelog ends at, now if empty")
+	tmpl := flags.String("template", "", "text/template file to render instead of Markdown")
+	title := flags.String("title", "", "heading of the changelog")
+	if _, err := parseFlags(flags, args); err != nil {
+		return
+	}
+
+	changelog, err := issue.NewChangelog(config, githubClient, git.New("")).Changelog(*since, *until)
+	if err != nil {
+		fmt.Printf("error on changelog: %v\n", err)
+		return
+	}
+	if *title != "" {
+		changelog.Title = *title
+	}
+	if err = issue.PrintChangelog(os.Stdout, changelog, *tmpl); err != nil {
+		fmt.Printf("error on print changelog: %v\n", err)
+	}
+}
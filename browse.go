@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"git-issues/domain"
+	"git-issues/features/issue"
+	"git-issues/service/browser"
+)
+
+var strBrowseUsage = `usage:
+  ghissues browse [<n>] [--print-url]`
+
+func runBrowse(config *domain.Config, args []string) {
+	flags := flag.NewFlagSet("browse", flag.ContinueOnError)
+	printURL := flags.Bool("print-url", false, "print the url instead of opening it")
+	args, err := parseFlags(flags, args)
+	if err != nil {
+		return
+	}
+	if len(args) > 1 {
+		fmt.Println(strBrowseUsage)
+		return
+	}
+	if len(args) == 0 {
+		openWeb(config, *printURL, (*issue.BrowseFeature).IssuesURL)
+		return
+	}
+	number, ok := issueNumber(args)
+	if !ok {
+		return
+	}
+	openWeb(config, *printURL, func(b *issue.BrowseFeature) (string, error) {
+		return b.IssueURL(number)
+	})
+}
+
+// openWeb opens the page of the repository page returns in the browser, or
+// prints its url.
+func openWeb(config *domain.Config, printURL bool, page func(*issue.BrowseFeature) (string, error)) {
+	browse := issue.NewBrowse(config, browser.New())
+	browse.PrintURL = printURL
+	pageURL, err := page(browse)
+	if err == nil {
+		err = browse.Open(pageURL)
+	}
+	if err != nil {
+		fmt.Printf("error on browse: %v\n", err)
+	}
+}
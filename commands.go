@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"git-issues/application"
+	"git-issues/domain"
+	"git-issues/features/completion"
+	"git-issues/features/issue"
+	"git-issues/features/watch"
+	"git-issues/service/client"
+)
+
+// program is the name the completion scripts complete.
+const program = "ghissues"
+
+// completeCommand is the hidden command the completion scripts run to get
+// issues, labels and the other values that are not known in advance.
+const completeCommand = "__complete"
+
+var (
+	formatFlag = completion.Flag{Name: "format", Summary: "output format", Values: issue.Formats}
+	pagerFlag  = completion.Flag{Name: "no-pager", Summary: "print without the pager"}
+	sortFlag   = completion.Flag{Name: "sort", Summary: "order of the issues", Values: issue.ListSorts}
+	webFlag    = completion.Flag{Name: "web", Summary: "open in the browser"}
+	urlFlag    = completion.Flag{Name: "print-url", Summary: "print the url for the browser"}
+)
+
+// globalFlags apply to every command.
+var globalFlags = []completion.Flag{
+	{Name: "profile", Summary: "run with the values of a profile", Kind: completion.KindProfile},
+}
+
+// registry is every command the dispatcher knows, in the order of the help.
+// Aliases may start with them and may not take their names.
+var registry = []completion.Command{
+	{Name: "init", Summary: "configure the app"},
+	{Name: "auth", Summary: "log in, show or remove the token", Subcommands: []string{"login", "status", "logout"}, Flags: []completion.Flag{
+		{Name: "scopes", Summary: "scopes to ask for", Kind: completion.KindText},
+		{Name: "client-id", Summary: "OAuth app client id", Kind: completion.KindText},
+	}},
+	{Name: "config", Summary: "manage profiles", Subcommands: []string{"list", "use"}, Args: []completion.Kind{completion.KindProfile}},
+	{Name: "doctor", Summary: "check the setup", Flags: []completion.Flag{
+		{Name: "format", Summary: "output format", Values: []string{issue.FormatText, issue.FormatJSON}},
+	}},
+	{Name: "completion", Summary: "print the shell completion script", Args: []completion.Kind{completion.KindText}},
+	{Name: "alias", Summary: "manage command aliases", Subcommands: []string{"set", "list", "delete"}},
+	{Name: "query", Summary: "manage saved searches", Subcommands: []string{"save", "run", "list", "delete"}, Args: []completion.Kind{completion.KindQuery}, Flags: []completion.Flag{
+		formatFlag, sortFlag, pagerFlag,
+	}},
+	{Name: "create", Summary: "create an issue", Flags: []completion.Flag{
+		{Name: "project", Summary: "add the issue to a project", Kind: completion.KindText},
+		{Name: "no-duplicate-check", Summary: "do not look for similar issues"},
+		webFlag, urlFlag,
+		{Name: "title", Summary: "title of the form", Kind: completion.KindText},
+		{Name: "body", Summary: "body of the form", Kind: completion.KindText},
+		{Name: "label", Summary: "labels of the form", Kind: completion.KindLabel},
+		{Name: "template", Summary: "issue template of the form", Kind: completion.KindText},
+	}},
+	{Name: "list", Summary: "list the open issues", Flags: []completion.Flag{
+		formatFlag, sortFlag, pagerFlag,
+		{Name: "state", Summary: "issue state", Values: issue.ListStates},
+		{Name: "assignee", Summary: "issues assigned to a user", Kind: completion.KindAssignee},
+		{Name: "label", Summary: "issues with the labels", Kind: completion.KindLabel},
+		{Name: "repos", Summary: "owner/repo list to list together", Kind: completion.KindText},
+		{Name: "query", Summary: "list a saved query", Kind: completion.KindQuery},
+		webFlag, urlFlag,
+	}},
+	{Name: "search", Summary: "list the issues matching a search", Args: []completion.Kind{completion.KindText}, Flags: []completion.Flag{
+		formatFlag, sortFlag, pagerFlag,
+	}},
+	{Name: "view", Summary: "view an issue", Args: []completion.Kind{completion.KindIssue}, Flags: []completion.Flag{
+		{Name: "raw", Summary: "print the markdown unrendered"},
+		{Name: "tree", Summary: "print the sub-issues and task list"},
+		pagerFlag, webFlag, urlFlag,
+	}},
+	{Name: "browse", Summary: "open the issues or an issue in the browser", Args: []completion.Kind{completion.KindIssue}, Flags: []completion.Flag{
+		urlFlag,
+	}},
+	{Name: "history", Summary: "show the events of an issue", Args: []completion.Kind{completion.KindIssue}, Flags: []completion.Flag{
+		formatFlag, pagerFlag,
+	}},
+	{Name: "react", Summary: "react to an issue or comment", Args: []completion.Kind{completion.KindIssue, completion.KindText}, Flags: []completion.Flag{
+		{Name: "comment", Summary: "react to a comment", Kind: completion.KindText},
+	}},
+	{Name: "update", Summary: "update an issue", Args: []completion.Kind{completion.KindIssue}},
+	{Name: "close", Summary: "close an issue", Args: []completion.Kind{completion.KindIssue}},
+	{Name: "lock", Summary: "lock the conversation of an issue", Args: []completion.Kind{completion.KindIssue}, Flags: []completion.Flag{
+		{Name: "reason", Summary: "lock reason", Values: lockReasons()},
+	}},
+	{Name: "unlock", Summary: "unlock the conversation of an issue", Args: []completion.Kind{completion.KindIssue}},
+	{Name: "pin", Summary: "pin an issue", Args: []completion.Kind{completion.KindIssue}},
+	{Name: "unpin", Summary: "unpin an issue", Args: []completion.Kind{completion.KindIssue}},
+	{Name: "subissue", Summary: "add or remove a sub-issue", Subcommands: []string{"add", "remove"}, Args: []completion.Kind{completion.KindIssue, completion.KindIssue}},
+	{Name: "progress", Summary: "show the done sub-issues and tasks", Args: []completion.Kind{completion.KindIssue}},
+	{Name: "start", Summary: "start work on an issue", Args: []completion.Kind{completion.KindIssue}, Flags: []completion.Flag{
+		{Name: "in-progress", Summary: "add the in progress label"},
+	}},
+	{Name: "current", Summary: "show the issue of the branch"},
+	{Name: "hooks", Summary: "install the commit hook", Subcommands: []string{"install"}, Flags: []completion.Flag{
+		{Name: "force", Summary: "replace an existing hook"},
+	}},
+	{Name: "commits", Summary: "list the commits of an issue", Args: []completion.Kind{completion.KindIssue}, Flags: []completion.Flag{
+		{Name: "all", Summary: "search every branch"},
+	}},
+	{Name: "verify-closes", Summary: "list the issues the branch closes", Flags: []completion.Flag{
+		{Name: "base", Summary: "branch to merge into", Kind: completion.KindText},
+	}},
+	{Name: "changelog", Summary: "release notes of the closed issues", Flags: []completion.Flag{
+		{Name: "since", Summary: "tag or date to start after", Kind: completion.KindText},
+		{Name: "until", Summary: "tag or date to end at", Kind: completion.KindText},
+		{Name: "template", Summary: "text/template file", Kind: completion.KindFile},
+		{Name: "title", Summary: "heading of the changelog", Kind: completion.KindText},
+	}},
+	{Name: "transfer", Summary: "move issues to another repository", Args: []completion.Kind{completion.KindIssue}, Flags: []completion.Flag{
+		{Name: "query", Summary: "transfer the matches of a search or saved query", Kind: completion.KindQuery},
+		{Name: "yes", Summary: "do not ask before a bulk transfer"},
+	}},
+	{Name: "watch", Summary: "print issue changes as they happen", Flags: []completion.Flag{
+		{Name: "format", Summary: "output format", Values: watch.Formats},
+		{Name: "events", Summary: "changes to print", Values: watch.Kinds},
+		{Name: "label", Summary: "changes of issues with the labels", Kind: completion.KindLabel},
+		{Name: "assignee", Summary: "changes of issues assigned to a user", Kind: completion.KindAssignee},
+		{Name: "actor", Summary: "changes made by a user", Kind: completion.KindAssignee},
+		{Name: "interval", Summary: "least wait between polls", Kind: completion.KindText},
+		{Name: "cursor", Summary: "file keeping the position", Kind: completion.KindFile},
+	}},
+	{Name: "export", Summary: "write the issues to an archive", Flags: []completion.Flag{
+		{Name: "format", Summary: "archive format", Values: []string{exportJSON, exportMarkdown}},
+		{Name: "output", Summary: "file or directory to write", Kind: completion.KindFile},
+	}},
+	{Name: "import", Summary: "recreate the issues of an archive", Args: []completion.Kind{completion.KindFile}, Flags: []completion.Flag{
+		{Name: "label-map", Summary: "rename a label, old=new", Kind: completion.KindText},
+		{Name: "milestone-map", Summary: "rename a milestone, old=new", Kind: completion.KindText},
+		{Name: "mapping", Summary: "mapping file", Kind: completion.KindFile},
+		{Name: "dry-run", Summary: "print what would be imported"},
+	}},
+	{Name: "project", Summary: "manage Projects", Subcommands: []string{"list", "add", "set", "items"}, Args: []completion.Kind{completion.KindIssue}, Flags: []completion.Flag{
+		{Name: "project", Summary: "project title or number", Kind: completion.KindText},
+		{Name: "field", Summary: "Field=Value to set", Kind: completion.KindText},
+		{Name: "filter", Summary: "Field=Value to match", Kind: completion.KindText},
+	}},
+	{Name: "tui", Summary: "browse issues in a full-screen view"},
+	{Name: "help", Summary: "display help"},
+}
+
+// lockReasons are the reasons of lock with dashes, which need no quoting.
+func lockReasons() []string {
+	reasons := make([]string, len(issue.LockReasons))
+	for i, r := range issue.LockReasons {
+		reasons[i] = strings.ReplaceAll(r, " ", "-")
+	}
+	return reasons
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(registry)+1)
+	for _, c := range registry {
+		names = append(names, c.Name)
+	}
+	return append(names, completeCommand)
+}
+
+func isCommand(name string) bool {
+	for _, command := range commandNames() {
+		if command == name {
+			return true
+		}
+	}
+	return false
+}
+
+func runCompletion(args []string) {
+	if len(args) != 1 {
+		fmt.Println("usage:\n  ghissues completion bash|zsh|fish")
+		return
+	}
+	if err := completion.Write(os.Stdout, args[0], program, registry, globalFlags); err != nil {
+		fmt.Printf("error on completion: %v\n", err)
+	}
+}
+
+// runComplete prints the values of a kind for the completion scripts. Errors
+// go to stderr, which the scripts discard, as stdout lands in the command line.
+func runComplete(profile string, args []string) {
+	if len(args) != 1 {
+		return
+	}
+	config, err := application.LoadProfile(domain.ConfigFile, profile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	// stale candidates still print when the refresh fails or is slow, as it
+	// is not retried
+	githubClient := client.NewWithTimeout(config, completion.RequestTimeout)
+	candidates, err := completion.New(config, githubClient).Candidates(completion.Kind(args[0]))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	_ = completion.PrintCandidates(os.Stdout, candidates)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+
+	"git-issues/features/conf"
+)
+
+const strConfigUsage = `usage:
+  ghissues config list
+  ghissues config use <profile|default>`
+
+func runConfig(featureConfig *conf.Feature, args []string) {
+	if len(args) < 1 {
+		fmt.Println(strConfigUsage)
+		return
+	}
+
+	switch args[0] {
+	case "list":
+		names, current, err := featureConfig.Profiles()
+		if err != nil {
+			fmt.Printf("error on list profiles: %v\n", err)
+			return
+		}
+		if current == "" {
+			current = conf.DefaultProfile
+		}
+		for _, name := range append([]string{conf.DefaultProfile}, names...) {
+			mark := " "
+			if name == current {
+				mark = "*"
+			}
+			fmt.Printf("%s %s\n", mark, name)
+		}
+
+	case "use":
+		if len(args) < 2 {
+			fmt.Println(strConfigUsage)
+			return
+		}
+		if err := featureConfig.Use(args[1]); err != nil {
+			fmt.Printf("error on use profile: %v\n", err)
+			return
+		}
+		fmt.Printf("using profile %s\n", args[1])
+
+	default:
+		fmt.Println(strConfigUsage)
+	}
+}
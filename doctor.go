@@ -0,0 +1,26 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"git-issues/domain"
+	"git-issues/features/doctor"
+	"git-issues/features/issue"
+)
+
+// runDoctor works without a config file, that being one of the things it
+// reports.
+func runDoctor(profile string, args []string) {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	format := flags.String("format", issue.FormatText, "output format: text or json")
+	if _, err := parseFlags(flags, args); err != nil {
+		return
+	}
+
+	report := doctor.New(domain.ConfigFile, profile).Run()
+	if err := doctor.Print(os.Stdout, report, *format); err != nil {
+		fmt.Printf("error on doctor: %v\n", err)
+	}
+}
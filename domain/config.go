@@ -11,4 +11,87 @@ type Config struct {
 	Repo       string `json:"repo"`
 	Editor     string `json:"editor,omitempty"`
 	APIBaseURL string `json:"api_base_url,omitempty"`
+	Pager      string `json:"pager,omitempty"`
+	Host       string `json:"host,omitempty"`
+
+	// CACert, ClientCert and ClientKey are PEM files for hosts behind a
+	// private certificate authority or requiring client certificates.
+	CACert     string `json:"ca_cert,omitempty"`
+	ClientCert string `json:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty"`
+
+	// AppID, AppInstallationID and AppPrivateKey, the path of the PEM key,
+	// authenticate as a GitHub App installation instead of with Token.
+	AppID             int64  `json:"app_id,omitempty"`
+	AppInstallationID int64  `json:"app_installation_id,omitempty"`
+	AppPrivateKey     string `json:"app_private_key,omitempty"`
+
+	// OAuthClientID is the OAuth app auth login authorizes with.
+	OAuthClientID string `json:"oauth_client_id,omitempty"`
+
+	// BranchTemplate names the branch start creates, InProgressLabel is the
+	// label start --in-progress adds.
+	BranchTemplate  string `json:"branch_template,omitempty"`
+	InProgressLabel string `json:"in_progress_label,omitempty"`
+
+	// Changelog groups the issues of changelog by label.
+	Changelog *ChangelogConfig `json:"changelog,omitempty"`
+
+	// Hosts keeps the token and TLS files of each host by hostname, for the
+	// top level host and the host of the profile.
+	Hosts map[string]HostConfig `json:"hosts,omitempty"`
+
+	// Aliases are command lines run by name, with $1 to $9 and $@ taking the
+	// arguments. Queries are searches in the GitHub syntax saved by name.
+	// Both apply to every profile.
+	Aliases map[string]string `json:"aliases,omitempty"`
+	Queries map[string]string `json:"queries,omitempty"`
+
+	// Profiles are named sets of values that replace the ones above when
+	// selected with --profile or by CurrentProfile.
+	Profiles       map[string]Profile `json:"profiles,omitempty"`
+	CurrentProfile string             `json:"current_profile,omitempty"`
+}
+
+// Profile overrides the top level values it sets.
+type Profile struct {
+	Token      string `json:"token,omitempty"`
+	Owner      string `json:"owner,omitempty"`
+	Repo       string `json:"repo,omitempty"`
+	Editor     string `json:"editor,omitempty"`
+	APIBaseURL string `json:"api_base_url,omitempty"`
+	Pager      string `json:"pager,omitempty"`
+	Host       string `json:"host,omitempty"`
+
+	CACert     string `json:"ca_cert,omitempty"`
+	ClientCert string `json:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty"`
+
+	AppID             int64  `json:"app_id,omitempty"`
+	AppInstallationID int64  `json:"app_installation_id,omitempty"`
+	AppPrivateKey     string `json:"app_private_key,omitempty"`
+
+	OAuthClientID string `json:"oauth_client_id,omitempty"`
+}
+
+// HostConfig holds the settings of a single GitHub host.
+type HostConfig struct {
+	Token      string `json:"token,omitempty"`
+	CACert     string `json:"ca_cert,omitempty"`
+	ClientCert string `json:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty"`
+}
+
+// ChangelogConfig maps labels to the sections of the changelog. An issue
+// goes to the first section with one of its labels, or to Other.
+type ChangelogConfig struct {
+	Sections []ChangelogSection `json:"sections,omitempty"`
+	Other    string             `json:"other,omitempty"`
+	// Exclude leaves out the issues with any of these labels.
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+type ChangelogSection struct {
+	Title  string   `json:"title"`
+	Labels []string `json:"labels"`
 }
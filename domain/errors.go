@@ -8,4 +8,6 @@ var (
 	ErrApi           = errors.New("api error")
 	ErrCreateRequest = errors.New("create request error")
 	ErrEditor        = errors.New("editor error")
+	ErrGraphQL       = errors.New("graphql error")
+	ErrTLS           = errors.New("tls settings error")
 )
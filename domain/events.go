@@ -0,0 +1,30 @@
+package domain
+
+// Event is an item of the issue timeline. Only the fields of the event kind
+// are set: Label for labeled, Assignee for assigned, Rename for renamed,
+// CommitID for referenced and Source for cross-referenced events. Issue is
+// only set by the issue events of a whole repository.
+type Event struct {
+	ID          int64   `json:"id,omitempty"`
+	Event       string  `json:"event"`
+	Actor       *User   `json:"actor,omitempty"`
+	CreatedAt   string  `json:"created_at,omitempty"`
+	Label       *Label  `json:"label,omitempty"`
+	Assignee    *User   `json:"assignee,omitempty"`
+	Rename      *Rename `json:"rename,omitempty"`
+	CommitID    string  `json:"commit_id,omitempty"`
+	StateReason string  `json:"state_reason,omitempty"`
+	Source      *Source `json:"source,omitempty"`
+	Issue       *Issue  `json:"issue,omitempty"`
+}
+
+type Rename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Source is the issue or pull request that mentioned the issue.
+type Source struct {
+	Type  string `json:"type,omitempty"`
+	Issue *Issue `json:"issue,omitempty"`
+}
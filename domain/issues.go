@@ -1,8 +1,74 @@
 package domain
 
 type Issue struct {
-	Number int    `json:"number,omitempty"`
-	Title  string `json:"title"`
-	Body   string `json:"body,omitempty"`
-	State  string `json:"state,omitempty"`
+	Number           int        `json:"number,omitempty"`
+	NodeID           string     `json:"node_id,omitempty"`
+	Title            string     `json:"title"`
+	Body             string     `json:"body,omitempty"`
+	State            string     `json:"state,omitempty"`
+	Labels           []Label    `json:"labels,omitempty"`
+	Assignees        []User     `json:"assignees,omitempty"`
+	Locked           bool       `json:"locked,omitempty"`
+	ActiveLockReason string     `json:"active_lock_reason,omitempty"`
+	Pinned           bool       `json:"pinned,omitempty"`
+	HTMLURL          string     `json:"html_url,omitempty"`
+	Reactions        *Reactions `json:"reactions,omitempty"`
+	User             *User      `json:"user,omitempty"`
+	Milestone        *Milestone `json:"milestone,omitempty"`
+	CreatedAt        string     `json:"created_at,omitempty"`
+	UpdatedAt        string     `json:"updated_at,omitempty"`
+	ClosedAt         string     `json:"closed_at,omitempty"`
+	StateReason      string     `json:"state_reason,omitempty"`
+
+	// PullRequest is set when the issues endpoints return a pull request.
+	PullRequest *PullRequestLinks `json:"pull_request,omitempty"`
+
+	// Repo is the owner/repo of the issue in listings that span repositories.
+	Repo string `json:"-"`
+}
+
+// PullRequestLinks marks pull requests among issues.
+type PullRequestLinks struct {
+	HTMLURL string `json:"html_url,omitempty"`
+}
+
+type Label struct {
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type Milestone struct {
+	Number      int    `json:"number,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	State       string `json:"state,omitempty"`
+	DueOn       string `json:"due_on,omitempty"`
+}
+
+type User struct {
+	Login string `json:"login"`
+}
+
+type Comment struct {
+	ID        int64      `json:"id,omitempty"`
+	User      User       `json:"user"`
+	Body      string     `json:"body"`
+	CreatedAt string     `json:"created_at,omitempty"`
+	HTMLURL   string     `json:"html_url,omitempty"`
+	IssueURL  string     `json:"issue_url,omitempty"`
+	Reactions *Reactions `json:"reactions,omitempty"`
+}
+
+// Reactions is the reaction summary GitHub returns with issues and comments.
+type Reactions struct {
+	TotalCount int `json:"total_count"`
+	PlusOne    int `json:"+1"`
+	MinusOne   int `json:"-1"`
+	Laugh      int `json:"laugh"`
+	Hooray     int `json:"hooray"`
+	Confused   int `json:"confused"`
+	Heart      int `json:"heart"`
+	Rocket     int `json:"rocket"`
+	Eyes       int `json:"eyes"`
 }
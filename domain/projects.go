@@ -0,0 +1,49 @@
+package domain
+
+// Project field data types as reported by the Projects v2 api.
+const (
+	FieldSingleSelect = "SINGLE_SELECT"
+	FieldIteration    = "ITERATION"
+	FieldNumber       = "NUMBER"
+	FieldDate         = "DATE"
+	FieldText         = "TEXT"
+)
+
+type Project struct {
+	ID     string `json:"id"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url,omitempty"`
+	Closed bool   `json:"closed,omitempty"`
+}
+
+type ProjectField struct {
+	ID         string             `json:"id"`
+	Name       string             `json:"name"`
+	DataType   string             `json:"dataType"`
+	Options    []ProjectOption    `json:"options,omitempty"`
+	Iterations []ProjectIteration `json:"iterations,omitempty"`
+}
+
+type ProjectOption struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ProjectIteration struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	StartDate string `json:"startDate"`
+	Duration  int    `json:"duration"`
+}
+
+// ProjectItem is an issue, pull request or draft on a project board with its
+// custom field values rendered as text.
+type ProjectItem struct {
+	ID     string            `json:"id"`
+	Type   string            `json:"type"`
+	Number int               `json:"number,omitempty"`
+	Title  string            `json:"title"`
+	State  string            `json:"state,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
@@ -0,0 +1,277 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+	"git-issues/service/host"
+)
+
+const (
+	deviceCodePath  = "/login/device/code"
+	accessTokenPath = "/login/oauth/access_token"
+	deviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// ClientIDEnv names the variable that holds the client id of the OAuth
+	// app used by login when the config has none.
+	ClientIDEnv = "GHISSUES_OAUTH_CLIENT_ID"
+
+	defaultInterval = 5 * time.Second
+	slowDownStep    = 5 * time.Second
+)
+
+// DefaultScopes are the token scopes every command of the application needs.
+var DefaultScopes = []string{"repo", "project"}
+
+// impliedScopes lists the scopes a broader scope includes.
+var impliedScopes = map[string][]string{
+	"repo":    {"repo:status", "repo_deployment", "public_repo", "repo:invite", "security_events"},
+	"project": {"read:project"},
+	"user":    {"read:user", "user:email", "user:follow"},
+}
+
+var (
+	errClientID     = errors.New("no OAuth app client id: set oauth_client_id in the config, " + ClientIDEnv + " or pass --client-id")
+	errDeviceCode   = errors.New("could not request a device code")
+	errAccessToken  = errors.New("could not get the access token")
+	errExpiredToken = errors.New("the device code expired, run auth login again")
+	errAccessDenied = errors.New("the authorization was denied")
+	errStatus       = errors.New("could not read the login")
+	errWebHost      = errors.New("the api url has no known web login page")
+)
+
+// Auth logs in with the OAuth device flow and reports the current token.
+type Auth interface {
+	Login(scopes []string) (string, error)
+	Status() (*Status, error)
+}
+
+// Status is who the token belongs to and what it may do. Scopes is nil for
+// tokens GitHub does not report scopes for, like fine-grained tokens.
+type Status struct {
+	Host   string
+	Login  string
+	Scopes []string
+}
+
+type Feature struct {
+	config     *domain.Config
+	client     client.HeaderClient
+	httpClient *http.Client
+	// webURL is where the OAuth endpoints live, the web root of the host.
+	webURL   string
+	clientID string
+	tlsErr   error
+	out      io.Writer
+	sleep    func(time.Duration)
+	now      func() time.Time
+}
+
+type deviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	Scope            string `json:"scope"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+	Interval         int    `json:"interval"`
+}
+
+func New(config *domain.Config, githubClient client.HeaderClient) *Feature {
+	clientID := config.OAuthClientID
+	if clientID == "" {
+		clientID = os.Getenv(ClientIDEnv)
+	}
+	httpClient, err := client.NewHTTPClient(config)
+	httpClient.Timeout = 30 * time.Second
+	return &Feature{
+		config:     config,
+		client:     githubClient,
+		httpClient: httpClient,
+		tlsErr:     err,
+		webURL:     host.FromAPIBaseURL(config.APIBaseURL).Web,
+		clientID:   clientID,
+		out:        os.Stdout,
+		sleep:      time.Sleep,
+		now:        time.Now,
+	}
+}
+
+// SetClientID overrides the OAuth app of the config, for --client-id.
+func (f *Feature) SetClientID(clientID string) {
+	if clientID != "" {
+		f.clientID = clientID
+	}
+}
+
+// Login runs the device flow: it shows the user code, waits while the user
+// authorizes it in the browser and returns the token.
+func (f *Feature) Login(scopes []string) (string, error) {
+	if f.clientID == "" {
+		return "", errClientID
+	}
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+
+	var code deviceCode
+	form := url.Values{"client_id": {f.clientID}, "scope": {strings.Join(scopes, " ")}}
+	if err := f.postForm(deviceCodePath, form, &code); err != nil {
+		return "", errors.Join(errDeviceCode, err)
+	}
+	if code.DeviceCode == "" {
+		return "", errDeviceCode
+	}
+
+	fmt.Fprintf(f.out, "First copy your one-time code: %s\nThen open %s in a browser and enter it.\nWaiting for authorization...\n",
+		code.UserCode, code.VerificationURI)
+	return f.poll(code)
+}
+
+// poll asks for the token every interval until the user answers or the code
+// expires. slow_down makes the interval longer for the rest of the flow.
+func (f *Feature) poll(code deviceCode) (string, error) {
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	var deadline time.Time
+	if code.ExpiresIn > 0 {
+		deadline = f.now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	}
+
+	form := url.Values{"client_id": {f.clientID}, "device_code": {code.DeviceCode}, "grant_type": {deviceGrantType}}
+	for {
+		if !deadline.IsZero() && f.now().After(deadline) {
+			return "", errExpiredToken
+		}
+		f.sleep(interval)
+
+		var token tokenResponse
+		if err := f.postForm(accessTokenPath, form, &token); err != nil {
+			return "", errors.Join(errAccessToken, err)
+		}
+
+		switch token.Error {
+		case "":
+			if token.AccessToken == "" {
+				return "", errAccessToken
+			}
+			return token.AccessToken, nil
+		case "authorization_pending":
+		case "slow_down":
+			if token.Interval > 0 {
+				interval = time.Duration(token.Interval) * time.Second
+			} else {
+				interval += slowDownStep
+			}
+		case "expired_token":
+			return "", errExpiredToken
+		case "access_denied":
+			return "", errAccessDenied
+		default:
+			return "", errors.Join(errAccessToken, fmt.Errorf("%s: %s", token.Error, token.ErrorDescription))
+		}
+	}
+}
+
+// Status reads the login of the token from /user and its scopes from the
+// X-OAuth-Scopes header.
+func (f *Feature) Status() (*Status, error) {
+	body, header, err := f.client.MakeRequestHeader("GET", strings.TrimSuffix(f.config.APIBaseURL, "/")+"/user")
+	if err != nil {
+		return nil, errors.Join(errStatus, err)
+	}
+
+	var user domain.User
+	if err = json.Unmarshal(body, &user); err != nil || user.Login == "" {
+		return nil, errStatus
+	}
+
+	status := &Status{Host: host.FromAPIBaseURL(f.config.APIBaseURL).Name, Login: user.Login}
+	if _, ok := header["X-Oauth-Scopes"]; ok {
+		status.Scopes = ParseScopes(header.Get("X-OAuth-Scopes"))
+	}
+	return status, nil
+}
+
+// ParseScopes splits the comma separated scopes of the X-OAuth-Scopes header.
+func ParseScopes(header string) []string {
+	scopes := []string{}
+	for _, scope := range strings.Split(header, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// MissingScopes returns the scopes of want that have does not grant, directly
+// or through a broader scope.
+func MissingScopes(have, want []string) []string {
+	granted := map[string]bool{}
+	for _, scope := range have {
+		granted[scope] = true
+		for _, implied := range impliedScopes[scope] {
+			granted[implied] = true
+		}
+	}
+
+	var missing []string
+	for _, scope := range want {
+		if !granted[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+func (f *Feature) postForm(path string, form url.Values, result interface{}) error {
+	if f.tlsErr != nil {
+		return f.tlsErr
+	}
+	if f.webURL == "" {
+		return fmt.Errorf("%w: %q", errWebHost, f.config.APIBaseURL)
+	}
+	req, err := http.NewRequest("POST", strings.TrimSuffix(f.webURL, "/")+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return errors.Join(domain.ErrCreateRequest, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return errors.Join(domain.ErrRequest, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Join(domain.ErrRequest, err)
+	}
+	if resp.StatusCode >= 400 {
+		return errors.Join(domain.ErrApi, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body))))
+	}
+	if err = json.Unmarshal(body, result); err != nil {
+		return errors.Join(domain.ErrEncoding, err)
+	}
+	return nil
+}
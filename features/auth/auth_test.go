@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+// fakeAuthServer answers the device code request and then the token polls
+// with the given responses in order.
+func fakeAuthServer(t *testing.T, polls []string) (*httptest.Server, *[]string) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("invalid form: %v", err)
+		}
+		if r.Header.Get("Accept") != "application/json" || r.Form.Get("client_id") != "client" {
+			t.Errorf("unexpected request %v %v", r.Header, r.Form)
+		}
+		requests = append(requests, r.URL.Path)
+
+		switch r.URL.Path {
+		case deviceCodePath:
+			if r.Form.Get("scope") != "repo project" {
+				t.Errorf("unexpected scope %q", r.Form.Get("scope"))
+			}
+			w.Write([]byte(`{"device_code":"dev","user_code":"ABCD-1234","verification_uri":"https://github.com/login/device","expires_in":900,"interval":5}`))
+		case accessTokenPath:
+			if r.Form.Get("device_code") != "dev" || r.Form.Get("grant_type") != deviceGrantType {
+				t.Errorf("unexpected poll %v", r.Form)
+			}
+			if len(polls) == 0 {
+				t.Error("too many polls")
+				http.Error(w, "", http.StatusGone)
+				return
+			}
+			w.Write([]byte(polls[0]))
+			polls = polls[1:]
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return server, &requests
+}
+
+func TestLogin(t *testing.T) {
+	tests := []struct {
+		name          string
+		polls         []string
+		want          string
+		wantErr       error
+		wantIntervals []time.Duration
+	}{
+		{
+			name: "pending, slow down and success",
+			polls: []string{
+				`{"error":"authorization_pending"}`,
+				`{"error":"slow_down"}`,
+				`{"error":"slow_down","interval":20}`,
+				`{"access_token":"gho_token","scope":"repo,project"}`,
+			},
+			want:          "gho_token",
+			wantIntervals: []time.Duration{5 * time.Second, 5 * time.Second, 10 * time.Second, 20 * time.Second},
+		},
+		{
+			name:          "expired code",
+			polls:         []string{`{"error":"expired_token"}`},
+			wantErr:       errExpiredToken,
+			wantIntervals: []time.Duration{5 * time.Second},
+		},
+		{
+			name:          "denied",
+			polls:         []string{`{"error":"access_denied"}`},
+			wantErr:       errAccessDenied,
+			wantIntervals: []time.Duration{5 * time.Second},
+		},
+		{
+			name:          "unknown error",
+			polls:         []string{`{"error":"incorrect_client_credentials"}`},
+			wantErr:       errAccessToken,
+			wantIntervals: []time.Duration{5 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := fakeAuthServer(t, tt.polls)
+			defer server.Close()
+
+			var out bytes.Buffer
+			var intervals []time.Duration
+			f := New(&domain.Config{OAuthClientID: "client"}, &stubs.ClientStub{})
+			f.webURL = server.URL
+			f.out = &out
+			f.sleep = func(d time.Duration) { intervals = append(intervals, d) }
+
+			got, err := f.Login(nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("token = %q want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(intervals, tt.wantIntervals) {
+				t.Errorf("intervals = %v want %v", intervals, tt.wantIntervals)
+			}
+			if !bytes.Contains(out.Bytes(), []byte("ABCD-1234")) || !bytes.Contains(out.Bytes(), []byte("https://github.com/login/device")) {
+				t.Errorf("the user code and url should be shown, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestLoginDeadline(t *testing.T) {
+	polls := make([]string, 10)
+	for i := range polls {
+		polls[i] = `{"error":"authorization_pending"}`
+	}
+	server, _ := fakeAuthServer(t, polls)
+	defer server.Close()
+
+	now := time.Unix(0, 0)
+	f := New(&domain.Config{OAuthClientID: "client"}, &stubs.ClientStub{})
+	f.webURL = server.URL
+	f.out = &bytes.Buffer{}
+	f.now = func() time.Time { return now }
+	f.sleep = func(d time.Duration) { now = now.Add(5 * time.Minute) }
+
+	if _, err := f.Login(nil); !errors.Is(err, errExpiredToken) {
+		t.Fatalf("expected errExpiredToken, got %v", err)
+	}
+}
+
+func TestLoginErrors(t *testing.T) {
+	t.Setenv(ClientIDEnv, "")
+	f := New(&domain.Config{}, &stubs.ClientStub{})
+	if _, err := f.Login(nil); !errors.Is(err, errClientID) {
+		t.Errorf("expected errClientID, got %v", err)
+	}
+
+	t.Setenv(ClientIDEnv, "client")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"Not Found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+	f = New(&domain.Config{}, &stubs.ClientStub{})
+	f.webURL = server.URL
+	f.out = &bytes.Buffer{}
+	if _, err := f.Login(nil); !errors.Is(err, errDeviceCode) || !errors.Is(err, domain.ErrApi) {
+		t.Errorf("expected errDeviceCode, got %v", err)
+	}
+
+	// the web root of an unknown host is not guessed from its api url
+	f = New(&domain.Config{APIBaseURL: "http://localhost:8080"}, &stubs.ClientStub{})
+	f.out = &bytes.Buffer{}
+	if _, err := f.Login(nil); !errors.Is(err, errDeviceCode) || !errors.Is(err, errWebHost) {
+		t.Errorf("expected errWebHost, got %v", err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	cfg := &domain.Config{APIBaseURL: "https://ghe.example.com/api/v3"}
+
+	tests := []struct {
+		name    string
+		body    string
+		header  http.Header
+		err     error
+		want    *Status
+		wantErr error
+	}{
+		{
+			name:   "classic token",
+			body:   `{"login":"octocat"}`,
+			header: http.Header{"X-Oauth-Scopes": {"repo, read:org,project"}},
+			want:   &Status{Host: "ghe.example.com", Login: "octocat", Scopes: []string{"project", "read:org", "repo"}},
+		},
+		{
+			name:   "no scopes",
+			body:   `{"login":"octocat"}`,
+			header: http.Header{"X-Oauth-Scopes": {""}},
+			want:   &Status{Host: "ghe.example.com", Login: "octocat", Scopes: []string{}},
+		},
+		{
+			name:   "fine-grained token",
+			body:   `{"login":"octocat"}`,
+			header: http.Header{},
+			want:   &Status{Host: "ghe.example.com", Login: "octocat"},
+		},
+		{name: "bad credentials", err: domain.ErrApi, wantErr: errStatus},
+		{name: "invalid body", body: `{}`, wantErr: errStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New(cfg, &stubs.ClientStub{
+				MakeRequestHeaderFunc: func(method, url string) ([]byte, http.Header, error) {
+					if method != "GET" || url != "https://ghe.example.com/api/v3/user" {
+						t.Errorf("unexpected request %s %s", method, url)
+					}
+					return []byte(tt.body), tt.header, tt.err
+				},
+			})
+			got, err := f.Status()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMissingScopes(t *testing.T) {
+	tests := []struct {
+		have []string
+		want []string
+		miss []string
+	}{
+		{have: []string{"repo", "project"}, want: DefaultScopes},
+		{have: []string{"public_repo"}, want: DefaultScopes, miss: []string{"repo", "project"}},
+		{have: []string{"repo", "read:project"}, want: []string{"public_repo", "read:project", "project"}, miss: []string{"project"}},
+		{have: nil, want: nil},
+	}
+	for _, tt := range tests {
+		if got := MissingScopes(tt.have, tt.want); !reflect.DeepEqual(got, tt.miss) {
+			t.Errorf("MissingScopes(%v, %v) = %v want %v", tt.have, tt.want, got, tt.miss)
+		}
+	}
+}
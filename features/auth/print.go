@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	strStatusFormat  = "Logged in to %s as %s\n"
+	strScopesFormat  = "Token scopes: %s\n"
+	strMissingFormat = "Missing scopes: %s, run 'ghissues auth login' or update the token\n"
+)
+
+// PrintStatus prints the login and scopes of the token, and the scopes the
+// application needs that the token lacks.
+func PrintStatus(w io.Writer, status *Status) error {
+	_, err := fmt.Fprintf(w, strStatusFormat, status.Host, status.Login)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case status.Scopes == nil:
+		_, err = fmt.Fprintf(w, strScopesFormat, "not reported (fine-grained or app token)")
+		return err
+	case len(status.Scopes) == 0:
+		_, err = fmt.Fprintf(w, strScopesFormat, "none")
+	default:
+		_, err = fmt.Fprintf(w, strScopesFormat, strings.Join(status.Scopes, ", "))
+	}
+	if err != nil {
+		return err
+	}
+
+	if missing := MissingScopes(status.Scopes, DefaultScopes); len(missing) > 0 {
+		_, err = fmt.Fprintf(w, strMissingFormat, strings.Join(missing, ", "))
+	}
+	return err
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrintStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *Status
+		want   string
+	}{
+		{
+			name:   "all scopes",
+			status: &Status{Host: "github.com", Login: "octocat", Scopes: []string{"project", "repo"}},
+			want:   "Logged in to github.com as octocat\nToken scopes: project, repo\n",
+		},
+		{
+			name:   "missing scopes",
+			status: &Status{Host: "github.com", Login: "octocat", Scopes: []string{"public_repo"}},
+			want:   "Logged in to github.com as octocat\nToken scopes: public_repo\nMissing scopes: repo, project, run 'ghissues auth login' or update the token\n",
+		},
+		{
+			name:   "no scopes",
+			status: &Status{Host: "github.com", Login: "octocat", Scopes: []string{}},
+			want:   "Logged in to github.com as octocat\nToken scopes: none\nMissing scopes: repo, project, run 'ghissues auth login' or update the token\n",
+		},
+		{
+			name:   "not reported",
+			status: &Status{Host: "github.com", Login: "octocat"},
+			want:   "Logged in to github.com as octocat\nToken scopes: not reported (fine-grained or app token)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := PrintStatus(&buf, tt.status); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got %q want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package completion
+
+import (
+	"fmt"
+	"strings"
+)
+
+func bash(program string, commands []Command, global []Flag) string {
+	fn := functionName(program)
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s, generated by `%s completion bash`.\n", program, program)
+	fmt.Fprintf(&b, "# Load it with: source <(%s completion bash)\n\n", program)
+
+	fmt.Fprintf(&b, "%s_takes_value() {\n    case \"$1 $2\" in\n", fn)
+	fmt.Fprintf(&b, "        %s) return 0 ;;\n    esac\n    return 1\n}\n\n", casePatterns(valueFlags(commands, global)))
+
+	fmt.Fprintf(&b, "%s_dynamic() {\n    local IFS=$'\\n'\n", fn)
+	fmt.Fprintf(&b, "    COMPREPLY=($(compgen -W \"$(%s __complete \"$1\" 2>/dev/null | cut -f1)\" -- \"$cur\"))\n}\n\n", program)
+
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString(`    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+    local cmd="" word i positional=0
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        word="${COMP_WORDS[i]}"
+        if [[ "$word" == -* ]]; then
+`)
+	fmt.Fprintf(&b, "            %s_takes_value \"$cmd\" \"$word\" && ((i++))\n", fn)
+	b.WriteString(`            continue
+        fi
+        if [[ -z "$cmd" ]]; then
+            cmd="$word"
+        else
+            ((positional++))
+        fi
+    done
+
+    case "$cmd $prev" in
+`)
+	for _, f := range global {
+		if f.TakesValue() {
+			fmt.Fprintf(&b, "        *\" --%s\") %s; return ;;\n", f.Name, bashValues(fn, f))
+		}
+	}
+	for _, c := range commands {
+		for _, f := range c.Flags {
+			if f.TakesValue() {
+				fmt.Fprintf(&b, "        \"%s --%s\") %s; return ;;\n", c.Name, f.Name, bashValues(fn, f))
+			}
+		}
+	}
+	b.WriteString("    esac\n\n")
+
+	fmt.Fprintf(&b, "    if [[ -z \"$cmd\" ]]; then\n        if [[ \"$cur\" == -* ]]; then\n")
+	fmt.Fprintf(&b, "            COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n        else\n", flagNames(global))
+	fmt.Fprintf(&b, "            COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n        fi\n        return\n    fi\n\n", names(commands))
+
+	b.WriteString("    if [[ \"$cur\" == -* ]]; then\n        case \"$cmd\" in\n")
+	for _, c := range commands {
+		if len(c.Flags) > 0 {
+			fmt.Fprintf(&b, "            %s) COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")) ;;\n", c.Name, flagNames(append(c.Flags, global...)))
+		}
+	}
+	b.WriteString("        esac\n        return\n    fi\n\n    case \"$cmd\" in\n")
+	for _, c := range commands {
+		positions := arguments(c)
+		if len(positions) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "        %s)\n            case \"$positional\" in\n", c.Name)
+		for _, p := range positions {
+			fmt.Fprintf(&b, "                %d) %s ;;\n", p.position, bashValues(fn, Flag{Values: p.values, Kind: p.kind}))
+		}
+		b.WriteString("            esac ;;\n")
+	}
+	b.WriteString("    esac\n}\n\n")
+	fmt.Fprintf(&b, "complete -o default -F %s %s\n", fn, program)
+	return b.String()
+}
+
+// bashValues is the command filling COMPREPLY with the values of f. Files
+// and text are left to the default completion.
+func bashValues(fn string, f Flag) string {
+	switch {
+	case len(f.Values) > 0:
+		return fmt.Sprintf("COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))", strings.Join(f.Values, " "))
+	case isDynamic(f.Kind):
+		return fmt.Sprintf("%s_dynamic %s", fn, f.Kind)
+	}
+	return ":"
+}
+
+// casePatterns joins "command --flag" pairs into the patterns of a case,
+// "* --flag" matching any command.
+func casePatterns(pairs []string) string {
+	patterns := make([]string, len(pairs))
+	for i, pair := range pairs {
+		if rest, ok := strings.CutPrefix(pair, "* "); ok {
+			patterns[i] = `*" ` + rest + `"`
+		} else {
+			patterns[i] = `"` + pair + `"`
+		}
+	}
+	return strings.Join(patterns, "|")
+}
+
+// argument is what completes at a position after the command.
+type argument struct {
+	position int
+	values   []string
+	kind     Kind
+}
+
+// arguments lists the subcommands at position 0, when the command has them,
+// and the Args after them.
+func arguments(c Command) []argument {
+	var list []argument
+	offset := 0
+	if len(c.Subcommands) > 0 {
+		list = append(list, argument{position: 0, values: c.Subcommands})
+		offset = 1
+	}
+	for i, kind := range c.Args {
+		if kind != KindText {
+			list = append(list, argument{position: i + offset, kind: kind})
+		}
+	}
+	return list
+}
+
+func isDynamic(kind Kind) bool {
+	for _, k := range Dynamic {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
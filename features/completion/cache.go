@@ -0,0 +1,202 @@
+package completion
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+	"git-issues/service/host"
+)
+
+// DefaultTTL is how long fetched values are completed without asking the api
+// again.
+const DefaultTTL = 5 * time.Minute
+
+// RequestTimeout bounds a refresh, as the shell waits on it; past it the
+// stale values are completed.
+const RequestTimeout = 2 * time.Second
+
+// retryBackoff is how long the values of a failed refresh are completed
+// before the api is asked again, so that offline every completion does not
+// wait for the timeout.
+const retryBackoff = time.Minute
+
+// Candidate is a value to complete with a description to show next to it.
+type Candidate struct {
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// cacheFile is the content of a cache file, one per repository and kind.
+// RetryAt is set by a failed refresh.
+type cacheFile struct {
+	Repository string      `json:"repository"`
+	FetchedAt  time.Time   `json:"fetched_at"`
+	RetryAt    time.Time   `json:"retry_at,omitempty"`
+	Candidates []Candidate `json:"candidates"`
+}
+
+// Cache completes issues, labels and assignees from files in dir younger
+// than ttl, and the queries and profiles from the config.
+type Cache struct {
+	config    *domain.Config
+	client    client.GitHubClient
+	dir       string
+	ttl       time.Duration
+	now       func() time.Time
+	readFile  func(name string) ([]byte, error)
+	writeFile func(name string, data []byte, perm os.FileMode) error
+}
+
+// New keeps the cache in the ghissues directory of the user cache dir.
+func New(config *domain.Config, client client.GitHubClient) *Cache {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return &Cache{
+		config:    config,
+		client:    client,
+		dir:       filepath.Join(dir, "ghissues"),
+		ttl:       DefaultTTL,
+		now:       time.Now,
+		readFile:  os.ReadFile,
+		writeFile: os.WriteFile,
+	}
+}
+
+// Candidates returns the values of kind. Values older than the ttl are
+// fetched again; when that fails the old ones are returned with the error,
+// and answer alone until retryBackoff passed.
+func (c *Cache) Candidates(kind Kind) ([]Candidate, error) {
+	switch kind {
+	case KindQuery:
+		return fromMap(c.config.Queries), nil
+	case KindProfile:
+		profiles := make(map[string]string, len(c.config.Profiles))
+		for name, p := range c.config.Profiles {
+			profiles[name] = strings.Trim(p.Owner+"/"+p.Repo, "/")
+		}
+		return fromMap(profiles), nil
+	case KindIssue, KindLabel, KindAssignee:
+	default:
+		return nil, fmt.Errorf("%w: %q", errKind, kind)
+	}
+
+	repository := c.config.Owner + "/" + c.config.Repo
+	file := c.path(kind)
+	var cached cacheFile
+	if data, err := c.readFile(file); err == nil && json.Unmarshal(data, &cached) == nil && cached.Repository == repository {
+		if c.now().Sub(cached.FetchedAt) < c.ttl || c.now().Before(cached.RetryAt) {
+			return cached.Candidates, nil
+		}
+	} else {
+		cached = cacheFile{Repository: repository}
+	}
+
+	candidates, err := c.fetch(kind)
+	if err != nil {
+		cached.RetryAt = c.now().Add(retryBackoff)
+		// the error of the refresh matters more than the one of the save
+		_ = c.save(file, cached)
+		return cached.Candidates, err
+	}
+	return candidates, c.save(file, cacheFile{Repository: repository, FetchedAt: c.now(), Candidates: candidates})
+}
+
+func (c *Cache) save(file string, cached cacheFile) error {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+	return c.writeFile(file, data, 0600)
+}
+
+// path names the file of kind after the host and repository.
+func (c *Cache) path(kind Kind) string {
+	name := host.FileName(host.FromAPIBaseURL(c.config.APIBaseURL).Name, c.config.Owner, c.config.Repo, string(kind))
+	return filepath.Join(c.dir, name+".json")
+}
+
+func (c *Cache) fetch(kind Kind) ([]Candidate, error) {
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", c.config.APIBaseURL, c.config.Owner, c.config.Repo)
+	var candidates []Candidate
+	switch kind {
+	case KindIssue:
+		var issues []domain.Issue
+		if err := c.get(repoURL+"/issues?state=open&per_page=100", &issues); err != nil {
+			return nil, err
+		}
+		for _, i := range issues {
+			if i.PullRequest == nil {
+				candidates = append(candidates, Candidate{Value: strconv.Itoa(i.Number), Description: i.Title})
+			}
+		}
+	case KindLabel:
+		var labels []domain.Label
+		if err := c.get(repoURL+"/labels?per_page=100", &labels); err != nil {
+			return nil, err
+		}
+		for _, l := range labels {
+			candidates = append(candidates, Candidate{Value: l.Name, Description: l.Description})
+		}
+	case KindAssignee:
+		var users []domain.User
+		if err := c.get(repoURL+"/assignees?per_page=100", &users); err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			candidates = append(candidates, Candidate{Value: u.Login})
+		}
+	}
+	return candidates, nil
+}
+
+func (c *Cache) get(url string, v interface{}) error {
+	response, err := c.client.MakeRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(response, v); err != nil {
+		return errors.Join(domain.ErrEncoding, err)
+	}
+	return nil
+}
+
+func fromMap(m map[string]string) []Candidate {
+	candidates := make([]Candidate, 0, len(m))
+	for value, description := range m {
+		candidates = append(candidates, Candidate{Value: value, Description: description})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Value < candidates[j].Value
+	})
+	return candidates
+}
+
+// PrintCandidates writes a candidate per line, the value and its description
+// separated by a tab, the format the completion scripts read.
+func PrintCandidates(w io.Writer, candidates []Candidate) error {
+	for _, c := range candidates {
+		line := c.Value
+		if description := strings.Join(strings.Fields(c.Description), " "); description != "" {
+			line += "\t" + description
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package completion
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+func TestCandidates(t *testing.T) {
+	config := &domain.Config{
+		APIBaseURL: "https://api.github.com",
+		Owner:      "owner",
+		Repo:       "repo",
+		Queries:    map[string]string{"mine": "assignee:@me", "bugs": "label:bug"},
+		Profiles:   map[string]domain.Profile{"work": {Owner: "acme", Repo: "api"}},
+	}
+	var requests []string
+	fail := false
+	stub := &stubs.ClientStub{
+		MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+			requests = append(requests, url)
+			if fail {
+				return nil, domain.ErrApi
+			}
+			switch url {
+			case "https://api.github.com/repos/owner/repo/issues?state=open&per_page=100":
+				return []byte(`[{"number":12,"title":"Crash on start"},{"number":9,"title":"A pull","pull_request":{}}]`), nil
+			case "https://api.github.com/repos/owner/repo/labels?per_page=100":
+				return []byte(`[{"name":"bug","description":"Something\nbroken"}]`), nil
+			case "https://api.github.com/repos/owner/repo/assignees?per_page=100":
+				return []byte(`[{"login":"mona"}]`), nil
+			}
+			return nil, domain.ErrApi
+		},
+	}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	c := New(config, stub)
+	c.dir = t.TempDir()
+	c.now = func() time.Time { return now }
+
+	tests := []struct {
+		kind Kind
+		want []Candidate
+	}{
+		{kind: KindIssue, want: []Candidate{{Value: "12", Description: "Crash on start"}}},
+		{kind: KindLabel, want: []Candidate{{Value: "bug", Description: "Something\nbroken"}}},
+		{kind: KindAssignee, want: []Candidate{{Value: "mona"}}},
+		{kind: KindQuery, want: []Candidate{{Value: "bugs", Description: "label:bug"}, {Value: "mine", Description: "assignee:@me"}}},
+		{kind: KindProfile, want: []Candidate{{Value: "work", Description: "acme/api"}}},
+	}
+	for _, tt := range tests {
+		got, err := c.Candidates(tt.kind)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.kind, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v want %+v", tt.kind, got, tt.want)
+		}
+	}
+	if len(requests) != 3 {
+		t.Fatalf("got %d requests want 3", len(requests))
+	}
+
+	// within the ttl the cache answers
+	now = now.Add(DefaultTTL - time.Second)
+	if got, err := c.Candidates(KindIssue); err != nil || len(got) != 1 || len(requests) != 3 {
+		t.Errorf("got %+v, %v after %d requests, want the cached issue", got, err, len(requests))
+	}
+
+	// after it the api is asked again, and its failure keeps the old values
+	now = now.Add(2 * time.Second)
+	fail = true
+	got, err := c.Candidates(KindIssue)
+	if !errors.Is(err, domain.ErrApi) || len(got) != 1 || len(requests) != 4 {
+		t.Errorf("got %+v, %v after %d requests, want the stale issue and the error", got, err, len(requests))
+	}
+
+	// another repository does not read the cache of this one
+	config.Repo = "other"
+	fail = false
+	if _, err = c.Candidates(KindLabel); err == nil || len(requests) != 5 {
+		t.Errorf("got %v after %d requests, want a request for the other repository", err, len(requests))
+	}
+
+	if _, err = c.Candidates("milestone"); !errors.Is(err, errKind) {
+		t.Errorf("got %v want errKind", err)
+	}
+}
+
+func TestCandidatesBackoff(t *testing.T) {
+	config := &domain.Config{APIBaseURL: "https://api.github.com", Owner: "owner", Repo: "repo"}
+	requests := 0
+	stub := &stubs.ClientStub{
+		MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+			requests++
+			return nil, domain.ErrRequest
+		},
+	}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	files := map[string][]byte{}
+	c := New(config, stub)
+	c.dir = t.TempDir()
+	c.now = func() time.Time { return now }
+	c.readFile = func(name string) ([]byte, error) {
+		data, ok := files[name]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return data, nil
+	}
+	c.writeFile = func(name string, data []byte, perm os.FileMode) error {
+		files[name] = data
+		return nil
+	}
+	stale, _ := json.Marshal(cacheFile{Repository: "owner/repo", FetchedAt: now.Add(-time.Hour), Candidates: []Candidate{{Value: "bug"}}})
+	files[c.path(KindLabel)] = stale
+
+	// the failed refresh still completes the stale values
+	got, err := c.Candidates(KindLabel)
+	if !errors.Is(err, domain.ErrRequest) || len(got) != 1 || requests != 1 {
+		t.Fatalf("got %+v, %v after %d requests, want the stale label and the error", got, err, requests)
+	}
+
+	// which answer alone until the backoff passed
+	now = now.Add(retryBackoff - time.Second)
+	if got, err = c.Candidates(KindLabel); err != nil || len(got) != 1 || requests != 1 {
+		t.Errorf("got %+v, %v after %d requests, want the stale label without a request", got, err, requests)
+	}
+	now = now.Add(2 * time.Second)
+	if got, err = c.Candidates(KindLabel); err == nil || len(got) != 1 || requests != 2 {
+		t.Errorf("got %+v, %v after %d requests, want a new request", got, err, requests)
+	}
+
+	// without any values the failure is not asked again at once either
+	if _, err = c.Candidates(KindAssignee); err == nil || requests != 3 {
+		t.Errorf("got %v after %d requests, want the error", err, requests)
+	}
+	if _, err = c.Candidates(KindAssignee); err != nil || requests != 3 {
+		t.Errorf("got %v after %d requests, want no request", err, requests)
+	}
+}
+
+func TestPrintCandidates(t *testing.T) {
+	var b bytes.Buffer
+	err := PrintCandidates(&b, []Candidate{{Value: "12", Description: "Crash\n on  start"}, {Value: "mona"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "12\tCrash on start\nmona\n"; b.String() != want {
+		t.Errorf("got %q want %q", b.String(), want)
+	}
+}
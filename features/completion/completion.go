@@ -0,0 +1,127 @@
+// Package completion writes the shell completion scripts of the commands and
+// looks up the values they complete from the api, through a short lived
+// cache so that completing stays fast.
+package completion
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Kind is what a flag value or an argument is, deciding how it completes.
+type Kind string
+
+const (
+	// KindText takes any value and completes nothing.
+	KindText Kind = "text"
+	// KindFile completes file and directory names.
+	KindFile Kind = "file"
+
+	// The kinds below are asked to the program with __complete <kind>.
+	KindIssue    Kind = "issue"
+	KindLabel    Kind = "label"
+	KindAssignee Kind = "assignee"
+	KindQuery    Kind = "query"
+	KindProfile  Kind = "profile"
+)
+
+// Dynamic are the kinds completed by running the program.
+var Dynamic = []Kind{KindIssue, KindLabel, KindAssignee, KindQuery, KindProfile}
+
+// Shells are the shells Write has scripts for.
+var Shells = []string{"bash", "zsh", "fish"}
+
+var (
+	errShell = errors.New("unknown shell, use bash, zsh or fish")
+	errKind  = errors.New("unknown completion kind")
+)
+
+// Flag is a flag of a command. A flag with neither Values nor Kind is a
+// switch and takes no value.
+type Flag struct {
+	Name    string
+	Summary string
+	Values  []string
+	Kind    Kind
+}
+
+// TakesValue tells the flags followed by a value from the switches.
+func (f Flag) TakesValue() bool {
+	return len(f.Values) > 0 || f.Kind != ""
+}
+
+// Command is an entry of the command registry. Subcommands complete as the
+// first argument; Args are the kinds of the arguments after the command, or
+// after its subcommand, by position.
+type Command struct {
+	Name        string
+	Summary     string
+	Subcommands []string
+	Args        []Kind
+	Flags       []Flag
+}
+
+// Write writes the completion script of shell for program.
+func Write(w io.Writer, shell string, program string, commands []Command, global []Flag) error {
+	var script string
+	switch shell {
+	case "bash":
+		script = bash(program, commands, global)
+	case "zsh":
+		script = zsh(program, commands, global)
+	case "fish":
+		script = fish(program, commands, global)
+	default:
+		return fmt.Errorf("%w: %q", errShell, shell)
+	}
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// valueFlags returns the "command --flag" pairs of the flags taking a value,
+// so the scripts can tell flag values from arguments. Global flags pair with
+// any command.
+func valueFlags(commands []Command, global []Flag) []string {
+	var pairs []string
+	for _, f := range global {
+		if f.TakesValue() {
+			pairs = append(pairs, "* --"+f.Name)
+		}
+	}
+	for _, c := range commands {
+		for _, f := range c.Flags {
+			if f.TakesValue() {
+				pairs = append(pairs, c.Name+" --"+f.Name)
+			}
+		}
+	}
+	return pairs
+}
+
+func names(commands []Command) string {
+	list := make([]string, len(commands))
+	for i, c := range commands {
+		list[i] = c.Name
+	}
+	return strings.Join(list, " ")
+}
+
+func flagNames(flags []Flag) string {
+	list := make([]string, len(flags))
+	for i, f := range flags {
+		list[i] = "--" + f.Name
+	}
+	return strings.Join(list, " ")
+}
+
+// functionName turns the program name into a shell function name.
+func functionName(program string) string {
+	return "_" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, program)
+}
@@ -0,0 +1,162 @@
+package completion
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testCommands = []Command{
+	{Name: "list", Summary: "list the open issues", Flags: []Flag{
+		{Name: "format", Summary: "output format", Values: []string{"text", "json"}},
+		{Name: "label", Summary: "issues with the labels", Kind: KindLabel},
+		{Name: "no-pager", Summary: "print without the pager"},
+	}},
+	{Name: "view", Summary: "view an issue", Args: []Kind{KindIssue}},
+	{Name: "subissue", Summary: "add or remove a sub-issue", Subcommands: []string{"add", "remove"}, Args: []Kind{KindIssue, KindIssue}},
+	{Name: "import", Summary: "recreate the issues of an archive", Args: []Kind{KindFile}, Flags: []Flag{
+		{Name: "mapping", Summary: "mapping file", Kind: KindFile},
+	}},
+	{Name: "search", Summary: "list the issues matching a search", Args: []Kind{KindText}},
+}
+
+var testGlobal = []Flag{{Name: "profile", Summary: "run with a profile's values", Kind: KindProfile}}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  []string
+		check []string
+	}{
+		{
+			shell: "bash",
+			want: []string{
+				`*" --profile"|"list --format"|"list --label"|"import --mapping") return 0 ;;`,
+				`"list --format") COMPREPLY=($(compgen -W "text json" -- "$cur")); return ;;`,
+				`"list --label") _ghissues_dynamic label; return ;;`,
+				`COMPREPLY=($(compgen -W "list view subissue import search" -- "$cur"))`,
+				`list) COMPREPLY=($(compgen -W "--format --label --no-pager --profile" -- "$cur")) ;;`,
+				"        subissue)\n            case \"$positional\" in\n                0) COMPREPLY=($(compgen -W \"add remove\" -- \"$cur\")) ;;\n                1) _ghissues_dynamic issue ;;\n                2) _ghissues_dynamic issue ;;\n",
+				"complete -o default -F _ghissues ghissues\n",
+			},
+			check: []string{"bash", "-n"},
+		},
+		{
+			shell: "zsh",
+			want: []string{
+				"#compdef ghissues\n",
+				`"list --format") compadd -- text json; return ;;`,
+				`"import --mapping") _files; return ;;`,
+				`local -a commands=('list:list the open issues' 'view:view an issue'`,
+				`local -a flags=('--profile:run with a profile'\''s values')`,
+				"                0) _ghissues_dynamic issue ;;\n",
+				"    compdef _ghissues ghissues\n",
+			},
+			check: []string{"zsh", "-n"},
+		},
+		{
+			shell: "fish",
+			want: []string{
+				"complete -c ghissues -l profile -x -a '(ghissues __complete profile 2>/dev/null)' -d 'run with a profile\\'s values'\n",
+				"complete -c ghissues -n __ghissues_no_command -a view -d 'view an issue'\n",
+				"complete -c ghissues -n '__ghissues_using list' -l format -x -a 'text json' -d 'output format'\n",
+				"complete -c ghissues -n '__ghissues_using list' -l no-pager -d 'print without the pager'\n",
+				"complete -c ghissues -n '__ghissues_using subissue 1' -x -a '(ghissues __complete issue 2>/dev/null)'\n",
+				"complete -c ghissues -n '__ghissues_using import 0' -r -F\n",
+			},
+			check: []string{"fish", "--no-execute"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := Write(&b, tt.shell, "ghissues", testCommands, testGlobal); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			script := b.String()
+			for _, want := range tt.want {
+				if !strings.Contains(script, want) {
+					t.Errorf("script lacks %q", want)
+				}
+			}
+			// the text argument of search completes nothing
+			if strings.Contains(script, "search 0") || strings.Contains(script, "        search)\n") {
+				t.Error("script completes the text argument of search")
+			}
+
+			// the shell, when installed, checks the syntax
+			if _, err := exec.LookPath(tt.check[0]); err != nil {
+				return
+			}
+			file := filepath.Join(t.TempDir(), "ghissues."+tt.shell)
+			if err := os.WriteFile(file, b.Bytes(), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if out, err := exec.Command(tt.check[0], append(tt.check[1:], file)...).CombinedOutput(); err != nil {
+				t.Errorf("%s rejects the script: %v\n%s", tt.shell, err, out)
+			}
+		})
+	}
+
+	if err := Write(&bytes.Buffer{}, "powershell", "ghissues", testCommands, testGlobal); !errors.Is(err, errShell) {
+		t.Errorf("got %v want errShell", err)
+	}
+}
+
+func TestBashCompletes(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+	dir := t.TempDir()
+	var b bytes.Buffer
+	if err := Write(&b, "bash", "ghissues", testCommands, testGlobal); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "completion.bash"), b.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// a stand-in program answering __complete
+	fake := "#!/bin/sh\ncase \"$2\" in\n  issue) printf '12\\tCrash on start\\n7\\tDocs\\n' ;;\n  label) printf 'bug\\tSomething broken\\n' ;;\n  profile) echo work ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(dir, "ghissues"), []byte(fake), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{line: "ghissues vi", want: "view"},
+		{line: "ghissues view ''", want: "12 7"},
+		{line: "ghissues view 12 ''", want: ""},
+		{line: "ghissues --profile ''", want: "work"},
+		{line: "ghissues --profile work list --label ''", want: "bug"},
+		{line: "ghissues list --format json --l", want: "--label"},
+		{line: "ghissues subissue ''", want: "add remove"},
+		{line: "ghissues subissue add --profile work 12 ''", want: "12 7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			script := `source completion.bash
+COMP_WORDS=(` + tt.line + `)
+COMP_CWORD=$((${#COMP_WORDS[@]} - 1))
+_ghissues
+echo "${COMPREPLY[*]}"`
+			cmd := exec.Command("bash", "-c", script)
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "PATH="+dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+			out, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("bash failed: %v\n%s", err, out)
+			}
+			if got := strings.TrimSpace(string(out)); got != tt.want {
+				t.Errorf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package completion
+
+import (
+	"fmt"
+	"strings"
+)
+
+func fish(program string, commands []Command, global []Flag) string {
+	fn := strings.TrimPrefix(functionName(program), "_")
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s, generated by `%s completion fish`.\n", program, program)
+	fmt.Fprintf(&b, "# Save it as ~/.config/fish/completions/%s.fish, or load it with:\n", program)
+	fmt.Fprintf(&b, "# %s completion fish | source\n\n", program)
+
+	fmt.Fprintf(&b, "function __%s_takes_value\n    switch \"$argv[1] $argv[2]\"\n", fn)
+	fmt.Fprintf(&b, "        case %s\n            return 0\n    end\n    return 1\nend\n\n", fishPatterns(valueFlags(commands, global)))
+
+	// __<program>_words prints the number of arguments before the cursor and
+	// the command, if any, flags and their values left out
+	fmt.Fprintf(&b, "function __%s_words\n", fn)
+	b.WriteString(`    set -l tokens (commandline -opc)
+    set -l cmd ""
+    set -l positional 0
+    set -l skip 0
+    for word in $tokens[2..-1]
+        if test $skip = 1
+            set skip 0
+            continue
+        end
+        switch $word
+            case '-*'
+`)
+	fmt.Fprintf(&b, "                __%s_takes_value \"$cmd\" $word; and set skip 1\n", fn)
+	b.WriteString(`            case '*'
+                if test -z "$cmd"
+                    set cmd $word
+                else
+                    set positional (math $positional + 1)
+                end
+        end
+    end
+    echo $positional
+    echo $cmd
+end
+
+`)
+	fmt.Fprintf(&b, "function __%s_using\n    set -l words (__%s_words)\n", fn, fn)
+	b.WriteString(`    test "$words[2]" = "$argv[1]"; or return 1
+    test (count $argv) -lt 2; or test "$words[1]" = "$argv[2]"
+end
+
+`)
+	fmt.Fprintf(&b, "function __%s_no_command\n    set -l words (__%s_words)\n    test (count $words) -lt 2; or test -z \"$words[2]\"\nend\n\n", fn, fn)
+
+	fmt.Fprintf(&b, "complete -c %s -f\n", program)
+	for _, f := range global {
+		fmt.Fprintf(&b, "complete -c %s -l %s%s -d %s\n", program, f.Name, fishValues(program, f), fishQuote(f.Summary))
+	}
+	for _, c := range commands {
+		fmt.Fprintf(&b, "complete -c %s -n __%s_no_command -a %s -d %s\n", program, fn, c.Name, fishQuote(c.Summary))
+	}
+	for _, c := range commands {
+		for _, f := range c.Flags {
+			fmt.Fprintf(&b, "complete -c %s -n '__%s_using %s' -l %s%s -d %s\n", program, fn, c.Name, f.Name, fishValues(program, f), fishQuote(f.Summary))
+		}
+		for _, p := range arguments(c) {
+			fmt.Fprintf(&b, "complete -c %s -n '__%s_using %s %d'%s\n", program, fn, c.Name, p.position, fishValues(program, Flag{Values: p.values, Kind: p.kind}))
+		}
+	}
+	return b.String()
+}
+
+// fishValues are the options of complete offering the values of f.
+func fishValues(program string, f Flag) string {
+	switch {
+	case len(f.Values) > 0:
+		return " -x -a " + fishQuote(strings.Join(f.Values, " "))
+	case isDynamic(f.Kind):
+		return fmt.Sprintf(" -x -a '(%s __complete %s 2>/dev/null)'", program, f.Kind)
+	case f.Kind == KindFile:
+		return " -r -F"
+	case f.Kind == KindText:
+		return " -x"
+	}
+	return ""
+}
+
+func fishPatterns(pairs []string) string {
+	quoted := make([]string, len(pairs))
+	for i, pair := range pairs {
+		quoted[i] = fishQuote(pair)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,126 @@
+package completion
+
+import (
+	"fmt"
+	"strings"
+)
+
+func zsh(program string, commands []Command, global []Flag) string {
+	fn := functionName(program)
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n", program)
+	fmt.Fprintf(&b, "# zsh completion for %s, generated by `%s completion zsh`.\n", program, program)
+	fmt.Fprintf(&b, "# Save it as %s in a directory of $fpath, or load it with:\n", fn)
+	fmt.Fprintf(&b, "# source <(%s completion zsh)\n\n", program)
+
+	fmt.Fprintf(&b, "%s_takes_value() {\n    case \"$1 $2\" in\n", fn)
+	fmt.Fprintf(&b, "        %s) return 0 ;;\n    esac\n    return 1\n}\n\n", casePatterns(valueFlags(commands, global)))
+
+	// the values are printed as value<tab>description, _describe wants
+	// value:description with the colons of the value escaped
+	fmt.Fprintf(&b, "%s_dynamic() {\n    local line\n    local -a items\n", fn)
+	fmt.Fprintf(&b, "    for line in ${(f)\"$(%s __complete $1 2>/dev/null)\"}; do\n", program)
+	b.WriteString(`        if [[ "$line" == *$'\t'* ]]; then
+            items+=("${${line%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+        else
+            items+=("${line//:/\\:}")
+        fi
+    done
+    _describe -t "$1" "$1" items
+}
+
+`)
+
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString(`    local cur="${words[CURRENT]}" prev="${words[CURRENT-1]}"
+    local cmd="" word
+    local -i i positional=0
+    for ((i = 2; i < CURRENT; i++)); do
+        word="${words[i]}"
+        if [[ "$word" == -* ]]; then
+`)
+	fmt.Fprintf(&b, "            %s_takes_value \"$cmd\" \"$word\" && ((i++))\n", fn)
+	b.WriteString(`            continue
+        fi
+        if [[ -z "$cmd" ]]; then
+            cmd="$word"
+        else
+            ((positional++))
+        fi
+    done
+
+    case "$cmd $prev" in
+`)
+	for _, f := range global {
+		if f.TakesValue() {
+			fmt.Fprintf(&b, "        *\" --%s\") %s; return ;;\n", f.Name, zshValues(fn, f))
+		}
+	}
+	for _, c := range commands {
+		for _, f := range c.Flags {
+			if f.TakesValue() {
+				fmt.Fprintf(&b, "        \"%s --%s\") %s; return ;;\n", c.Name, f.Name, zshValues(fn, f))
+			}
+		}
+	}
+	b.WriteString("    esac\n\n")
+
+	b.WriteString("    if [[ -z \"$cmd\" ]]; then\n        if [[ \"$cur\" == -* ]]; then\n")
+	fmt.Fprintf(&b, "            local -a flags=(%s)\n            _describe -t flags flag flags\n        else\n", zshDescribed(flagItems(global)))
+	items := make([]string, len(commands))
+	for i, c := range commands {
+		items[i] = c.Name + ":" + c.Summary
+	}
+	fmt.Fprintf(&b, "            local -a commands=(%s)\n            _describe -t commands command commands\n        fi\n        return\n    fi\n\n", zshDescribed(items))
+
+	b.WriteString("    if [[ \"$cur\" == -* ]]; then\n        local -a flags\n        case \"$cmd\" in\n")
+	for _, c := range commands {
+		if len(c.Flags) > 0 {
+			fmt.Fprintf(&b, "            %s) flags=(%s) ;;\n", c.Name, zshDescribed(flagItems(append(c.Flags, global...))))
+		}
+	}
+	b.WriteString("        esac\n        _describe -t flags flag flags\n        return\n    fi\n\n    case \"$cmd\" in\n")
+	for _, c := range commands {
+		positions := arguments(c)
+		if len(positions) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "        %s)\n            case \"$positional\" in\n", c.Name)
+		for _, p := range positions {
+			fmt.Fprintf(&b, "                %d) %s ;;\n", p.position, zshValues(fn, Flag{Values: p.values, Kind: p.kind}))
+		}
+		b.WriteString("            esac ;;\n")
+	}
+	b.WriteString("    esac\n}\n\n")
+	fmt.Fprintf(&b, "if [[ \"$funcstack[1]\" == \"%s\" ]]; then\n    %s \"$@\"\nelse\n    compdef %s %s\nfi\n", fn, fn, fn, program)
+	return b.String()
+}
+
+func zshValues(fn string, f Flag) string {
+	switch {
+	case len(f.Values) > 0:
+		return "compadd -- " + strings.Join(f.Values, " ")
+	case isDynamic(f.Kind):
+		return fmt.Sprintf("%s_dynamic %s", fn, f.Kind)
+	case f.Kind == KindFile:
+		return "_files"
+	}
+	return ":"
+}
+
+func flagItems(flags []Flag) []string {
+	items := make([]string, len(flags))
+	for i, f := range flags {
+		items[i] = "--" + f.Name + ":" + f.Summary
+	}
+	return items
+}
+
+// zshDescribed quotes name:description items for an array.
+func zshDescribed(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = "'" + strings.ReplaceAll(item, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,258 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"git-issues/domain"
+)
+
+var (
+	errAliasName     = errors.New("invalid name, use letters, digits, - and _")
+	errAliasShadows  = errors.New("an alias cannot replace a command")
+	errAliasCommand  = errors.New("an alias must start with a command")
+	errAliasNotFound = errors.New("alias not found")
+	errAliasArgs     = errors.New("missing argument for the alias")
+	errQueryEmpty    = errors.New("the query is empty")
+	errQueryNotFound = errors.New("saved query not found")
+	errQuote         = errors.New("unterminated quote")
+)
+
+// SetAlias stores expansion, a command line without the program name, under
+// name. commands are the names an alias may start with and may not take.
+func (f *Feature) SetAlias(name string, expansion string, commands []string) error {
+	if !validName(name) {
+		return fmt.Errorf("%w: %q", errAliasName, name)
+	}
+	if contains(commands, name) {
+		return fmt.Errorf("%w: %s", errAliasShadows, name)
+	}
+	words, err := SplitWords(expansion)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 || !contains(commands, words[0]) {
+		return fmt.Errorf("%w: %q", errAliasCommand, expansion)
+	}
+
+	config, err := f.configOrNew()
+	if err != nil {
+		return err
+	}
+	if config.Aliases == nil {
+		config.Aliases = map[string]string{}
+	}
+	config.Aliases[name] = expansion
+	return f.save(config)
+}
+
+func (f *Feature) DeleteAlias(name string) error {
+	config, err := f.GetConfig()
+	if err != nil {
+		return err
+	}
+	if _, ok := config.Aliases[name]; !ok {
+		return fmt.Errorf("%w: %s", errAliasNotFound, name)
+	}
+	delete(config.Aliases, name)
+	return f.save(config)
+}
+
+// Aliases returns the aliases with their names in order.
+func (f *Feature) Aliases() (map[string]string, []string, error) {
+	config, err := f.GetConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	return config.Aliases, sortedKeys(config.Aliases), nil
+}
+
+// SaveQuery stores a search in the GitHub syntax, like "is:open label:bug",
+// under name.
+func (f *Feature) SaveQuery(name string, query string) error {
+	if !validName(name) {
+		return fmt.Errorf("%w: %q", errAliasName, name)
+	}
+	if strings.TrimSpace(query) == "" {
+		return errQueryEmpty
+	}
+
+	config, err := f.configOrNew()
+	if err != nil {
+		return err
+	}
+	if config.Queries == nil {
+		config.Queries = map[string]string{}
+	}
+	config.Queries[name] = strings.TrimSpace(query)
+	return f.save(config)
+}
+
+func (f *Feature) DeleteQuery(name string) error {
+	config, err := f.GetConfig()
+	if err != nil {
+		return err
+	}
+	if _, ok := config.Queries[name]; !ok {
+		return fmt.Errorf("%w: %s", errQueryNotFound, name)
+	}
+	delete(config.Queries, name)
+	return f.save(config)
+}
+
+// Queries returns the saved queries with their names in order.
+func (f *Feature) Queries() (map[string]string, []string, error) {
+	config, err := f.GetConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	return config.Queries, sortedKeys(config.Queries), nil
+}
+
+// Query returns the saved query called name.
+func Query(config *domain.Config, name string) (string, error) {
+	query, ok := config.Queries[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", errQueryNotFound, name)
+	}
+	return query, nil
+}
+
+// ExpandAlias splits expansion into words the way a shell would and puts
+// the arguments in place of $1 to $9, or all of them in place of $@, outside
+// of single quotes. Arguments no placeholder takes are appended.
+func ExpandAlias(expansion string, args []string) ([]string, error) {
+	used := 0
+	words, err := splitWords(expansion, func(ref rune) ([]string, error) {
+		if ref == '@' {
+			used = len(args)
+			return args, nil
+		}
+		n := int(ref - '0')
+		if n > len(args) {
+			return nil, fmt.Errorf("%w: $%d of %q", errAliasArgs, n, expansion)
+		}
+		if n > used {
+			used = n
+		}
+		return args[n-1 : n], nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(words, args[used:]...), nil
+}
+
+// SplitWords splits s on spaces outside of single or double quotes, which
+// are removed. A backslash keeps the next character as it is, outside of
+// single quotes.
+func SplitWords(s string) ([]string, error) {
+	return splitWords(s, nil)
+}
+
+// splitWords is SplitWords calling arg for each $1 to $9 and $@ outside of
+// single quotes when it is not nil. The values of an unquoted $@ are words of
+// their own, elsewhere they are joined by spaces.
+func splitWords(s string, arg func(ref rune) ([]string, error)) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case arg != nil && r == '$' && quote != '\'' && i+1 < len(runes) && (runes[i+1] == '@' || runes[i+1] >= '1' && runes[i+1] <= '9'):
+			values, err := arg(runes[i+1])
+			if err != nil {
+				return nil, err
+			}
+			i++
+			if runes[i] == '@' && quote == 0 {
+				// "x$@" keeps x on the first value, like a shell
+				for j, value := range values {
+					if j > 0 {
+						words = append(words, word.String())
+						word.Reset()
+					}
+					word.WriteString(value)
+				}
+				inWord = inWord || len(values) > 0
+				continue
+			}
+			word.WriteString(strings.Join(values, " "))
+			inWord = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("%w: %s", errQuote, s)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// configOrNew returns the config, or a new one when there is no file yet.
+func (f *Feature) configOrNew() (*domain.Config, error) {
+	config, err := f.GetConfig()
+	if errors.Is(err, os.ErrNotExist) {
+		config, err = &domain.Config{APIBaseURL: domain.ApiBaseUrl}, nil
+	}
+	return config, err
+}
+
+func validName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "-") {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
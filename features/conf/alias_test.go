@@ -0,0 +1,168 @@
+package conf
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"git-issues/domain"
+)
+
+var aliasCommands = []string{"list", "search", "view"}
+
+func TestSetAlias(t *testing.T) {
+	tests := []struct {
+		name      string
+		alias     string
+		expansion string
+		want      map[string]string
+		wantErr   error
+	}{
+		{
+			name:      "set",
+			alias:     "mine",
+			expansion: "list --assignee @me --state open --sort updated",
+			want:      map[string]string{"old": "view 1", "mine": "list --assignee @me --state open --sort updated"},
+		},
+		{
+			name:      "replace",
+			alias:     "old",
+			expansion: "view $1",
+			want:      map[string]string{"old": "view $1"},
+		},
+		{name: "command name", alias: "list", expansion: "list --state open", wantErr: errAliasShadows},
+		{name: "invalid name", alias: "my alias", expansion: "list", wantErr: errAliasName},
+		{name: "not a command", alias: "mine", expansion: "rm -rf /", wantErr: errAliasCommand},
+		{name: "empty", alias: "mine", expansion: " ", wantErr: errAliasCommand},
+		{name: "bad quoting", alias: "mine", expansion: "search 'label:bug", wantErr: errQuote},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written []byte
+			f := New()
+			f.config = &domain.Config{Token: "t", Aliases: map[string]string{"old": "view 1"}}
+			f.writeFile = func(filename string, data []byte, perm os.FileMode) error {
+				written = data
+				return nil
+			}
+
+			err := f.SetAlias(tt.alias, tt.expansion, aliasCommands)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if written != nil {
+					t.Error("config saved on error")
+				}
+				return
+			}
+			var saved domain.Config
+			if err = json.Unmarshal(written, &saved); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(saved.Aliases, tt.want) || saved.Token != "t" {
+				t.Errorf("got %+v want aliases %v", saved, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueries(t *testing.T) {
+	var written []byte
+	f := New()
+	f.config = &domain.Config{Queries: map[string]string{"bugs": "is:open label:bug"}}
+	f.writeFile = func(filename string, data []byte, perm os.FileMode) error {
+		written = data
+		return nil
+	}
+
+	if err := f.SaveQuery("triage", "  is:open no:label "); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := f.DeleteQuery("bugs"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var saved domain.Config
+	if err := json.Unmarshal(written, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(saved.Queries, map[string]string{"triage": "is:open no:label"}) {
+		t.Errorf("got %v", saved.Queries)
+	}
+
+	if err := f.SaveQuery("empty", " "); !errors.Is(err, errQueryEmpty) {
+		t.Errorf("got %v want errQueryEmpty", err)
+	}
+	if err := f.DeleteQuery("bugs"); !errors.Is(err, errQueryNotFound) {
+		t.Errorf("got %v want errQueryNotFound", err)
+	}
+	if _, err := Query(&saved, "triage"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := Query(&saved, "bugs"); !errors.Is(err, errQueryNotFound) {
+		t.Errorf("got %v want errQueryNotFound", err)
+	}
+}
+
+func TestExpandAlias(t *testing.T) {
+	tests := []struct {
+		name      string
+		expansion string
+		args      []string
+		want      []string
+		wantErr   error
+	}{
+		{
+			name:      "no placeholders appends the arguments",
+			expansion: "list --state open",
+			args:      []string{"--format", "json"},
+			want:      []string{"list", "--state", "open", "--format", "json"},
+		},
+		{
+			name:      "positional",
+			expansion: `search "label:$1 is:open" --sort $2`,
+			args:      []string{"bug", "updated", "--no-pager"},
+			want:      []string{"search", "label:bug is:open", "--sort", "updated", "--no-pager"},
+		},
+		{
+			name:      "all arguments",
+			expansion: `transfer $@ --yes`,
+			args:      []string{"12", "owner/other"},
+			want:      []string{"transfer", "12", "owner/other", "--yes"},
+		},
+		{
+			name:      "quotes and escapes",
+			expansion: `search "label:\"good first issue\" $1" it\'s '$5'`,
+			args:      []string{"is:open"},
+			want:      []string{"search", `label:"good first issue" is:open`, "it's", "$5"},
+		},
+		{
+			name:      "all arguments quoted",
+			expansion: `search "$@" --sort updated`,
+			args:      []string{"label:bug", "is:open"},
+			want:      []string{"search", "label:bug is:open", "--sort", "updated"},
+		},
+		{
+			name:      "missing argument",
+			expansion: "view $1",
+			wantErr:   errAliasArgs,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandAlias(tt.expansion, tt.args)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,6 +10,9 @@ import (
 	"strings"
 
 	"git-issues/domain"
+	"git-issues/service/client"
+	"git-issues/service/host"
+	"git-issues/service/terminal"
 )
 
 type Conf interface {
@@ -24,67 +27,252 @@ var (
 type Feature struct {
 	config    *domain.Config
 	reader    io.Reader
+	writer    io.Writer
 	writeFile func(filename string, data []byte, perm os.FileMode) error
+	// hideInput stops echoing the token while it is typed.
+	hideInput func() (func() error, error)
+	// newClient builds the client Init checks the answers with.
+	newClient func(config *domain.Config) client.HeaderClient
+}
+
+// answers are the values Init asks for.
+type answers struct {
+	host   string
+	caCert string
+	token  string
+	owner  string
+	repo   string
+	editor string
 }
 
 func New() *Feature {
 	return &Feature{
 		writeFile: os.WriteFile,
 		reader:    os.Stdin,
+		writer:    os.Stdout,
+		hideInput: hideStdin,
+		newClient: func(config *domain.Config) client.HeaderClient { return client.New(config) },
 	}
 }
 
+// Init asks for the configuration, showing the values of an existing one as
+// defaults, and checks them against the api. When a check fails the values
+// at fault can be entered again before saving.
 func (f *Feature) Init() error {
 	reader := bufio.NewReader(f.reader)
 
-	fmt.Print("GitHub Personal Access Token: ")
-	token, err := reader.ReadString('\n')
+	existing, err := loadConfig()
 	if err != nil {
-		return err
+		existing = &domain.Config{}
 	}
-	token = strings.TrimSpace(token)
+	values := answersFrom(existing)
 
-	fmt.Print("Repository Owner (username/organization): ")
-	owner, err := reader.ReadString('\n')
-	if err != nil {
-		return err
+	ask := []field{fieldHost, fieldToken, fieldRepo, fieldEditor}
+	for {
+		if err = f.ask(reader, existing, &values, ask); err != nil {
+			return err
+		}
+
+		result := f.validate(values)
+		if err = result.print(f.writer); err != nil {
+			return err
+		}
+		if len(result.problems) == 0 {
+			break
+		}
+
+		retry, err := f.confirm(reader, "Re-enter the values with errors? [Y/n]: ")
+		if err != nil {
+			return err
+		}
+		if !retry {
+			break
+		}
+		ask = result.fields()
 	}
-	owner = strings.TrimSpace(owner)
 
-	fmt.Print("Repository Name: ")
-	repo, err := reader.ReadString('\n')
-	if err != nil {
+	if err = f.save(values.apply(existing)); err != nil {
 		return err
 	}
-	repo = strings.TrimSpace(repo)
+	fmt.Fprintln(f.writer, "conf created with success!")
+	return nil
+}
 
-	fmt.Print("Default text editor (empty for system default): ")
-	editor, err := reader.ReadString('\n')
-	if err != nil {
-		return err
+func (f *Feature) ask(reader *bufio.Reader, existing *domain.Config, values *answers, fields []field) error {
+	var err error
+	for _, fld := range fields {
+		switch fld {
+		case fieldHost:
+			hostName := values.host
+			if hostName == "" {
+				hostName = host.GitHub
+			}
+			if hostName, err = f.prompt(reader, "GitHub host", hostName); err != nil {
+				return err
+			}
+			if name := host.New(hostName).Name; name != values.host {
+				// another host brings its own token and CA bundle
+				values.host = name
+				values.token, values.caCert = "", ""
+				if settings, ok := host.Settings(existing, host.New(name)); ok {
+					values.token, values.caCert = settings.Token, settings.CACert
+				}
+			}
+			if host.New(values.host).IsEnterprise() {
+				if values.caCert, err = f.prompt(reader, "CA bundle for the host (empty for the system roots)", values.caCert); err != nil {
+					return err
+				}
+			}
+		case fieldToken:
+			if values.token, err = f.promptSecret(reader, "GitHub Personal Access Token", values.token); err != nil {
+				return err
+			}
+		case fieldRepo:
+			if values.owner, err = f.prompt(reader, "Repository Owner (username/organization)", values.owner); err != nil {
+				return err
+			}
+			if values.repo, err = f.prompt(reader, "Repository Name", values.repo); err != nil {
+				return err
+			}
+		case fieldEditor:
+			if values.editor, err = f.prompt(reader, "Default text editor (empty for system default)", values.editor); err != nil {
+				return err
+			}
+		}
 	}
-	editor = strings.TrimSpace(editor)
+	return nil
+}
 
-	config := domain.Config{
-		Token:      token,
-		Owner:      owner,
-		Repo:       repo,
-		Editor:     editor,
-		APIBaseURL: domain.ApiBaseUrl,
+// prompt reads a line, keeping current when it is empty.
+func (f *Feature) prompt(reader *bufio.Reader, label string, current string) (string, error) {
+	if current != "" {
+		fmt.Fprintf(f.writer, "%s [%s]: ", label, current)
+	} else {
+		fmt.Fprintf(f.writer, "%s: ", label)
 	}
+	return readAnswer(reader, current)
+}
 
-	configData, err := json.MarshalIndent(config, "", "  ")
+// promptSecret reads a line without echo and never shows the current value.
+func (f *Feature) promptSecret(reader *bufio.Reader, label string, current string) (string, error) {
+	if current != "" {
+		fmt.Fprintf(f.writer, "%s [keep current]: ", label)
+	} else {
+		fmt.Fprintf(f.writer, "%s: ", label)
+	}
+
+	if restore, err := f.hideInput(); err == nil {
+		defer func() {
+			restore()
+			// the newline typed by the user was not echoed
+			fmt.Fprintln(f.writer)
+		}()
+	}
+	return readAnswer(reader, current)
+}
+
+func (f *Feature) confirm(reader *bufio.Reader, question string) (bool, error) {
+	fmt.Fprint(f.writer, question)
+	answer, err := readAnswer(reader, "y")
 	if err != nil {
-		return fmt.Errorf("could not generat conf: %w\n", err)
+		return false, err
 	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
 
-	err = f.writeFile(domain.ConfigFile, configData, 0600)
+func readAnswer(reader *bufio.Reader, current string) (string, error) {
+	answer, err := reader.ReadString('\n')
 	if err != nil {
-		return fmt.Errorf("could not save conf: %w\n", err)
+		return "", err
 	}
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return current, nil
+	}
+	return answer, nil
+}
 
-	fmt.Println("conf created with success!")
-	return nil
+// hideStdin disables the echo when the input is a terminal.
+func hideStdin() (func() error, error) {
+	if !terminal.IsTerminal(os.Stdin) {
+		return nil, errors.New("stdin is not a terminal")
+	}
+	return terminal.DisableEcho()
+}
+
+// answersFrom takes the defaults from the top level values of the config.
+func answersFrom(config *domain.Config) answers {
+	values := answers{
+		host:   config.Host,
+		token:  config.Token,
+		owner:  config.Owner,
+		repo:   config.Repo,
+		editor: config.Editor,
+	}
+	if values.host == "" && config.APIBaseURL != "" {
+		values.host = host.FromAPIBaseURL(config.APIBaseURL).Name
+	}
+	if h := host.New(values.host); h.IsEnterprise() {
+		if settings, ok := host.Settings(config, h); ok {
+			values.token, values.caCert = settings.Token, settings.CACert
+		}
+	}
+	return values
+}
+
+// apply writes the answers into the config, keeping what Init does not ask
+// for, like profiles and the settings of other hosts.
+func (a answers) apply(existing *domain.Config) *domain.Config {
+	config := *existing
+	h := host.New(a.host)
+	hosts := map[string]domain.HostConfig{}
+	for name, settings := range existing.Hosts {
+		hosts[name] = settings
+	}
+
+	// the token and tls files of the previous host move to its settings,
+	// where they are not set yet, so that switching back needs no new login
+	previous := host.FromAPIBaseURL(existing.APIBaseURL)
+	if existing.Host != "" {
+		previous = host.New(existing.Host)
+	}
+	if previous.Name != h.Name && (existing.Token != "" || existing.CACert != "" || existing.ClientCert != "") {
+		key, ok := hostKey(existing, previous)
+		if !ok {
+			key = previous.Name
+		}
+		settings := hosts[key]
+		fill(&settings.Token, existing.Token)
+		fill(&settings.CACert, existing.CACert)
+		fill(&settings.ClientCert, existing.ClientCert)
+		fill(&settings.ClientKey, existing.ClientKey)
+		hosts[key] = settings
+		config.CACert, config.ClientCert, config.ClientKey = "", "", ""
+	}
+
+	config.Token = a.token
+	config.Owner = a.owner
+	config.Repo = a.repo
+	config.Editor = a.editor
+	config.APIBaseURL = h.REST
+	config.Host = ""
+	if h.IsEnterprise() {
+		// the token belongs to the host, so profiles on it can share it
+		settings := hosts[h.Name]
+		settings.Token, settings.CACert = a.token, a.caCert
+		hosts[h.Name] = settings
+		config.Host, config.Token = h.Name, ""
+	}
+	if len(hosts) > 0 {
+		config.Hosts = hosts
+	}
+	return &config
+}
+
+func fill(value *string, with string) {
+	if *value == "" {
+		*value = with
+	}
 }
 
 func (f *Feature) GetConfig() (*domain.Config, error) {
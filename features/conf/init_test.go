@@ -3,19 +3,52 @@ package conf
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"reflect"
 	"strings"
 	"testing"
 
 	"git-issues/domain"
+	"git-issues/service/client"
 	"git-issues/testdata/data"
+	"git-issues/testdata/stubs"
 )
 
+// fakeAPI answers /user with the login and scopes and any repository with
+// issues and push access, unless the token or repository is in bad.
+func fakeAPI(f *Feature, bad ...string) *[]string {
+	var requests []string
+	f.writer = io.Discard
+	f.hideInput = func() (func() error, error) { return func() error { return nil }, nil }
+	f.newClient = func(config *domain.Config) client.HeaderClient {
+		return &stubs.ClientStub{
+			MakeRequestHeaderFunc: func(method, url string) ([]byte, http.Header, error) {
+				requests = append(requests, url)
+				for _, b := range bad {
+					if config.Token == b || strings.HasSuffix(url, "/"+b) {
+						return nil, nil, errors.Join(errors.New("404 Not Found"), domain.ErrApi)
+					}
+				}
+				if strings.HasSuffix(url, "/user") {
+					header := http.Header{}
+					header.Set("X-OAuth-Scopes", "repo, project")
+					return []byte(`{"login":"octocat"}`), header, nil
+				}
+				name := url[strings.Index(url, "/repos/")+len("/repos/"):]
+				return []byte(fmt.Sprintf(`{"full_name":%q,"has_issues":true,"permissions":{"push":true}}`, name)), nil, nil
+			},
+		}
+	}
+	return &requests
+}
+
 func TestInitConfig(t *testing.T) {
 	// ARRANGE
 	// user inputs
-	input := "myToken\nmyOwner\nmyRepo\nmyEditor\n"
+	input := "\nmyToken\nmyOwner\nmyRepo\nmyEditor\n"
 	reader := strings.NewReader(input)
 
 	// "Fake" FileWriter store data in a variable
@@ -34,6 +67,7 @@ func TestInitConfig(t *testing.T) {
 
 	ft.writeFile = fakeWriteFile
 	ft.reader = reader
+	requests := fakeAPI(ft)
 
 	// ACT
 	err := ft.Init()
@@ -49,18 +83,23 @@ func TestInitConfig(t *testing.T) {
 	}
 
 	// ASSERT
-	if want != got {
+	if !reflect.DeepEqual(want, got) {
 		t.Errorf("unexpected configuration")
 	}
+	wantRequests := []string{domain.ApiBaseUrl + "/user", domain.ApiBaseUrl + "/repos/myOwner/myRepo"}
+	if !reflect.DeepEqual(*requests, wantRequests) {
+		t.Errorf("requests: got %v, want %v", *requests, wantRequests)
+	}
 }
 
 func TestInitWriteFileError(t *testing.T) {
 	// ARRANGE
-	input := "tkn\nowner\nrepo\neditor\n"
+	input := "\ntkn\nowner\nrepo\neditor\n"
 	reader := strings.NewReader(input)
 
 	f := New()
 	f.reader = reader
+	fakeAPI(f)
 	f.writeFile = func(filename string, data []byte, perm os.FileMode) error {
 		return errors.New("disk full")
 	}
@@ -158,3 +197,232 @@ func TestGetConfigInvalidJSON(t *testing.T) {
 		t.Fatalf("expected error to be wrapped with errReadConfig for invalid json; got: %v", err)
 	}
 }
+
+func TestInitEnterpriseHost(t *testing.T) {
+	input := "https://ghe.example.com/\n/etc/ghe-ca.pem\nghe-token\nplatform\napi\n\n"
+	existing := domain.Config{Token: "dotcom-token", CACert: "/etc/corp-ca.pem", Owner: "octo", Repo: "tools", APIBaseURL: domain.ApiBaseUrl}
+	data, err := json.Marshal(existing)
+	if err != nil {
+		t.Fatalf("failed to marshal config: %v", err)
+	}
+	if err := os.WriteFile(domain.ConfigFile, data, 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Remove(domain.ConfigFile) })
+
+	var written []byte
+	f := New()
+	f.reader = strings.NewReader(input)
+	fakeAPI(f)
+	f.writeFile = func(filename string, data []byte, perm os.FileMode) error {
+		written = data
+		return nil
+	}
+
+	if err := f.Init(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got domain.Config
+	if err := json.Unmarshal(written, &got); err != nil {
+		t.Fatalf("error on json decoding: %v", err)
+	}
+	want := domain.Config{
+		Owner:      "platform",
+		Repo:       "api",
+		Host:       "ghe.example.com",
+		APIBaseURL: "https://ghe.example.com/api/v3",
+		Hosts: map[string]domain.HostConfig{
+			"ghe.example.com": {Token: "ghe-token", CACert: "/etc/ghe-ca.pem"},
+			// the previous host keeps its token and CA bundle
+			"github.com": {Token: "dotcom-token", CACert: "/etc/corp-ca.pem"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestInitBackToPreviousHost(t *testing.T) {
+	existing := domain.Config{
+		Owner:      "platform",
+		Repo:       "api",
+		Host:       "ghe.example.com",
+		APIBaseURL: "https://ghe.example.com/api/v3",
+		Hosts: map[string]domain.HostConfig{
+			"ghe.example.com": {Token: "ghe-token"},
+			"github.com":      {Token: "dotcom-token"},
+		},
+	}
+	data, err := json.Marshal(existing)
+	if err != nil {
+		t.Fatalf("failed to marshal config: %v", err)
+	}
+	if err := os.WriteFile(domain.ConfigFile, data, 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Remove(domain.ConfigFile) })
+
+	var written []byte
+	f := New()
+	// only the host changes, the token is kept
+	f.reader = strings.NewReader("github.com\n\n\n\n\n")
+	fakeAPI(f)
+	f.writeFile = func(filename string, data []byte, perm os.FileMode) error {
+		written = data
+		return nil
+	}
+
+	if err := f.Init(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got domain.Config
+	if err := json.Unmarshal(written, &got); err != nil {
+		t.Fatalf("error on json decoding: %v", err)
+	}
+	if got.Token != "dotcom-token" || got.Host != "" || got.APIBaseURL != domain.ApiBaseUrl || got.Hosts["ghe.example.com"].Token != "ghe-token" {
+		t.Errorf("unexpected config %+v", got)
+	}
+}
+
+func TestInitReenterInvalidValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		bad   []string
+		want  domain.Config
+	}{
+		{
+			name: "bad token asked again",
+			// the second answer set only asks for the token
+			input: "\nbadToken\nowner\nrepo\n\n\ngoodToken\n",
+			bad:   []string{"badToken"},
+			want:  domain.Config{Token: "goodToken", Owner: "owner", Repo: "repo", APIBaseURL: domain.ApiBaseUrl},
+		},
+		{
+			name: "missing repository asked again with defaults",
+			// keeps the owner and changes the repository
+			input: "\ntoken\nowner\nmissing\n\ny\n\nrepo\n",
+			bad:   []string{"missing"},
+			want:  domain.Config{Token: "token", Owner: "owner", Repo: "repo", APIBaseURL: domain.ApiBaseUrl},
+		},
+		{
+			name:  "saved anyway",
+			input: "\ntoken\nowner\nmissing\n\nn\n",
+			bad:   []string{"missing"},
+			want:  domain.Config{Token: "token", Owner: "owner", Repo: "missing", APIBaseURL: domain.ApiBaseUrl},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written []byte
+			f := New()
+			f.reader = strings.NewReader(tt.input)
+			fakeAPI(f, tt.bad...)
+			f.writeFile = func(filename string, data []byte, perm os.FileMode) error {
+				written = data
+				return nil
+			}
+
+			if err := f.Init(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got domain.Config
+			if err := json.Unmarshal(written, &got); err != nil {
+				t.Fatalf("error on json decoding: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInitShowsExistingValues(t *testing.T) {
+	existing := domain.Config{
+		Token:      "oldToken",
+		Owner:      "oldOwner",
+		Repo:       "oldRepo",
+		Editor:     "vim",
+		APIBaseURL: domain.ApiBaseUrl,
+		Profiles:   map[string]domain.Profile{"work": {Repo: "work"}},
+	}
+	data, err := json.Marshal(existing)
+	if err != nil {
+		t.Fatalf("failed to marshal config: %v", err)
+	}
+	if err := os.WriteFile(domain.ConfigFile, data, 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Remove(domain.ConfigFile) })
+
+	var written []byte
+	var out strings.Builder
+	f := New()
+	// only the repository changes
+	f.reader = strings.NewReader("\n\n\nnewRepo\n\n")
+	fakeAPI(f)
+	f.writer = &out
+	f.writeFile = func(filename string, data []byte, perm os.FileMode) error {
+		written = data
+		return nil
+	}
+
+	if err := f.Init(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got domain.Config
+	if err := json.Unmarshal(written, &got); err != nil {
+		t.Fatalf("error on json decoding: %v", err)
+	}
+	want := existing
+	want.Repo = "newRepo"
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	for _, prompt := range []string{"GitHub host [github.com]", "Token [keep current]", "Owner (username/organization) [oldOwner]", "Default text editor (empty for system default) [vim]"} {
+		if !strings.Contains(out.String(), prompt) {
+			t.Errorf("output misses %q:\n%s", prompt, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "oldToken") {
+		t.Errorf("the token was shown:\n%s", out.String())
+	}
+}
+
+func TestValidateReports(t *testing.T) {
+	f := New()
+	fakeAPI(f)
+	f.newClient = func(config *domain.Config) client.HeaderClient {
+		return &stubs.ClientStub{
+			MakeRequestHeaderFunc: func(method, url string) ([]byte, http.Header, error) {
+				if strings.HasSuffix(url, "/user") {
+					header := http.Header{}
+					header.Set("X-OAuth-Scopes", "public_repo")
+					return []byte(`{"login":"octocat"}`), header, nil
+				}
+				return []byte(`{"full_name":"o/r","has_issues":true,"permissions":{"push":false}}`), nil, nil
+			},
+		}
+	}
+
+	result := f.validate(answers{host: "github.com", token: "t", owner: "o", repo: "r"})
+
+	if len(result.problems) != 0 {
+		t.Errorf("warnings must not ask to re-enter: %v", result.problems)
+	}
+	var out strings.Builder
+	if err := result.print(&out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, line := range []string{"ok       logged in to github.com as octocat", "warning  the token is missing the scopes: repo, project", "warning  no push access to o/r"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("output misses %q:\n%s", line, out.String())
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package conf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"git-issues/application"
+	"git-issues/domain"
+)
+
+// DefaultProfile selects the top level values of the config, unless a
+// profile is named like it.
+const DefaultProfile = application.DefaultProfile
+
+var errProfileNotFound = errors.New("profile not found")
+
+// Use makes the named profile the one commands run with when --profile is
+// not given.
+func (f *Feature) Use(name string) error {
+	config, err := f.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	switch _, ok := config.Profiles[name]; {
+	case ok:
+		config.CurrentProfile = name
+	case name == DefaultProfile:
+		config.CurrentProfile = ""
+	default:
+		return fmt.Errorf("%w: %s", errProfileNotFound, name)
+	}
+	return f.save(config)
+}
+
+// Profiles returns the names of the profiles in order and the current one.
+func (f *Feature) Profiles() ([]string, string, error) {
+	config, err := f.GetConfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	names := make([]string, 0, len(config.Profiles))
+	for name := range config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, config.CurrentProfile, nil
+}
+
+func (f *Feature) save(config *domain.Config) error {
+	configData, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not generat conf: %w", err)
+	}
+	if err = f.writeFile(domain.ConfigFile, configData, 0600); err != nil {
+		return fmt.Errorf("could not save conf: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package conf
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"git-issues/domain"
+)
+
+func TestUseProfile(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     string
+		use         string
+		wantCurrent string
+		wantErr     error
+	}{
+		{name: "select profile", use: "work", wantCurrent: "work"},
+		{name: "back to default", current: "work", use: DefaultProfile, wantCurrent: ""},
+		{name: "unknown profile", use: "home", wantErr: errProfileNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written []byte
+			f := New()
+			f.config = &domain.Config{
+				Token:          "t",
+				Profiles:       map[string]domain.Profile{"work": {Host: "ghe.example.com", Repo: "r"}},
+				CurrentProfile: tt.current,
+			}
+			f.writeFile = func(filename string, data []byte, perm os.FileMode) error {
+				if filename != domain.ConfigFile || perm != 0600 {
+					t.Errorf("unexpected write of %s with %v", filename, perm)
+				}
+				written = data
+				return nil
+			}
+
+			err := f.Use(tt.use)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				if written != nil {
+					t.Error("config should not be written on error")
+				}
+				return
+			}
+
+			var got domain.Config
+			if err = json.Unmarshal(written, &got); err != nil {
+				t.Fatalf("invalid config written: %v", err)
+			}
+			if got.CurrentProfile != tt.wantCurrent || got.Token != "t" || got.Profiles["work"].Host != "ghe.example.com" {
+				t.Errorf("unexpected config written: %+v", got)
+			}
+		})
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	f := New()
+	f.config = &domain.Config{
+		Profiles:       map[string]domain.Profile{"work": {}, "oss": {}, "home": {}},
+		CurrentProfile: "oss",
+	}
+
+	names, current, err := f.Profiles()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"home", "oss", "work"}) || current != "oss" {
+		t.Errorf("got %v, %q", names, current)
+	}
+}
@@ -0,0 +1,122 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"git-issues/application"
+	"git-issues/domain"
+	"git-issues/service/host"
+)
+
+// SaveToken stores the token where the config is read from for the profile,
+// or the current one when profile is empty, DefaultProfile being the top
+// level values: in the profile when there is one,
+// in the host settings for Enterprise hosts or hosts that already have them,
+// and at the top level otherwise. An empty token removes it. A missing config
+// file is created.
+func (f *Feature) SaveToken(profile string, token string) error {
+	config, err := f.GetConfig()
+	if errors.Is(err, os.ErrNotExist) {
+		config, err = &domain.Config{APIBaseURL: domain.ApiBaseUrl}, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if profile == "" {
+		profile = config.CurrentProfile
+	}
+	if settings, ok := config.Profiles[profile]; ok {
+		settings.Token = token
+		config.Profiles[profile] = settings
+		return f.save(config)
+	}
+	if profile != "" && profile != DefaultProfile {
+		return fmt.Errorf("%w: %s", errProfileNotFound, profile)
+	}
+
+	h := host.FromAPIBaseURL(config.APIBaseURL)
+	if config.Host != "" {
+		h = host.New(config.Host)
+	}
+	name := h.Name
+	for key := range config.Hosts {
+		if host.New(key).Name == h.Name {
+			name = key
+		}
+	}
+	if _, ok := config.Hosts[name]; ok || h.IsEnterprise() {
+		if config.Hosts == nil {
+			config.Hosts = map[string]domain.HostConfig{}
+		}
+		settings := config.Hosts[name]
+		settings.Token = token
+		config.Hosts[name] = settings
+		return f.save(config)
+	}
+
+	config.Token = token
+	return f.save(config)
+}
+
+// RemoveToken removes the token commands of the profile, or of the current
+// one when profile is empty, authenticate with. The token may come from the
+// profile, the settings of its host or the top level, so each is removed in
+// that order until the profile resolves no token. It returns where tokens
+// were removed from, nothing when there was none.
+func (f *Feature) RemoveToken(profile string) ([]string, error) {
+	config, err := f.GetConfig()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for {
+		resolved := *config
+		if err = application.ApplyProfile(&resolved, profile); err != nil {
+			return nil, err
+		}
+		if resolved.Token == "" {
+			break
+		}
+
+		if settings := config.Profiles[resolved.CurrentProfile]; settings.Token != "" {
+			settings.Token = ""
+			config.Profiles[resolved.CurrentProfile] = settings
+			removed = append(removed, "profile "+resolved.CurrentProfile)
+			continue
+		}
+		key, ok := hostKey(config, host.FromAPIBaseURL(resolved.APIBaseURL))
+		if settings := config.Hosts[key]; ok && settings.Token != "" {
+			settings.Token = ""
+			config.Hosts[key] = settings
+			removed = append(removed, "host "+key)
+			continue
+		}
+		if config.Token == "" {
+			break
+		}
+		config.Token = ""
+		removed = append(removed, "top level")
+	}
+
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	return removed, f.save(config)
+}
+
+// hostKey is the key of the settings of h in the config.
+func hostKey(config *domain.Config, h host.Host) (string, bool) {
+	for key := range config.Hosts {
+		if host.New(key).Name == h.Name {
+			return key, true
+		}
+	}
+	return "", false
+}
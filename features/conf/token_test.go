@@ -0,0 +1,240 @@
+package conf
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"git-issues/application"
+	"git-issues/domain"
+)
+
+func TestSaveToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *domain.Config
+		profile string
+		check   func(t *testing.T, got domain.Config)
+		wantErr error
+	}{
+		{
+			name:   "top level",
+			config: &domain.Config{Token: "old", APIBaseURL: domain.ApiBaseUrl},
+			check: func(t *testing.T, got domain.Config) {
+				if got.Token != "new" {
+					t.Errorf("token = %q", got.Token)
+				}
+			},
+		},
+		{
+			name:   "enterprise host",
+			config: &domain.Config{Token: "dotcom", Host: "ghe.example.com", APIBaseURL: "https://ghe.example.com/api/v3"},
+			check: func(t *testing.T, got domain.Config) {
+				if got.Token != "dotcom" || got.Hosts["ghe.example.com"].Token != "new" {
+					t.Errorf("unexpected config %+v", got)
+				}
+			},
+		},
+		{
+			name: "existing host settings",
+			config: &domain.Config{APIBaseURL: domain.ApiBaseUrl, Hosts: map[string]domain.HostConfig{
+				"https://github.com": {Token: "old", CACert: "ca.pem"},
+			}},
+			check: func(t *testing.T, got domain.Config) {
+				if s := got.Hosts["https://github.com"]; s.Token != "new" || s.CACert != "ca.pem" || got.Token != "" {
+					t.Errorf("unexpected config %+v", got)
+				}
+			},
+		},
+		{
+			name: "current profile",
+			config: &domain.Config{Token: "top", CurrentProfile: "work", Profiles: map[string]domain.Profile{
+				"work": {Owner: "o"},
+			}},
+			check: func(t *testing.T, got domain.Config) {
+				if p := got.Profiles["work"]; p.Token != "new" || p.Owner != "o" || got.Token != "top" {
+					t.Errorf("unexpected config %+v", got)
+				}
+			},
+		},
+		{
+			name:    "named profile",
+			config:  &domain.Config{Profiles: map[string]domain.Profile{"work": {}, "oss": {}}},
+			profile: "oss",
+			check: func(t *testing.T, got domain.Config) {
+				if got.Profiles["oss"].Token != "new" || got.Profiles["work"].Token != "" {
+					t.Errorf("unexpected config %+v", got)
+				}
+			},
+		},
+		{
+			name: "default profile",
+			config: &domain.Config{Token: "top", APIBaseURL: domain.ApiBaseUrl, CurrentProfile: "work", Profiles: map[string]domain.Profile{
+				"work": {Token: "own"},
+			}},
+			profile: DefaultProfile,
+			check: func(t *testing.T, got domain.Config) {
+				if got.Token != "new" || got.Profiles["work"].Token != "own" {
+					t.Errorf("unexpected config %+v", got)
+				}
+			},
+		},
+		{
+			name:    "unknown profile",
+			config:  &domain.Config{},
+			profile: "home",
+			wantErr: errProfileNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written []byte
+			f := New()
+			f.config = tt.config
+			f.writeFile = func(filename string, data []byte, perm os.FileMode) error {
+				written = data
+				return nil
+			}
+
+			err := f.SaveToken(tt.profile, "new")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			var got domain.Config
+			if err = json.Unmarshal(written, &got); err != nil {
+				t.Fatalf("invalid config written: %v", err)
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestSaveTokenWithoutConfig(t *testing.T) {
+	_ = os.Remove(domain.ConfigFile)
+
+	var written []byte
+	f := New()
+	f.writeFile = func(filename string, data []byte, perm os.FileMode) error {
+		written = data
+		return nil
+	}
+
+	if err := f.SaveToken("", "new"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got domain.Config
+	if err := json.Unmarshal(written, &got); err != nil {
+		t.Fatalf("invalid config written: %v", err)
+	}
+	if got.Token != "new" || got.APIBaseURL != domain.ApiBaseUrl {
+		t.Errorf("unexpected config %+v", got)
+	}
+}
+
+func TestRemoveToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *domain.Config
+		profile     string
+		wantRemoved []string
+		check       func(t *testing.T, got domain.Config)
+		wantErr     error
+	}{
+		{
+			name:        "top level",
+			config:      &domain.Config{Token: "old", APIBaseURL: domain.ApiBaseUrl},
+			wantRemoved: []string{"top level"},
+			check: func(t *testing.T, got domain.Config) {
+				if got.Token != "" {
+					t.Errorf("token = %q", got.Token)
+				}
+			},
+		},
+		{
+			name: "profile falling back to the top level",
+			config: &domain.Config{Token: "top", APIBaseURL: domain.ApiBaseUrl, CurrentProfile: "work", Profiles: map[string]domain.Profile{
+				"work": {Owner: "o", Token: "own"},
+			}},
+			wantRemoved: []string{"profile work", "top level"},
+			check: func(t *testing.T, got domain.Config) {
+				if got.Token != "" || got.Profiles["work"].Token != "" || got.Profiles["work"].Owner != "o" {
+					t.Errorf("unexpected config %+v", got)
+				}
+			},
+		},
+		{
+			name: "profile on a host with settings keeps the top level token",
+			config: &domain.Config{Token: "dotcom", APIBaseURL: domain.ApiBaseUrl,
+				Profiles: map[string]domain.Profile{"ghe": {Host: "ghe.example.com"}},
+				Hosts:    map[string]domain.HostConfig{"https://GHE.example.com": {Token: "ghe", CACert: "ca.pem"}},
+			},
+			profile:     "ghe",
+			wantRemoved: []string{"host https://GHE.example.com"},
+			check: func(t *testing.T, got domain.Config) {
+				if s := got.Hosts["https://GHE.example.com"]; s.Token != "" || s.CACert != "ca.pem" || got.Token != "dotcom" {
+					t.Errorf("unexpected config %+v", got)
+				}
+			},
+		},
+		{
+			name: "default profile",
+			config: &domain.Config{Token: "top", APIBaseURL: domain.ApiBaseUrl, CurrentProfile: "work", Profiles: map[string]domain.Profile{
+				"work": {Token: "own"},
+			}},
+			profile:     DefaultProfile,
+			wantRemoved: []string{"top level"},
+			check: func(t *testing.T, got domain.Config) {
+				if got.Token != "" || got.Profiles["work"].Token != "own" || got.CurrentProfile != "work" {
+					t.Errorf("unexpected config %+v", got)
+				}
+			},
+		},
+		{
+			name:   "no token",
+			config: &domain.Config{APIBaseURL: domain.ApiBaseUrl},
+		},
+		{
+			name:    "unknown profile",
+			config:  &domain.Config{},
+			profile: "home",
+			wantErr: application.ErrProfileNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written []byte
+			f := New()
+			f.config = tt.config
+			f.writeFile = func(filename string, data []byte, perm os.FileMode) error {
+				written = data
+				return nil
+			}
+
+			removed, err := f.RemoveToken(tt.profile)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("removed %q want %q", removed, tt.wantRemoved)
+			}
+			if tt.check == nil {
+				if written != nil {
+					t.Errorf("config written without a token to remove")
+				}
+				return
+			}
+			var got domain.Config
+			if err = json.Unmarshal(written, &got); err != nil {
+				t.Fatalf("invalid config written: %v", err)
+			}
+			tt.check(t, got)
+		})
+	}
+}
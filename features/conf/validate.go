@@ -0,0 +1,145 @@
+package conf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"git-issues/domain"
+	"git-issues/features/auth"
+	"git-issues/service/host"
+)
+
+// field is a group of values Init asks for together.
+type field int
+
+const (
+	fieldHost field = iota
+	fieldToken
+	fieldRepo
+	fieldEditor
+)
+
+type checkLevel string
+
+const (
+	levelOK      checkLevel = "ok"
+	levelWarning checkLevel = "warning"
+	levelError   checkLevel = "error"
+)
+
+type check struct {
+	level   checkLevel
+	message string
+	// field is the value to enter again when the check is an error.
+	field field
+}
+
+type validation struct {
+	checks   []check
+	problems []field
+}
+
+type repoAccess struct {
+	FullName    string `json:"full_name"`
+	HasIssues   bool   `json:"has_issues"`
+	Permissions struct {
+		Push bool `json:"push"`
+	} `json:"permissions"`
+}
+
+type userLogin struct {
+	Login string `json:"login"`
+}
+
+// validate checks the token with /user and the repository with
+// /repos/{owner}/{repo}. No push access and missing scopes are warnings, as
+// reading issues still works.
+func (f *Feature) validate(values answers) validation {
+	var result validation
+	h := host.New(values.host)
+	githubClient := f.newClient(&domain.Config{
+		Token:      values.token,
+		APIBaseURL: h.REST,
+		CACert:     values.caCert,
+	})
+
+	body, header, err := githubClient.MakeRequestHeader(http.MethodGet, h.REST+"/user")
+	if err != nil {
+		if errors.Is(err, domain.ErrApi) {
+			result.add(levelError, fieldToken, "the token was rejected by %s: %v", h.Name, err)
+		} else {
+			result.add(levelError, fieldHost, "could not reach %s: %v", h.Name, err)
+		}
+		// without a valid token the repository can not be checked
+		return result
+	}
+
+	var user userLogin
+	if err = json.Unmarshal(body, &user); err != nil {
+		result.add(levelError, fieldHost, "unexpected answer from %s: %v", h.Name, err)
+		return result
+	}
+	result.add(levelOK, fieldToken, "logged in to %s as %s", h.Name, user.Login)
+
+	// fine-grained tokens report no scopes
+	scopes, reported := header["X-Oauth-Scopes"]
+	if missing := auth.MissingScopes(auth.ParseScopes(strings.Join(scopes, ",")), auth.DefaultScopes); reported && len(missing) > 0 {
+		result.add(levelWarning, fieldToken, "the token is missing the scopes: %s", strings.Join(missing, ", "))
+	}
+
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", h.REST, values.owner, values.repo)
+	body, _, err = githubClient.MakeRequestHeader(http.MethodGet, repoURL)
+	if err != nil {
+		result.add(levelError, fieldRepo, "could not find the repository %s/%s: %v", values.owner, values.repo, err)
+		return result
+	}
+	var repo repoAccess
+	if err = json.Unmarshal(body, &repo); err != nil {
+		result.add(levelError, fieldRepo, "unexpected answer for %s/%s: %v", values.owner, values.repo, err)
+		return result
+	}
+
+	if !repo.HasIssues {
+		result.add(levelError, fieldRepo, "issues are disabled on %s", repo.FullName)
+		return result
+	}
+	result.add(levelOK, fieldRepo, "issues are enabled on %s", repo.FullName)
+	if !repo.Permissions.Push {
+		result.add(levelWarning, fieldRepo, "no push access to %s: editing and closing issues of others will fail", repo.FullName)
+	}
+	return result
+}
+
+func (v *validation) add(level checkLevel, fld field, format string, args ...interface{}) {
+	v.checks = append(v.checks, check{level: level, message: fmt.Sprintf(format, args...), field: fld})
+	if level == levelError {
+		v.problems = append(v.problems, fld)
+	}
+}
+
+// fields are the values to ask again, in the order Init asks them.
+func (v validation) fields() []field {
+	var fields []field
+	for _, fld := range []field{fieldHost, fieldToken, fieldRepo, fieldEditor} {
+		for _, problem := range v.problems {
+			if problem == fld {
+				fields = append(fields, fld)
+				break
+			}
+		}
+	}
+	return fields
+}
+
+func (v validation) print(w io.Writer) error {
+	for _, c := range v.checks {
+		if _, err := fmt.Fprintf(w, "%-7s  %s\n", c.level, c.message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package doctor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"git-issues/application"
+	"git-issues/domain"
+	"git-issues/features/auth"
+	"git-issues/service/client"
+	"git-issues/service/editor"
+	"git-issues/service/git"
+	"git-issues/service/host"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+const (
+	CheckConfig = "config"
+	CheckToken  = "token"
+	CheckAPI    = "api"
+	CheckEditor = "editor"
+	CheckGit    = "git"
+	CheckClock  = "clock"
+)
+
+const (
+	// rateLimitHeadroom is the share of the core rate limit below which the
+	// api check warns.
+	rateLimitHeadroom = 0.1
+	// maxClockSkew is the difference to the server clock the check accepts.
+	// GitHub App tokens are signed with an issue time a minute in the past.
+	maxClockSkew = time.Minute
+)
+
+// Check is one diagnostic. Hint says how to fix a warning or failure.
+type Check struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// System describes where the checks ran, for bug reports.
+type System struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+	Go   string `json:"go"`
+}
+
+// Report holds the checks in the order they ran.
+type Report struct {
+	System System  `json:"system"`
+	Checks []Check `json:"checks"`
+}
+
+// Doctor diagnoses the config, token, network, editor and git setup.
+type Doctor interface {
+	Run() *Report
+}
+
+type Feature struct {
+	configFile string
+	profile    string
+	newClient  func(config *domain.Config) client.HeaderClient
+	lookPath   func(file string) (string, error)
+	git        git.Git
+	now        func() time.Time
+}
+
+type rateLimit struct {
+	Resources struct {
+		Core struct {
+			Limit     int   `json:"limit"`
+			Remaining int   `json:"remaining"`
+			Reset     int64 `json:"reset"`
+		} `json:"core"`
+	} `json:"resources"`
+}
+
+func New(configFile string, profile string) *Feature {
+	return &Feature{
+		configFile: configFile,
+		profile:    profile,
+		newClient:  func(config *domain.Config) client.HeaderClient { return client.New(config) },
+		lookPath:   exec.LookPath,
+		git:        git.New(""),
+		now:        time.Now,
+	}
+}
+
+// Run performs every check. The later checks still run when the config is
+// missing, on the defaults, so one run shows everything that is wrong.
+func (f *Feature) Run() *Report {
+	report := &Report{System: System{OS: runtime.GOOS, Arch: runtime.GOARCH, Go: runtime.Version()}}
+
+	config, check := f.checkConfig()
+	report.add(check)
+
+	githubClient := f.newClient(config)
+	report.add(f.checkToken(config, githubClient))
+
+	serverDate, check := f.checkAPI(config, githubClient)
+	report.add(check)
+
+	report.add(f.checkEditor(config))
+	report.add(f.checkGit(config))
+	report.add(f.checkClock(serverDate))
+	return report
+}
+
+// Count returns how many checks ended with the status.
+func (r *Report) Count(status Status) int {
+	count := 0
+	for _, c := range r.Checks {
+		if c.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *Report) add(c Check) {
+	r.Checks = append(r.Checks, c)
+}
+
+func (f *Feature) checkConfig() (*domain.Config, Check) {
+	defaults := &domain.Config{APIBaseURL: domain.ApiBaseUrl}
+	path, err := filepath.Abs(f.configFile)
+	if err != nil {
+		path = f.configFile
+	}
+
+	config, err := application.LoadConfig(f.configFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return defaults, fail(CheckConfig, fmt.Sprintf("no config file at %s", path),
+			"run 'ghissues init', or 'ghissues auth login' to start with the token")
+	case err != nil:
+		return defaults, fail(CheckConfig, fmt.Sprintf("could not read %s: %v", path, err),
+			"fix the JSON of the file or run 'ghissues init' again")
+	}
+
+	if err = application.ApplyProfile(config, f.profile); err != nil {
+		return config, fail(CheckConfig, err.Error(), "see 'ghissues config list' for the profiles")
+	}
+	if config.APIBaseURL == "" {
+		config.APIBaseURL = domain.ApiBaseUrl
+	}
+
+	message := fmt.Sprintf("loaded %s", path)
+	if config.CurrentProfile != "" {
+		message += fmt.Sprintf(" with profile %s", config.CurrentProfile)
+	}
+	if config.Owner == "" || config.Repo == "" {
+		return config, warn(CheckConfig, message+", but owner or repo is empty",
+			"set owner and repo with 'ghissues init' or in the profile")
+	}
+	return config, pass(CheckConfig, fmt.Sprintf("%s for %s/%s", message, config.Owner, config.Repo))
+}
+
+func (f *Feature) checkToken(config *domain.Config, githubClient client.HeaderClient) Check {
+	if config.AppID != 0 {
+		// the installation token is checked by the api request
+		return pass(CheckToken, fmt.Sprintf("authenticating as GitHub App %d, installation %d", config.AppID, config.AppInstallationID))
+	}
+	if config.Token == "" {
+		return fail(CheckToken, "no token in the config", "run 'ghissues auth login' or 'ghissues init'")
+	}
+
+	h := host.FromAPIBaseURL(config.APIBaseURL)
+	body, header, err := githubClient.MakeRequestHeader(http.MethodGet, h.REST+"/user")
+	if errors.Is(err, domain.ErrApi) {
+		return fail(CheckToken, fmt.Sprintf("the token was rejected: %v", err),
+			"the token expired or was revoked, run 'ghissues auth login' or create a new one")
+	}
+	if err != nil {
+		return fail(CheckToken, fmt.Sprintf("could not check the token: %v", err), "see the api check")
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err = json.Unmarshal(body, &user); err != nil {
+		return fail(CheckToken, fmt.Sprintf("unexpected answer from /user: %v", err), "check api_base_url in the config")
+	}
+
+	message := fmt.Sprintf("valid for %s on %s", user.Login, h.Name)
+	if _, ok := header["X-Oauth-Scopes"]; !ok {
+		return pass(CheckToken, message+", scopes not reported (fine-grained token)")
+	}
+	scopes := auth.ParseScopes(header.Get("X-OAuth-Scopes"))
+	if missing := auth.MissingScopes(scopes, auth.DefaultScopes); len(missing) > 0 {
+		return warn(CheckToken, fmt.Sprintf("%s, missing scopes: %s", message, strings.Join(missing, ", ")),
+			"run 'ghissues auth login' or add the scopes to the token")
+	}
+	return pass(CheckToken, fmt.Sprintf("%s, scopes: %s", message, strings.Join(scopes, ", ")))
+}
+
+// checkAPI requests the rate limit, which does not count against it, and
+// returns the server date for the clock check.
+func (f *Feature) checkAPI(config *domain.Config, githubClient client.HeaderClient) (time.Time, Check) {
+	url := host.FromAPIBaseURL(config.APIBaseURL).REST + "/rate_limit"
+	body, header, err := githubClient.MakeRequestHeader(http.MethodGet, url)
+	if err != nil {
+		return time.Time{}, fail(CheckAPI, fmt.Sprintf("could not reach %s: %v", url, err),
+			"check the network and proxy, the host or api_base_url, and ca_cert for Enterprise hosts")
+	}
+	serverDate, _ := http.ParseTime(header.Get("Date"))
+
+	var limit rateLimit
+	if err = json.Unmarshal(body, &limit); err != nil || limit.Resources.Core.Limit == 0 {
+		// Enterprise Server may have rate limiting disabled
+		return serverDate, pass(CheckAPI, fmt.Sprintf("%s reachable, no rate limit reported", url))
+	}
+
+	core := limit.Resources.Core
+	reset := time.Unix(core.Reset, 0).Local().Format("15:04")
+	message := fmt.Sprintf("reachable, %d of %d requests left until %s", core.Remaining, core.Limit, reset)
+	if float64(core.Remaining) < float64(core.Limit)*rateLimitHeadroom {
+		return serverDate, warn(CheckAPI, message, "wait for the reset, or authenticate as a GitHub App for a higher limit")
+	}
+	return serverDate, pass(CheckAPI, message)
+}
+
+func (f *Feature) checkEditor(config *domain.Config) Check {
+	command := editor.New(config).Command()
+	path, err := f.lookPath(command)
+	if err != nil {
+		return fail(CheckEditor, fmt.Sprintf("%s was not found on PATH", command),
+			"set editor in the config or $EDITOR to a command on PATH (Windows: notepad or code)")
+	}
+	return pass(CheckEditor, fmt.Sprintf("%s resolves to %s", command, path))
+}
+
+func (f *Feature) checkGit(config *domain.Config) Check {
+	out, err := f.git.Remotes()
+	if err != nil {
+		return warn(CheckGit, "not in a git repository or git is not installed",
+			"run ghissues inside a clone of the repository to use the git integration")
+	}
+
+	remotes := ParseRemotes(out)
+	if len(remotes) == 0 {
+		return warn(CheckGit, "the git repository has no remotes", "add the GitHub repository with 'git remote add origin <url>'")
+	}
+	want := strings.ToLower(config.Owner + "/" + config.Repo)
+	for _, r := range remotes {
+		if strings.ToLower(r.Repo) == want {
+			return pass(CheckGit, fmt.Sprintf("remote %s points to %s", r.Name, r.Repo))
+		}
+	}
+	return warn(CheckGit, fmt.Sprintf("no remote points to %s/%s (%s is %s)", config.Owner, config.Repo, remotes[0].Name, remotes[0].Repo),
+		"use a profile for this repository with --profile or 'ghissues config use'")
+}
+
+func (f *Feature) checkClock(serverDate time.Time) Check {
+	if serverDate.IsZero() {
+		return warn(CheckClock, "could not compare with the server clock", "see the api check")
+	}
+	skew := f.now().Sub(serverDate).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew {
+		return warn(CheckClock, fmt.Sprintf("the local clock is %s off the server", skew),
+			"sync the clock with NTP, GitHub App tokens are refused with a skewed clock")
+	}
+	return pass(CheckClock, fmt.Sprintf("in sync with the server (%s off)", skew))
+}
+
+func pass(name, message string) Check {
+	return Check{Name: name, Status: StatusPass, Message: oneLine(message)}
+}
+
+func warn(name, message, hint string) Check {
+	return Check{Name: name, Status: StatusWarn, Message: oneLine(message), Hint: hint}
+}
+
+func fail(name, message, hint string) Check {
+	return Check{Name: name, Status: StatusFail, Message: oneLine(message), Hint: hint}
+}
+
+// oneLine joins the lines of wrapped errors, so each check stays one line.
+func oneLine(message string) string {
+	return strings.ReplaceAll(strings.TrimSpace(message), "\n", ": ")
+}
@@ -0,0 +1,169 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+	"git-issues/testdata/stubs"
+)
+
+var serverNow = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+// api answers /user and /rate_limit like github.com does.
+type api struct {
+	scopes    string
+	remaining int
+	userErr   error
+	rateErr   error
+}
+
+func (a api) client(config *domain.Config) client.HeaderClient {
+	return &stubs.ClientStub{
+		MakeRequestHeaderFunc: func(method, url string) ([]byte, http.Header, error) {
+			header := http.Header{}
+			header.Set("Date", serverNow.Format(http.TimeFormat))
+			if strings.HasSuffix(url, "/user") {
+				if a.userErr != nil {
+					return nil, nil, a.userErr
+				}
+				if a.scopes != "" {
+					header.Set("X-OAuth-Scopes", a.scopes)
+				}
+				return []byte(`{"login":"octocat"}`), header, nil
+			}
+			if a.rateErr != nil {
+				return nil, nil, a.rateErr
+			}
+			body := fmt.Sprintf(`{"resources":{"core":{"limit":5000,"remaining":%d,"reset":1773147600}}}`, a.remaining)
+			return []byte(body), header, nil
+		},
+	}
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), ".ghissuescli")
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return file
+}
+
+func TestRun(t *testing.T) {
+	const validConfig = `{"token":"t","owner":"octo","repo":"hello","editor":"vim","api_base_url":"https://api.github.com"}`
+	const origin = "origin\tgit@github.com:octo/hello.git (fetch)\norigin\tgit@github.com:octo/hello.git (push)\n"
+
+	tests := []struct {
+		name    string
+		config  string
+		profile string
+		api     api
+		remotes string
+		gitErr  error
+		editor  bool
+		now     time.Time
+		want    map[string]Status
+	}{
+		{
+			name:    "all good",
+			config:  validConfig,
+			api:     api{scopes: "repo, project", remaining: 4990},
+			remotes: origin,
+			editor:  true,
+			now:     serverNow.Add(2 * time.Second),
+			want: map[string]Status{CheckConfig: StatusPass, CheckToken: StatusPass, CheckAPI: StatusPass,
+				CheckEditor: StatusPass, CheckGit: StatusPass, CheckClock: StatusPass},
+		},
+		{
+			name:    "warnings",
+			config:  validConfig,
+			api:     api{scopes: "public_repo", remaining: 12},
+			remotes: "upstream\thttps://github.com/other/repo.git (fetch)\n",
+			editor:  true,
+			now:     serverNow.Add(-3 * time.Minute),
+			want: map[string]Status{CheckConfig: StatusPass, CheckToken: StatusWarn, CheckAPI: StatusWarn,
+				CheckEditor: StatusPass, CheckGit: StatusWarn, CheckClock: StatusWarn},
+		},
+		{
+			name:   "missing config",
+			api:    api{rateErr: errors.Join(errors.New("dial tcp: no such host"), domain.ErrRequest)},
+			gitErr: errors.New("exit status 128"),
+			now:    serverNow,
+			want: map[string]Status{CheckConfig: StatusFail, CheckToken: StatusFail, CheckAPI: StatusFail,
+				CheckEditor: StatusFail, CheckGit: StatusWarn, CheckClock: StatusWarn},
+		},
+		{
+			name:    "invalid json and rejected token",
+			config:  `{"token": `,
+			api:     api{userErr: errors.Join(errors.New("401 Bad credentials"), domain.ErrApi), remaining: 4000},
+			remotes: origin,
+			editor:  true,
+			now:     serverNow,
+			want:    map[string]Status{CheckConfig: StatusFail, CheckToken: StatusFail, CheckAPI: StatusPass},
+		},
+		{
+			name:    "unknown profile",
+			config:  validConfig,
+			profile: "work",
+			api:     api{userErr: errors.Join(errors.New("401 Bad credentials"), domain.ErrApi)},
+			remotes: origin,
+			editor:  true,
+			now:     serverNow,
+			want:    map[string]Status{CheckConfig: StatusFail, CheckToken: StatusFail},
+		},
+		{
+			name:    "fine-grained token",
+			config:  validConfig,
+			api:     api{remaining: 5000},
+			remotes: origin,
+			editor:  true,
+			now:     serverNow,
+			want:    map[string]Status{CheckToken: StatusPass, CheckAPI: StatusPass},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "missing")
+			if tt.config != "" {
+				file = writeConfig(t, tt.config)
+			}
+			f := New(file, tt.profile)
+			f.newClient = tt.api.client
+			f.lookPath = func(command string) (string, error) {
+				if !tt.editor {
+					return "", errors.New("executable file not found in $PATH")
+				}
+				return "/usr/bin/" + command, nil
+			}
+			f.git = &stubs.GitStub{RemotesFunc: func() (string, error) { return tt.remotes, tt.gitErr }}
+			f.now = func() time.Time { return tt.now }
+
+			report := f.Run()
+
+			if len(report.Checks) != 6 {
+				t.Fatalf("got %d checks, want 6", len(report.Checks))
+			}
+			for _, c := range report.Checks {
+				want, ok := tt.want[c.Name]
+				if !ok {
+					continue
+				}
+				if c.Status != want {
+					t.Errorf("%s: got %s (%s), want %s", c.Name, c.Status, c.Message, want)
+				}
+				if c.Status != StatusPass && c.Hint == "" {
+					t.Errorf("%s: %s without a hint", c.Name, c.Status)
+				}
+			}
+		})
+	}
+}
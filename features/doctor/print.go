@@ -0,0 +1,43 @@
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"git-issues/features/issue"
+)
+
+const (
+	strCheckFormat   = "%-4s  %-6s  %s\n"
+	strHintFormat    = "              hint: %s\n"
+	strSummaryFormat = "\n%d passed, %d warnings, %d failed\n"
+)
+
+// Print writes the report as text, one line per check with its hint below,
+// or as indented JSON for bug reports.
+func Print(w io.Writer, report *Report, format string) error {
+	switch format {
+	case issue.FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case issue.FormatText, "":
+	default:
+		return fmt.Errorf("unknown format %q, use text or json", format)
+	}
+
+	for _, c := range report.Checks {
+		if _, err := fmt.Fprintf(w, strCheckFormat, c.Status, c.Name, c.Message); err != nil {
+			return err
+		}
+		if c.Hint == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, strHintFormat, c.Hint); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, strSummaryFormat, report.Count(StatusPass), report.Count(StatusWarn), report.Count(StatusFail))
+	return err
+}
@@ -0,0 +1,57 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func testReport() *Report {
+	return &Report{
+		System: System{OS: "linux", Arch: "amd64", Go: "go1.22"},
+		Checks: []Check{
+			{Name: CheckConfig, Status: StatusPass, Message: "loaded /home/u/.ghissuescli for octo/hello"},
+			{Name: CheckToken, Status: StatusWarn, Message: "missing scopes: project", Hint: "run 'ghissues auth login'"},
+			{Name: CheckEditor, Status: StatusFail, Message: "vim was not found on PATH", Hint: "set editor"},
+		},
+	}
+}
+
+func TestPrintText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Print(&buf, testReport(), "text"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "pass  config  loaded /home/u/.ghissuescli for octo/hello\n" +
+		"warn  token   missing scopes: project\n" +
+		"              hint: run 'ghissues auth login'\n" +
+		"fail  editor  vim was not found on PATH\n" +
+		"              hint: set editor\n" +
+		"\n1 passed, 1 warnings, 1 failed\n"
+	if buf.String() != want {
+		t.Errorf("got %q want %q", buf.String(), want)
+	}
+}
+
+func TestPrintJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Print(&buf, testReport(), "json"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got Report
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if !reflect.DeepEqual(&got, testReport()) {
+		t.Errorf("got %+v want %+v", got, testReport())
+	}
+}
+
+func TestPrintUnknownFormat(t *testing.T) {
+	if err := Print(&bytes.Buffer{}, testReport(), "csv"); err == nil {
+		t.Errorf("expected error for csv")
+	}
+}
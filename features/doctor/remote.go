@@ -0,0 +1,58 @@
+package doctor
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Remote is a git remote and the owner/repo its url points to.
+type Remote struct {
+	Name string
+	URL  string
+	Repo string
+}
+
+// ParseRemotes reads the output of git remote -v. Remotes whose url is not
+// a hosted repository, like a local path, are left out.
+func ParseRemotes(out string) []Remote {
+	var remotes []Remote
+	seen := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || seen[fields[0]] {
+			continue
+		}
+		repo, ok := RemoteRepo(fields[1])
+		if !ok {
+			continue
+		}
+		seen[fields[0]] = true
+		remotes = append(remotes, Remote{Name: fields[0], URL: fields[1], Repo: repo})
+	}
+	return remotes
+}
+
+// RemoteRepo returns owner/repo from an https, ssh or scp-like git url.
+func RemoteRepo(remote string) (string, bool) {
+	var path string
+	switch {
+	case strings.Contains(remote, "://"):
+		parsed, err := url.Parse(remote)
+		if err != nil || parsed.Host == "" {
+			return "", false
+		}
+		path = parsed.Path
+	case strings.Contains(remote, ":"):
+		// git@github.com:owner/repo.git
+		path = remote[strings.Index(remote, ":")+1:]
+	default:
+		return "", false
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return "", false
+	}
+	return parts[len(parts)-2] + "/" + parts[len(parts)-1], true
+}
@@ -0,0 +1,49 @@
+package doctor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemoteRepo(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+		ok     bool
+	}{
+		{remote: "https://github.com/octo/hello.git", want: "octo/hello", ok: true},
+		{remote: "https://github.com/octo/hello", want: "octo/hello", ok: true},
+		{remote: "git@github.com:octo/hello.git", want: "octo/hello", ok: true},
+		{remote: "ssh://git@ghe.example.com:2222/octo/hello.git", want: "octo/hello", ok: true},
+		{remote: "https://ghe.example.com/octo/hello/", want: "octo/hello", ok: true},
+		{remote: "/srv/git/hello.git"},
+		{remote: "file:///srv/git/hello.git"},
+		{remote: "https://github.com/octo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remote, func(t *testing.T) {
+			got, ok := RemoteRepo(tt.remote)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("got %q, %v want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseRemotes(t *testing.T) {
+	out := "origin\tgit@github.com:octo/hello.git (fetch)\n" +
+		"origin\tgit@github.com:octo/hello.git (push)\n" +
+		"local\t/srv/git/hello.git (fetch)\n" +
+		"upstream\thttps://github.com/main/hello (fetch)\n"
+
+	got := ParseRemotes(out)
+
+	want := []Remote{
+		{Name: "origin", URL: "git@github.com:octo/hello.git", Repo: "octo/hello"},
+		{Name: "upstream", URL: "https://github.com/main/hello", Repo: "main/hello"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
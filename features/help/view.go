@@ -6,22 +6,105 @@ func PrintHelp() {
 	fmt.Println(`GitHub Issues CLI - Application to manage GitHub issues
 
 Usage:
-  ghissues <comand> [args]
+  ghissues [--profile <name>] <comand> [args]
 
 Commands:
-  init       conf the app
-  create     Create a new issue
-  list       List all issues
-  view <n>   View the issue number n
+  init       conf the app, checking the token and repository before saving
+  auth       Log in with the browser (login), show the token (status), logout
+  config     Manage profiles: list, use <profile|default>
+  doctor     Check config, token, network, editor, git remote and clock
+             (--format json for bug reports)
+  completion bash|zsh|fish
+             Print the shell completion script
+  create     Create a new issue (--project <name> adds it to a project);
+             similar issues are shown first (--no-duplicate-check skips it);
+             --web opens the form in the browser, with --title, --body,
+             --label and --template, which need --web or --print-url
+  list       List the open issues (--format text|json|csv,
+             --sort reactions|updated|created, --state open|closed|all,
+             --assignee <login|@me>, --label <a,b>, --query <saved query>,
+             --repos owner/repo,... lists several repositories together,
+             --web opens the list in the browser)
+  search <query>
+             List the issues matching a GitHub search, like "is:open label:bug"
+  query      Saved searches: save <name> '<query>', run <name>, list, delete
+  alias      Command shortcuts: set <name> '<command line>' ($1, $@ take the
+             arguments), list, delete <name>
+  view <n>   View the issue number n (--raw prints the markdown unrendered,
+             --tree prints its sub-issues and task list as a tree,
+             --web opens it in the browser)
+             list and view page long output; --no-pager disables it
+  browse [<n>] Open the issues, or the issue n, in the browser ($BROWSER);
+             browse, view, list and create print the url with --print-url
+  history <n> Show who labeled, assigned, closed, renamed or referenced
+             the issue and when (--format text|json|csv)
   update <n> Update the issue number n
   close <n>  close the issue number n
+  lock <n>   Lock the conversation (--reason off-topic|too-heated|resolved|spam)
+  unlock <n> Unlock the conversation
+  pin <n>    Pin the issue to the repository
+  unpin <n>  Unpin the issue
+  react <n> <reaction>
+             React to the issue, or to a comment with --comment <id|url>
+             (+1, -1, laugh, hooray, confused, heart, rocket, eyes)
+  subissue   Manage sub-issues: add <parent> <child>, remove <parent> <child>
+  progress <n> Show how many sub-issues and tasks of the issue are done
+  start <n>  Create and check out the issue branch and assign the issue to you
+             (--in-progress adds the in progress label)
+  current    Show the issue of the checked out branch
+  hooks install
+             Install a commit hook appending Refs #n from the branch (--force)
+  commits <n> List the local commits referencing the issue (--all branches)
+  verify-closes
+             List the issues the branch closes when merged (--base <branch>)
+  changelog  Release notes of the issues closed --since <tag|date>
+             (--until <tag|date>, --template <file>, --title <text>)
+  transfer <n> <owner/repo>
+             Move the issue to another repository (--query "<search>" moves
+             every match after asking, --yes skips the question)
+  watch      Print new issues, comments, state and label changes as they
+             happen (--format text|ndjson, --events opened,commented,...,
+             --label, --assignee, --actor, --interval, --cursor <file>)
+  export     Write every issue with its comments to an archive
+             (--format json|markdown, --output <file|dir>)
+  import <archive.json|dir>
+             Recreate the issues of an archive or markdown export here
+             (--label-map old=new, --milestone-map old=new, --mapping <file>,
+             --dry-run)
+  project    Manage Projects: list, add <n>, set <n> --field F=V, items
+  tui        Browse and triage issues in a full-screen view
   help       Display Help
 
 Examples:
   ghissues init
+  ghissues auth login
+  source <(ghissues completion bash)
   ghissues create
   ghissues list
   ghissues view 123
+  ghissues browse 123
+  ghissues list --web --label bug --sort reactions
+  ghissues create --web --title "Crash on start" --template bug_report.md
+  ghissues history 123 --format csv
+  ghissues react 123 +1
+  ghissues list --sort reactions
+  ghissues alias set mine 'list --assignee @me --state open --sort updated'
+  ghissues query save bugs "is:open label:bug"
+  ghissues list --query bugs
+  ghissues --profile work list --repos platform/api,platform/web
   ghissues update 123
-  ghissues close 123`)
+  ghissues close 123
+  ghissues lock 123 --reason too-heated
+  ghissues view 100 --tree
+  ghissues subissue add 100 123
+  ghissues start 123 --in-progress
+  ghissues changelog --since v1.2.0
+  ghissues transfer 123 platform/web
+  ghissues transfer --query "label:frontend is:open" platform/web
+  ghissues watch --events opened,commented --label bug
+  ghissues watch --format ndjson | jq -r .title
+  ghissues export --output issues.json
+  ghissues --profile new import issues.json --label-map bug=defect --dry-run
+  ghissues project set 123 --project Roadmap --field "Status=In Progress"
+  ghissues tui`)
 }
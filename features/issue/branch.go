@@ -0,0 +1,145 @@
+package issue
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"git-issues/domain"
+)
+
+// DefaultBranchTemplate names branches after the number and title of the
+// issue, like 12-fix-the-login-page.
+const DefaultBranchTemplate = "{{.Number}}-{{slug .Title}}"
+
+// maxSlugLength keeps branch names readable in prompts and lists.
+const maxSlugLength = 50
+
+// numberMarker stands for the issue number when the template is rendered to
+// find what comes before the number.
+const numberMarker = 987654321
+
+var reBranchNumber = regexp.MustCompile(`(?:^|[/_.-])(\d+)(?:[/_.-]|$)`)
+
+// BranchNamer maps issues to branch names and back.
+type BranchNamer interface {
+	BranchName(issue *domain.Issue) (string, error)
+	IssueNumber(branch string) (int, bool)
+}
+
+// TemplateBranch names branches with a text/template executed on the issue.
+// Besides the template builtins it has slug, which turns a title into
+// lower case words joined by dashes.
+type TemplateBranch struct {
+	tmpl *template.Template
+	// prefix is what the template renders before the number, when it does
+	// not depend on the issue.
+	prefix    string
+	hasPrefix bool
+}
+
+func NewBranchNamer(text string) (*TemplateBranch, error) {
+	if text == "" {
+		text = DefaultBranchTemplate
+	}
+	tmpl, err := template.New("branch").Funcs(template.FuncMap{"slug": Slug}).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.Join(errBranchTemplate, err)
+	}
+
+	namer := &TemplateBranch{tmpl: tmpl}
+	first, err1 := namer.render(&domain.Issue{Number: numberMarker})
+	second, err2 := namer.render(&domain.Issue{Number: numberMarker, Title: "a title"})
+	if err1 != nil || err2 != nil {
+		return nil, errors.Join(errBranchTemplate, err1, err2)
+	}
+	marker := strconv.Itoa(numberMarker)
+	if !strings.Contains(first, marker) {
+		return nil, errors.Join(errBranchTemplate, errBranchNumber)
+	}
+	namer.prefix = first[:strings.Index(first, marker)]
+	namer.hasPrefix = strings.HasPrefix(second, namer.prefix+marker)
+	return namer, nil
+}
+
+func (b *TemplateBranch) BranchName(issue *domain.Issue) (string, error) {
+	name, err := b.render(issue)
+	if err != nil {
+		return "", errors.Join(errBranchTemplate, err)
+	}
+	if name == "" || strings.ContainsAny(name, " \t~^:?*[\\") || strings.Contains(name, "..") {
+		return "", fmt.Errorf("%w: %q", errBranchName, name)
+	}
+	return name, nil
+}
+
+// IssueNumber reads the number back from a branch the template named. For
+// other branches it takes the first number standing alone between
+// separators, so feature/12-login and 12_login work too.
+func (b *TemplateBranch) IssueNumber(branch string) (int, bool) {
+	if b.hasPrefix && strings.HasPrefix(branch, b.prefix) {
+		rest := branch[len(b.prefix):]
+		end := strings.IndexFunc(rest, func(r rune) bool { return !unicode.IsDigit(r) })
+		if end == -1 {
+			end = len(rest)
+		}
+		if number, err := strconv.Atoi(rest[:end]); err == nil && number > 0 {
+			return number, true
+		}
+	}
+
+	match := reBranchNumber.FindStringSubmatch(branch)
+	if match == nil {
+		return 0, false
+	}
+	number, err := strconv.Atoi(match[1])
+	return number, err == nil && number > 0
+}
+
+func (b *TemplateBranch) render(issue *domain.Issue) (string, error) {
+	var name strings.Builder
+	if err := b.tmpl.Execute(&name, issue); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(name.String()), nil
+}
+
+// Slug lower cases the letters and digits of s and joins the words with
+// dashes, cut at a word boundary after maxSlugLength characters.
+func Slug(s string) string {
+	var words []string
+	var word strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			word.WriteRune(r)
+			continue
+		}
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+
+	slug := ""
+	for _, w := range words {
+		next := w
+		if slug != "" {
+			next = slug + "-" + w
+		}
+		if len(next) > maxSlugLength && slug != "" {
+			break
+		}
+		slug = next
+	}
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+	}
+	return slug
+}
@@ -0,0 +1,100 @@
+package issue
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"git-issues/domain"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "Fix the login page", want: "fix-the-login-page"},
+		{title: "  [Bug] Crash on `list --format json`!! ", want: "bug-crash-on-list-format-json"},
+		{title: "Ação inválida", want: "a-o-inv-lida"},
+		{title: "", want: ""},
+		{title: "A very long title that goes on and on about many details of the bug", want: "a-very-long-title-that-goes-on-and-on-about-many"},
+		{title: "Supercalifragilisticexpialidocious-and-more-than-fifty-chars", want: "supercalifragilisticexpialidocious-and-more-than"},
+		{title: strings.Repeat("x", 60), want: strings.Repeat("x", 50)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := Slug(tt.title); got != tt.want {
+				t.Errorf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBranchName(t *testing.T) {
+	issue := &domain.Issue{Number: 12, Title: "Fix the login page"}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  error
+	}{
+		{name: "default", want: "12-fix-the-login-page"},
+		{name: "prefix", template: "issue/{{.Number}}", want: "issue/12"},
+		{name: "lower title", template: "gh-{{.Number}}/{{.Title | slug}}", want: "gh-12/fix-the-login-page"},
+		{name: "title first", template: "{{slug .Title}}-{{.Number}}", want: "fix-the-login-page-12"},
+		{name: "no number", template: "{{slug .Title}}", wantErr: errBranchNumber},
+		{name: "parse error", template: "{{.Number", wantErr: errBranchTemplate},
+		{name: "unknown field", template: "{{.Nope}}", wantErr: errBranchTemplate},
+		{name: "spaces", template: "{{.Number}} {{.Title}}", wantErr: errBranchName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namer, err := NewBranchNamer(tt.template)
+			var got string
+			if err == nil {
+				got, err = namer.BranchName(issue)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueNumber(t *testing.T) {
+	tests := []struct {
+		template string
+		branch   string
+		want     int
+		ok       bool
+	}{
+		{branch: "12-fix-the-login-page", want: 12, ok: true},
+		{branch: "12", want: 12, ok: true},
+		{branch: "feature/34-login", want: 34, ok: true},
+		{branch: "fix_56_crash", want: 56, ok: true},
+		{branch: "main"},
+		{branch: "v2fix"},
+		{template: "issue/{{.Number}}", branch: "issue/78", want: 78, ok: true},
+		{template: "issue/{{.Number}}", branch: "release/1.2-issue", want: 1, ok: true},
+		{template: "{{slug .Title}}-{{.Number}}", branch: "fix-login-90", want: 90, ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template+" "+tt.branch, func(t *testing.T) {
+			namer, err := NewBranchNamer(tt.template)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, ok := namer.IssueNumber(tt.branch)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("got %d, %v want %d, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
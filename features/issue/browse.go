@@ -0,0 +1,152 @@
+package issue
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"git-issues/domain"
+	"git-issues/service/browser"
+	"git-issues/service/host"
+)
+
+type BrowseFeature struct {
+	config  *domain.Config
+	browser browser.Browser
+	writer  io.Writer
+
+	// PrintURL prints the urls instead of opening them.
+	PrintURL bool
+}
+
+// NewIssueForm prefills the new issue page. Template names a file of
+// .github/ISSUE_TEMPLATE, whose fields the others override.
+type NewIssueForm struct {
+	Title    string
+	Body     string
+	Labels   []string
+	Template string
+}
+
+// webSorts are the search qualifiers of ListSorts.
+var webSorts = map[string]string{
+	"reactions": "sort:reactions-+1-desc",
+	"updated":   "sort:updated-desc",
+	"created":   "sort:created-desc",
+}
+
+func NewBrowse(config *domain.Config, browser browser.Browser) *BrowseFeature {
+	return &BrowseFeature{
+		config:  config,
+		browser: browser,
+		writer:  os.Stdout,
+	}
+}
+
+// Open shows the url in the browser, or prints it with PrintURL.
+func (f *BrowseFeature) Open(pageURL string) error {
+	if f.PrintURL {
+		_, err := fmt.Fprintln(f.writer, pageURL)
+		return err
+	}
+	return f.browser.Open(pageURL)
+}
+
+// IssuesURL is the issues page of the repository.
+func (f *BrowseFeature) IssuesURL() (string, error) {
+	return f.repoURL("/issues")
+}
+
+// IssueURL is the page of an issue.
+func (f *BrowseFeature) IssueURL(number int) (string, error) {
+	if number <= 0 {
+		return "", errNumberIsRequered
+	}
+	return f.repoURL(fmt.Sprintf("/issues/%d", number))
+}
+
+// NewIssueURL is the new issue page, prefilled with the form.
+func (f *BrowseFeature) NewIssueURL(form NewIssueForm) (string, error) {
+	values := url.Values{}
+	if form.Title != "" {
+		values.Set("title", form.Title)
+	}
+	if form.Body != "" {
+		values.Set("body", form.Body)
+	}
+	if len(form.Labels) > 0 {
+		values.Set("labels", strings.Join(form.Labels, ","))
+	}
+	if form.Template != "" {
+		values.Set("template", form.Template)
+	}
+	path := "/issues/new"
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+	return f.repoURL(path)
+}
+
+// SearchURL is the issues page filtered like List with the filter, or with a
+// search in the GitHub syntax when query is set, and ordered like SortIssues.
+func (f *BrowseFeature) SearchURL(filter ListFilter, query, sortBy string) (string, error) {
+	terms := []string{"is:issue"}
+	if query != "" {
+		terms = append(terms, query)
+	} else {
+		switch filter.State {
+		case "", "open", "closed":
+			if filter.State == "" {
+				filter.State = "open"
+			}
+			terms = append(terms, "is:"+filter.State)
+		case "all":
+		default:
+			return "", fmt.Errorf("%w: %q, use %s", errState, filter.State, strings.Join(ListStates, ", "))
+		}
+
+		switch filter.Assignee {
+		case "":
+		case "none":
+			terms = append(terms, "no:assignee")
+		case "*":
+			terms = append(terms, "-no:assignee")
+		default:
+			terms = append(terms, "assignee:"+filter.Assignee)
+		}
+
+		for _, label := range strings.Split(filter.Labels, ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				terms = append(terms, "label:"+searchValue(label))
+			}
+		}
+	}
+
+	if sortBy != "" {
+		qualifier, ok := webSorts[sortBy]
+		if !ok {
+			return "", fmt.Errorf("%w: %q, use %s", errSort, sortBy, strings.Join(ListSorts, ", "))
+		}
+		terms = append(terms, qualifier)
+	}
+
+	return f.repoURL("/issues?" + url.Values{"q": {strings.Join(terms, " ")}}.Encode())
+}
+
+func (f *BrowseFeature) repoURL(path string) (string, error) {
+	web := host.FromAPIBaseURL(f.config.APIBaseURL).Web
+	if web == "" {
+		return "", fmt.Errorf("%w: %q", errBrowseHost, f.config.APIBaseURL)
+	}
+	return fmt.Sprintf("%s/%s/%s%s", web, f.config.Owner, f.config.Repo, path), nil
+}
+
+// searchValue quotes a value of the search syntax holding spaces.
+func searchValue(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return `"` + value + `"`
+	}
+	return value
+}
@@ -0,0 +1,133 @@
+package issue
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+func TestBrowseURLs(t *testing.T) {
+	cfg := &domain.Config{APIBaseURL: "https://api.github.com", Owner: "owner", Repo: "repo"}
+	f := NewBrowse(cfg, &stubs.BrowserStub{})
+
+	tests := []struct {
+		name    string
+		url     func() (string, error)
+		want    string
+		wantErr error
+	}{
+		{
+			name: "issues page",
+			url:  f.IssuesURL,
+			want: "https://github.com/owner/repo/issues",
+		},
+		{
+			name: "issue",
+			url:  func() (string, error) { return f.IssueURL(12) },
+			want: "https://github.com/owner/repo/issues/12",
+		},
+		{
+			name:    "issue without number",
+			url:     func() (string, error) { return f.IssueURL(0) },
+			wantErr: errNumberIsRequered,
+		},
+		{
+			name: "empty new issue form",
+			url:  func() (string, error) { return f.NewIssueURL(NewIssueForm{}) },
+			want: "https://github.com/owner/repo/issues/new",
+		},
+		{
+			name: "prefilled new issue form",
+			url: func() (string, error) {
+				return f.NewIssueURL(NewIssueForm{Title: "Crash on start", Body: "Steps:\n1. run", Labels: []string{"bug", "good first issue"}, Template: "bug_report.md"})
+			},
+			want: "https://github.com/owner/repo/issues/new?body=Steps%3A%0A1.+run&labels=bug%2Cgood+first+issue&template=bug_report.md&title=Crash+on+start",
+		},
+		{
+			name: "open issues",
+			url:  func() (string, error) { return f.SearchURL(ListFilter{}, "", "") },
+			want: "https://github.com/owner/repo/issues?q=is%3Aissue+is%3Aopen",
+		},
+		{
+			name: "filtered and sorted",
+			url: func() (string, error) {
+				return f.SearchURL(ListFilter{State: "all", Assignee: "@me", Labels: "bug, needs triage"}, "", "reactions")
+			},
+			want: "https://github.com/owner/repo/issues?q=is%3Aissue+assignee%3A%40me+label%3Abug+label%3A%22needs+triage%22+sort%3Areactions-%2B1-desc",
+		},
+		{
+			name: "unassigned closed",
+			url: func() (string, error) {
+				return f.SearchURL(ListFilter{State: "closed", Assignee: "none"}, "", "updated")
+			},
+			want: "https://github.com/owner/repo/issues?q=is%3Aissue+is%3Aclosed+no%3Aassignee+sort%3Aupdated-desc",
+		},
+		{
+			name: "search query",
+			url:  func() (string, error) { return f.SearchURL(ListFilter{State: "closed"}, "label:bug author:mona", "") },
+			want: "https://github.com/owner/repo/issues?q=is%3Aissue+label%3Abug+author%3Amona",
+		},
+		{
+			name:    "unknown state",
+			url:     func() (string, error) { return f.SearchURL(ListFilter{State: "merged"}, "", "") },
+			wantErr: errState,
+		},
+		{
+			name:    "unknown sort",
+			url:     func() (string, error) { return f.SearchURL(ListFilter{}, "", "comments") },
+			wantErr: errSort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.url()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBrowseEnterprise(t *testing.T) {
+	cfg := &domain.Config{APIBaseURL: "https://ghe.example.com/api/v3", Owner: "owner", Repo: "repo"}
+	if got, _ := NewBrowse(cfg, nil).IssueURL(3); got != "https://ghe.example.com/owner/repo/issues/3" {
+		t.Errorf("got %q", got)
+	}
+
+	cfg.APIBaseURL = "http://localhost:8080"
+	if _, err := NewBrowse(cfg, nil).IssuesURL(); !errors.Is(err, errBrowseHost) {
+		t.Errorf("got %v want errBrowseHost", err)
+	}
+}
+
+func TestBrowseOpen(t *testing.T) {
+	var opened []string
+	f := NewBrowse(&domain.Config{}, &stubs.BrowserStub{OpenFunc: func(url string) error {
+		opened = append(opened, url)
+		return nil
+	}})
+	var out bytes.Buffer
+	f.writer = &out
+
+	if err := f.Open("https://github.com/owner/repo/issues"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.PrintURL = true
+	if err := f.Open("https://github.com/owner/repo/issues/1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(opened) != 1 || opened[0] != "https://github.com/owner/repo/issues" {
+		t.Errorf("opened %q", opened)
+	}
+	if out.String() != "https://github.com/owner/repo/issues/1\n" {
+		t.Errorf("printed %q", out.String())
+	}
+}
@@ -0,0 +1,255 @@
+package issue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+	"git-issues/service/git"
+)
+
+// changelogPageSize is the largest page the issues endpoint returns.
+const changelogPageSize = 100
+
+const dateOnly = "2006-01-02"
+
+// dateLayouts are the forms --since and --until accept besides git refs.
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04", dateOnly}
+
+// DefaultChangelog is used when the config has no changelog mapping.
+var DefaultChangelog = domain.ChangelogConfig{
+	Sections: []domain.ChangelogSection{
+		{Title: "Features", Labels: []string{"enhancement", "feature"}},
+		{Title: "Fixes", Labels: []string{"bug"}},
+		{Title: "Documentation", Labels: []string{"documentation"}},
+	},
+	Other:   "Other",
+	Exclude: []string{"duplicate", "invalid", "wontfix"},
+}
+
+// DefaultChangelogTemplate renders the changelog as Markdown.
+const DefaultChangelogTemplate = `## {{.Title}}
+{{range .Sections}}
+### {{.Title}}
+
+{{range .Issues}}- {{.Title}} (#{{.Number}})
+{{end}}{{end}}`
+
+// ChangelogIssue collects the issues closed between two points in time.
+type ChangelogIssue interface {
+	Changelog(since string, until string) (*Changelog, error)
+}
+
+// Changelog is the data the templates render. Since and Until are the
+// values given, tags or dates, and From and To the times they stand for.
+type Changelog struct {
+	Title    string
+	Since    string
+	Until    string
+	From     time.Time
+	To       time.Time
+	Sections []ChangelogSection
+}
+
+type ChangelogSection struct {
+	Title  string
+	Issues []domain.Issue
+}
+
+type ChangelogFeature struct {
+	config *domain.Config
+	client client.GitHubClient
+	git    git.Git
+	now    func() time.Time
+}
+
+func NewChangelog(config *domain.Config, client client.GitHubClient, git git.Git) *ChangelogFeature {
+	return &ChangelogFeature{
+		config: config,
+		client: client,
+		git:    git,
+		now:    time.Now,
+	}
+}
+
+// Changelog returns the issues closed as completed after since and up to
+// until, now when it is empty, grouped into the sections of the config. A
+// day given as until includes the whole day.
+// Pull requests and issues closed as not planned are left out.
+func (f *ChangelogFeature) Changelog(since string, until string) (*Changelog, error) {
+	if since == "" {
+		return nil, errChangelogSince
+	}
+	changelog := &Changelog{Since: since, Until: until, To: f.now()}
+
+	var err error
+	if changelog.From, err = f.resolve(since); err != nil {
+		return nil, err
+	}
+	if until != "" {
+		if changelog.To, err = f.resolve(until); err != nil {
+			return nil, err
+		}
+		if _, err = time.Parse(dateOnly, until); err == nil {
+			// a day given as until is included
+			changelog.To = changelog.To.AddDate(0, 0, 1)
+		}
+	}
+	changelog.Title = changelogTitle(changelog)
+
+	issues, err := f.closedSince(changelog.From)
+	if err != nil {
+		return nil, errors.Join(errChangelog, err)
+	}
+
+	var closed []domain.Issue
+	for _, issue := range issues {
+		closedAt, err := time.Parse(time.RFC3339, issue.ClosedAt)
+		if err != nil || !closedAt.After(changelog.From) || closedAt.After(changelog.To) {
+			continue
+		}
+		if issue.PullRequest != nil || issue.StateReason == "not_planned" {
+			continue
+		}
+		closed = append(closed, issue)
+	}
+
+	mapping := DefaultChangelog
+	if f.config.Changelog != nil {
+		mapping = *f.config.Changelog
+	}
+	changelog.Sections = groupIssues(closed, mapping)
+	return changelog, nil
+}
+
+// resolve reads a date, or else asks git for the date of the tag or ref.
+func (f *ChangelogFeature) resolve(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return date, nil
+		}
+	}
+	date, err := f.git.RefDate(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w %q: %w", errChangelogRef, value, err)
+	}
+	return date, nil
+}
+
+// closedSince reads every closed issue updated after from, which includes
+// the ones closed after it.
+func (f *ChangelogFeature) closedSince(from time.Time) ([]domain.Issue, error) {
+	var issues []domain.Issue
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/repos/%s/%s/issues?state=closed&since=%s&sort=created&direction=asc&per_page=%d&page=%d",
+			f.config.APIBaseURL, f.config.Owner, f.config.Repo, url.QueryEscape(from.UTC().Format(time.RFC3339)), changelogPageSize, page)
+
+		response, err := f.client.MakeRequest("GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		pageIssues := []domain.Issue{}
+		if err = json.Unmarshal(response, &pageIssues); err != nil {
+			return nil, errProcessing
+		}
+		issues = append(issues, pageIssues...)
+		if len(pageIssues) < changelogPageSize {
+			return issues, nil
+		}
+	}
+}
+
+// groupIssues puts each issue in the first section with one of its labels.
+// Empty sections are dropped and the issues without a section go last.
+func groupIssues(issues []domain.Issue, mapping domain.ChangelogConfig) []ChangelogSection {
+	sections := make([]ChangelogSection, len(mapping.Sections))
+	for i, s := range mapping.Sections {
+		sections[i].Title = s.Title
+	}
+	other := ChangelogSection{Title: mapping.Other}
+	if other.Title == "" {
+		other.Title = DefaultChangelog.Other
+	}
+
+	for _, issue := range issues {
+		if hasAnyLabel(&issue, mapping.Exclude) {
+			continue
+		}
+		placed := false
+		for i, s := range mapping.Sections {
+			if hasAnyLabel(&issue, s.Labels) {
+				sections[i].Issues = append(sections[i].Issues, issue)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			other.Issues = append(other.Issues, issue)
+		}
+	}
+
+	var result []ChangelogSection
+	for _, s := range append(sections, other) {
+		if len(s.Issues) > 0 {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func hasAnyLabel(issue *domain.Issue, labels []string) bool {
+	for _, name := range labels {
+		if hasLabel(issue, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// labelNames joins the labels of the issue with commas.
+func labelNames(issue domain.Issue) string {
+	names := make([]string, 0, len(issue.Labels))
+	for _, l := range issue.Labels {
+		names = append(names, l.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func changelogTitle(c *Changelog) string {
+	if c.Until != "" {
+		return fmt.Sprintf("Changes from %s to %s", c.Since, c.Until)
+	}
+	return fmt.Sprintf("Changes since %s", c.Since)
+}
+
+// PrintChangelog renders the changelog with the text/template in the file,
+// or as Markdown when file is empty.
+func PrintChangelog(w io.Writer, changelog *Changelog, file string) error {
+	text := DefaultChangelogTemplate
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return errors.Join(errChangelogTemplate, err)
+		}
+		text = string(data)
+	}
+
+	tmpl, err := template.New("changelog").Funcs(template.FuncMap{
+		"date":   func(t time.Time) string { return t.Format(dateOnly) },
+		"labels": labelNames,
+		"slug":   Slug,
+		"lower":  strings.ToLower,
+	}).Parse(text)
+	if err != nil {
+		return errors.Join(errChangelogTemplate, err)
+	}
+	return tmpl.Execute(w, changelog)
+}
@@ -0,0 +1,180 @@
+package issue
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/service/git"
+	"git-issues/testdata/stubs"
+)
+
+var tagDate = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func closedIssue(number int, title, closedAt string, labels ...string) domain.Issue {
+	issue := domain.Issue{Number: number, Title: title, State: "closed", ClosedAt: closedAt}
+	for _, name := range labels {
+		issue.Labels = append(issue.Labels, domain.Label{Name: name})
+	}
+	return issue
+}
+
+func TestChangelog(t *testing.T) {
+	notPlanned := closedIssue(5, "Dark mode", "2026-01-10T00:00:00Z", "enhancement")
+	notPlanned.StateReason = "not_planned"
+	pull := closedIssue(6, "Fix typo", "2026-01-10T00:00:00Z", "bug")
+	pull.PullRequest = &domain.PullRequestLinks{HTMLURL: "https://github.com/o/r/pull/6"}
+	issues := []domain.Issue{
+		closedIssue(1, "Crash on start", "2026-01-05T00:00:00Z", "bug"),
+		closedIssue(2, "Add export", "2026-01-06T00:00:00Z", "Enhancement", "bug"),
+		closedIssue(3, "Bump deps", "2026-01-07T00:00:00Z"),
+		closedIssue(4, "Before the tag", "2025-12-31T00:00:00Z", "bug"),
+		notPlanned,
+		pull,
+		closedIssue(7, "Duplicate", "2026-01-08T00:00:00Z", "bug", "duplicate"),
+		closedIssue(8, "After until", "2026-02-03T12:00:00Z", "bug"),
+		closedIssue(9, "On the until day", "2026-02-01T12:00:00Z"),
+	}
+
+	tests := []struct {
+		name     string
+		config   *domain.Config
+		since    string
+		until    string
+		want     []ChangelogSection
+		wantFrom time.Time
+		wantErr  error
+	}{
+		{
+			name:  "default mapping since a tag",
+			since: "v1.0.0",
+			until: "2026-02-01",
+			want: []ChangelogSection{
+				{Title: "Features", Issues: []domain.Issue{issues[1]}},
+				{Title: "Fixes", Issues: []domain.Issue{issues[0]}},
+				{Title: "Other", Issues: []domain.Issue{issues[2], issues[8]}},
+			},
+			wantFrom: tagDate,
+		},
+		{
+			name: "configured mapping since a date",
+			config: &domain.Config{Changelog: &domain.ChangelogConfig{
+				Sections: []domain.ChangelogSection{{Title: "Bug fixes", Labels: []string{"bug"}}},
+				Other:    "Misc",
+			}},
+			since: "2026-01-01T12:00",
+			until: "2026-02-01",
+			want: []ChangelogSection{
+				{Title: "Bug fixes", Issues: []domain.Issue{issues[0], issues[1], issues[6]}},
+				{Title: "Misc", Issues: []domain.Issue{issues[2], issues[8]}},
+			},
+			wantFrom: time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local),
+		},
+		{
+			name:    "unknown tag",
+			since:   "v9",
+			wantErr: errChangelogRef,
+		},
+		{
+			name:    "no since",
+			wantErr: errChangelogSince,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &domain.Config{APIBaseURL: "https://api.example.com", Owner: "o", Repo: "r"}
+			if tt.config != nil {
+				cfg.Changelog = tt.config.Changelog
+			}
+			var urls []string
+			clientStub := &stubs.ClientStub{MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+				urls = append(urls, url)
+				return json.Marshal(issues)
+			}}
+			gitStub := &stubs.GitStub{RefDateFunc: func(ref string) (time.Time, error) {
+				if ref == "v1.0.0" {
+					return tagDate, nil
+				}
+				return time.Time{}, git.ErrGit
+			}}
+
+			got, err := NewChangelog(cfg, clientStub, gitStub).Changelog(tt.since, tt.until)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !got.From.Equal(tt.wantFrom) {
+				t.Errorf("from: got %v want %v", got.From, tt.wantFrom)
+			}
+			if !reflect.DeepEqual(got.Sections, tt.want) {
+				t.Errorf("got %+v\nwant %+v", got.Sections, tt.want)
+			}
+			wantURL := fmt.Sprintf("https://api.example.com/repos/o/r/issues?state=closed&since=%s&sort=created&direction=asc&per_page=100&page=1",
+				strings.ReplaceAll(tt.wantFrom.UTC().Format(time.RFC3339), ":", "%3A"))
+			if len(urls) != 1 || urls[0] != wantURL {
+				t.Errorf("got urls %v want %s", urls, wantURL)
+			}
+		})
+	}
+}
+
+func TestPrintChangelog(t *testing.T) {
+	changelog := &Changelog{
+		Title: "Changes since v1.0.0",
+		Since: "v1.0.0",
+		From:  tagDate,
+		Sections: []ChangelogSection{
+			{Title: "Features", Issues: []domain.Issue{closedIssue(2, "Add export", "", "enhancement", "ui")}},
+			{Title: "Fixes", Issues: []domain.Issue{closedIssue(1, "Crash on start", "", "bug")}},
+		},
+	}
+
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := PrintChangelog(&buf, changelog, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "## Changes since v1.0.0\n\n### Features\n\n- Add export (#2)\n\n### Fixes\n\n- Crash on start (#1)\n"
+		if buf.String() != want {
+			t.Errorf("got %q want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("template file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "notes.tmpl")
+		text := "{{.Since}} {{date .From}}\n{{range .Sections}}{{range .Issues}}{{lower $.Title}}: {{.Title}} [{{labels .}}]\n{{end}}{{end}}"
+		if err := os.WriteFile(file, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := PrintChangelog(&buf, changelog, file); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "v1.0.0 2026-01-01\nchanges since v1.0.0: Add export [enhancement, ui]\nchanges since v1.0.0: Crash on start [bug]\n"
+		if buf.String() != want {
+			t.Errorf("got %q want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "bad.tmpl")
+		if err := os.WriteFile(file, []byte("{{range"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := PrintChangelog(&bytes.Buffer{}, changelog, file); !errors.Is(err, errChangelogTemplate) {
+			t.Errorf("got %v want errChangelogTemplate", err)
+		}
+	})
+}
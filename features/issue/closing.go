@@ -0,0 +1,72 @@
+package issue
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ClosingKeywords are the words that close an issue when the commit or pull
+// request they are in is merged into the default branch.
+var ClosingKeywords = []string{"close", "closes", "closed", "fix", "fixes", "fixed", "resolve", "resolves", "resolved"}
+
+var (
+	reReference = regexp.MustCompile(`(?i)(?:^|[^\w/#])(?:(` + strings.Join(ClosingKeywords, "|") + `):?\s+)?` +
+		`((?:[\w.-]+/[\w.-]+)?#\d+|https?://[^/\s]+/[\w.-]+/[\w.-]+/(?:issues|pull)/\d+)\b`)
+	reInlineCode = regexp.MustCompile("`[^`\n]*`")
+)
+
+// Reference is a mention of an issue in a text. Keyword is the closing
+// keyword in front of it, in lower case, empty for plain mentions.
+type Reference struct {
+	Ref     IssueRef
+	Keyword string
+}
+
+// Closes reports whether merging the text closes the issue.
+func (r Reference) Closes() bool {
+	return r.Keyword != ""
+}
+
+// ParseReferences finds the issues a commit message or markdown text
+// mentions, as #12, owner/repo#12 or an issue url, in order. Like on GitHub,
+// each reference needs its own keyword to close, and code is skipped.
+func ParseReferences(text string) []Reference {
+	var references []Reference
+	inFence := false
+	for _, line := range strings.Split(text, "\n") {
+		if reCodeFence.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		line = reInlineCode.ReplaceAllString(line, "")
+		for _, match := range reReference.FindAllStringSubmatch(line, -1) {
+			ref, ok := ParseIssueRef(match[2])
+			if !ok {
+				continue
+			}
+			references = append(references, Reference{Ref: *ref, Keyword: strings.ToLower(match[1])})
+		}
+	}
+	return references
+}
+
+// ClosingReferences returns the references that close their issue.
+func ClosingReferences(text string) []Reference {
+	var closing []Reference
+	for _, r := range ParseReferences(text) {
+		if r.Closes() {
+			closing = append(closing, r)
+		}
+	}
+	return closing
+}
+
+// sameIssue compares references resolved to the same repository, ignoring
+// the case of owner and repo like GitHub does.
+func sameIssue(a, b IssueRef) bool {
+	return a.Number == b.Number && strings.EqualFold(a.Owner, b.Owner) && strings.EqualFold(a.Repo, b.Repo)
+}
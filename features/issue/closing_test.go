@@ -0,0 +1,69 @@
+package issue
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Reference
+	}{
+		{
+			name: "mention",
+			text: "Refs #12",
+			want: []Reference{{Ref: IssueRef{Number: 12}}},
+		},
+		{
+			name: "keywords",
+			text: "Fixes #1, closes owner/repo#2 and Resolved: #3\nclose https://github.com/o/r/issues/4",
+			want: []Reference{
+				{Ref: IssueRef{Number: 1}, Keyword: "fixes"},
+				{Ref: IssueRef{Owner: "owner", Repo: "repo", Number: 2}, Keyword: "closes"},
+				{Ref: IssueRef{Number: 3}, Keyword: "resolved"},
+				{Ref: IssueRef{Owner: "o", Repo: "r", Number: 4}, Keyword: "close"},
+			},
+		},
+		{
+			name: "keyword applies to one reference",
+			text: "fixes #1, #2",
+			want: []Reference{{Ref: IssueRef{Number: 1}, Keyword: "fixes"}, {Ref: IssueRef{Number: 2}}},
+		},
+		{
+			name: "not keywords or references",
+			text: "prefixes #5 and issue#6 and fixing #7 and #x",
+			want: []Reference{{Ref: IssueRef{Number: 5}}, {Ref: IssueRef{Number: 7}}},
+		},
+		{
+			name: "code is skipped",
+			text: "see `fixes #1`\n```\ncloses #2\n```\nfix #3",
+			want: []Reference{{Ref: IssueRef{Number: 3}, Keyword: "fix"}},
+		},
+		{
+			name: "none",
+			text: "Update the readme",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseReferences(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClosingReferences(t *testing.T) {
+	got := ClosingReferences("Fix the login\n\nFixes #12, refs #13\nCloses other/repo#1")
+	want := []Reference{
+		{Ref: IssueRef{Number: 12}, Keyword: "fixes"},
+		{Ref: IssueRef{Owner: "other", Repo: "repo", Number: 1}, Keyword: "closes"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+package issue
+
+import (
+	"errors"
+
+	"git-issues/domain"
+	"git-issues/service/git"
+)
+
+// CommitsIssue finds issue references in the local git history.
+type CommitsIssue interface {
+	Commits(number int, all bool) ([]Mention, error)
+	VerifyCloses(base string) (string, []Mention, error)
+}
+
+// Mention is a commit referencing an issue. Reference is the closing one
+// when the commit has both kinds.
+type Mention struct {
+	Commit    git.Commit
+	Reference Reference
+}
+
+type CommitsFeature struct {
+	config *domain.Config
+	git    git.Git
+}
+
+func NewCommits(config *domain.Config, git git.Git) *CommitsFeature {
+	return &CommitsFeature{
+		config: config,
+		git:    git,
+	}
+}
+
+// Commits lists the commits of HEAD, or of every branch with all, whose
+// message references the issue, newest first.
+func (f *CommitsFeature) Commits(number int, all bool) ([]Mention, error) {
+	if number == 0 {
+		return nil, errNumberIsRequered
+	}
+	var revisions []string
+	if all {
+		revisions = append(revisions, "--all")
+	}
+	commits, err := f.git.Log(revisions...)
+	if err != nil {
+		return nil, errors.Join(errCommits, err)
+	}
+
+	want := IssueRef{Owner: f.config.Owner, Repo: f.config.Repo, Number: number}
+	var mentions []Mention
+	for _, c := range commits {
+		var found *Reference
+		for _, r := range ParseReferences(c.Message()) {
+			if !sameIssue(r.Ref.Resolve(f.config.Owner, f.config.Repo), want) {
+				continue
+			}
+			if found == nil || (r.Closes() && !found.Closes()) {
+				r := r
+				found = &r
+			}
+		}
+		if found != nil {
+			mentions = append(mentions, Mention{Commit: c, Reference: *found})
+		}
+	}
+	return mentions, nil
+}
+
+// VerifyCloses lists the issues the commits of the current branch that are
+// not on base close once merged, each with the oldest commit closing it.
+// Without base the default branch is used. It returns the base it compared
+// with.
+func (f *CommitsFeature) VerifyCloses(base string) (string, []Mention, error) {
+	var err error
+	if base == "" {
+		if base, err = f.git.DefaultBranch(); err != nil {
+			return "", nil, errors.Join(errCommits, err)
+		}
+	}
+	commits, err := f.git.Log(base + "..HEAD")
+	if err != nil {
+		return base, nil, errors.Join(errCommits, err)
+	}
+
+	var closing []Mention
+	for i := len(commits) - 1; i >= 0; i-- {
+		for _, r := range ClosingReferences(commits[i].Message()) {
+			if !f.containsIssue(closing, r.Ref) {
+				closing = append(closing, Mention{Commit: commits[i], Reference: r})
+			}
+		}
+	}
+	return base, closing, nil
+}
+
+func (f *CommitsFeature) containsIssue(mentions []Mention, ref IssueRef) bool {
+	ref = ref.Resolve(f.config.Owner, f.config.Repo)
+	for _, m := range mentions {
+		if sameIssue(m.Reference.Ref.Resolve(f.config.Owner, f.config.Repo), ref) {
+			return true
+		}
+	}
+	return false
+}
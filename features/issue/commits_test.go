@@ -0,0 +1,138 @@
+package issue
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"git-issues/domain"
+	"git-issues/service/git"
+	"git-issues/testdata/stubs"
+)
+
+func TestCommits(t *testing.T) {
+	cfg := &domain.Config{Owner: "owner", Repo: "repo"}
+	log := []git.Commit{
+		{Hash: "c3", Subject: "Close the other one", Body: "Fixes other/repo#12"},
+		{Hash: "c2", Subject: "Fix the login", Body: "Refs #12\nFixes Owner/Repo#12"},
+		{Hash: "c1", Subject: "Start on #12 and #120"},
+		{Hash: "c0", Subject: "Unrelated"},
+	}
+
+	tests := []struct {
+		name     string
+		all      bool
+		logErr   error
+		wantRevs []string
+		want     []Mention
+		wantErr  error
+	}{
+		{
+			name: "head",
+			want: []Mention{
+				{Commit: log[1], Reference: Reference{Ref: IssueRef{Owner: "Owner", Repo: "Repo", Number: 12}, Keyword: "fixes"}},
+				{Commit: log[2], Reference: Reference{Ref: IssueRef{Number: 12}}},
+			},
+		},
+		{
+			name:     "all branches",
+			all:      true,
+			wantRevs: []string{"--all"},
+			want: []Mention{
+				{Commit: log[1], Reference: Reference{Ref: IssueRef{Owner: "Owner", Repo: "Repo", Number: 12}, Keyword: "fixes"}},
+				{Commit: log[2], Reference: Reference{Ref: IssueRef{Number: 12}}},
+			},
+		},
+		{
+			name:    "git error",
+			logErr:  git.ErrGit,
+			wantErr: errCommits,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var revs []string
+			gitStub := &stubs.GitStub{LogFunc: func(revisions ...string) ([]git.Commit, error) {
+				revs = revisions
+				return log, tt.logErr
+			}}
+
+			got, err := NewCommits(cfg, gitStub).Commits(12, tt.all)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(revs, tt.wantRevs) {
+				t.Errorf("revisions: got %v want %v", revs, tt.wantRevs)
+			}
+		})
+	}
+}
+
+func TestVerifyCloses(t *testing.T) {
+	cfg := &domain.Config{Owner: "owner", Repo: "repo"}
+	log := []git.Commit{
+		{Hash: "c3", Subject: "Finish", Body: "Closes owner/repo#12\nresolves #7"},
+		{Hash: "c2", Subject: "Fix the login", Body: "Fixes #12, fixes other/repo#3"},
+		{Hash: "c1", Subject: "Refs #8"},
+	}
+
+	tests := []struct {
+		name      string
+		base      string
+		wantBase  string
+		wantRange string
+		want      []Mention
+	}{
+		{
+			name:      "default branch",
+			wantBase:  "origin/main",
+			wantRange: "origin/main..HEAD",
+			want: []Mention{
+				{Commit: log[1], Reference: Reference{Ref: IssueRef{Number: 12}, Keyword: "fixes"}},
+				{Commit: log[1], Reference: Reference{Ref: IssueRef{Owner: "other", Repo: "repo", Number: 3}, Keyword: "fixes"}},
+				{Commit: log[0], Reference: Reference{Ref: IssueRef{Number: 7}, Keyword: "resolves"}},
+			},
+		},
+		{
+			name:      "given base",
+			base:      "develop",
+			wantBase:  "develop",
+			wantRange: "develop..HEAD",
+			want: []Mention{
+				{Commit: log[1], Reference: Reference{Ref: IssueRef{Number: 12}, Keyword: "fixes"}},
+				{Commit: log[1], Reference: Reference{Ref: IssueRef{Owner: "other", Repo: "repo", Number: 3}, Keyword: "fixes"}},
+				{Commit: log[0], Reference: Reference{Ref: IssueRef{Number: 7}, Keyword: "resolves"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var revs []string
+			gitStub := &stubs.GitStub{
+				DefaultBranchFunc: func() (string, error) { return "origin/main", nil },
+				LogFunc: func(revisions ...string) ([]git.Commit, error) {
+					revs = revisions
+					return log, nil
+				},
+			}
+
+			base, got, err := NewCommits(cfg, gitStub).VerifyCloses(tt.base)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if base != tt.wantBase || !reflect.DeepEqual(revs, []string{tt.wantRange}) {
+				t.Errorf("got base %q and range %v want %q and %q", base, revs, tt.wantBase, tt.wantRange)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v want %+v", got, tt.want)
+			}
+		})
+	}
+}
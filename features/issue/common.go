@@ -3,12 +3,54 @@ package issue
 import "errors"
 
 var (
-	errTitleRequired    = errors.New("title is required")
-	errBodyRequired     = errors.New("body is required")
-	errCreate           = errors.New("could not create issue")
-	errUpdate           = errors.New("could not update issue")
-	errClose            = errors.New("could not close  issue")
-	errNotFound         = errors.New("issue not found")
-	errProcessing       = errors.New("error on process response")
-	errNumberIsRequered = errors.New("number is required")
+	errTitleRequired     = errors.New("title is required")
+	errBodyRequired      = errors.New("body is required")
+	errCreate            = errors.New("could not create issue")
+	errUpdate            = errors.New("could not update issue")
+	errClose             = errors.New("could not close  issue")
+	errReopen            = errors.New("could not reopen issue")
+	errLabel             = errors.New("could not label issue")
+	errAssign            = errors.New("could not assign issue")
+	errLock              = errors.New("could not lock issue")
+	errUnlock            = errors.New("could not unlock issue")
+	errLockReason        = errors.New("invalid lock reason")
+	errPin               = errors.New("could not pin issue")
+	errUnpin             = errors.New("could not unpin issue")
+	errSubIssueAdd       = errors.New("could not add sub-issue")
+	errSubIssueRemove    = errors.New("could not remove sub-issue")
+	errInvalidRef        = errors.New("invalid issue reference")
+	errFormat            = errors.New("unknown output format")
+	errReact             = errors.New("could not add reaction")
+	errReaction          = errors.New("invalid reaction, use +1, -1, laugh, hooray, confused, heart, rocket or eyes")
+	errCommentID         = errors.New("invalid comment id")
+	errSort              = errors.New("unknown sort order")
+	errState             = errors.New("unknown state")
+	errSearch            = errors.New("could not search the issues")
+	errSearchEmpty       = errors.New("the search query is empty")
+	errRepoName          = errors.New("repository must be owner/repo")
+	errBranchTemplate    = errors.New("invalid branch template")
+	errBranchNumber      = errors.New("the branch template must contain {{.Number}}")
+	errBranchName        = errors.New("invalid branch name")
+	errNoIssueBranch     = errors.New("the branch names no issue")
+	errStart             = errors.New("could not start work on the issue")
+	errCommits           = errors.New("could not read the commits")
+	errHook              = errors.New("could not set up the commit hook")
+	errHookExists        = errors.New("a hook that ghissues did not install exists, use --force to replace it")
+	errHookReferenced    = errors.New("the message already references the issue")
+	errChangelog         = errors.New("could not collect the closed issues")
+	errChangelogSince    = errors.New("--since is required, a tag or a date like 2006-01-02")
+	errChangelogRef      = errors.New("not a date or a git ref")
+	errChangelogTemplate = errors.New("invalid changelog template")
+	errTransfer          = errors.New("could not transfer issue")
+	errTransferTarget    = errors.New("target repository not found or not accessible")
+	errTransferNoIssues  = errors.New("target repository has issues disabled")
+	errTransferSameRepo  = errors.New("the issue is already in the target repository")
+	errTransferSearch    = errors.New("could not search the issues to transfer")
+	errDuplicateSearch   = errors.New("could not search for duplicates")
+	errCreateAborted     = errors.New("issue not created")
+	errCommentDuplicate  = errors.New("could not comment on the duplicate")
+	errBrowseHost        = errors.New("the api url has no known web page")
+	errNotFound          = errors.New("issue not found")
+	errProcessing        = errors.New("error on process response")
+	errNumberIsRequered  = errors.New("number is required")
 )
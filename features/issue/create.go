@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"time"
 
 	"git-issues/domain"
 	"git-issues/service/client"
@@ -18,6 +21,22 @@ type CreateFeature struct {
 	client client.GitHubClient
 	editor editor.Editor
 	config *domain.Config
+	reader io.Reader
+	writer io.Writer
+	now    func() time.Time
+
+	// CheckDuplicates looks for similar issues before creating one and asks
+	// what to do through the reader and writer when there are.
+	CheckDuplicates bool
+}
+
+// Created is the issue CreateIssue made or, when the content went to a
+// duplicate as a comment, that duplicate with the url of the comment.
+// DuplicatesErr tells why the duplicates could not be looked up.
+type Created struct {
+	*domain.Issue
+	CommentURL    string
+	DuplicatesErr error
 }
 
 func NewCreate(config *domain.Config, editor editor.Editor, client client.GitHubClient) *CreateFeature {
@@ -25,36 +44,96 @@ func NewCreate(config *domain.Config, editor editor.Editor, client client.GitHub
 		config: config,
 		editor: editor,
 		client: client,
+		reader: os.Stdin,
+		writer: os.Stdout,
+		now:    time.Now,
 	}
 }
 
 func (f *CreateFeature) Create() (string, error) {
+	issue, err := f.CreateIssue()
+	if err != nil {
+		return "", err
+	}
+	if issue.CommentURL != "" {
+		return fmt.Sprintf("Comment added to issue #%v\nURL: %v\n", issue.Number, issue.CommentURL), nil
+	}
+
+	return fmt.Sprintf("Issue created with success!\nNumber: %v\nURL: %v\n", issue.Number, issue.HTMLURL), nil
+}
+
+// CreateIssue opens the editor, creates the issue and returns it as stored by
+// GitHub. With CheckDuplicates the user may abort or comment on a duplicate
+// instead; a failed search for duplicates does not stop the issue and comes
+// back in DuplicatesErr.
+func (f *CreateFeature) CreateIssue() (*Created, error) {
 	issue := &domain.Issue{}
 
 	err := f.editor.GetIssueContentFromEditor(issue)
 	if err != nil {
-		return "", errors.Join(err, domain.ErrEditor)
+		return nil, errors.Join(err, domain.ErrEditor)
 	}
 
 	if issue.Title == "" {
-		return "", errTitleRequired
+		return nil, errTitleRequired
 	}
 
 	if issue.Body == "" {
-		return "", errBodyRequired
+		return nil, errBodyRequired
+	}
+
+	var duplicatesErr error
+	if f.CheckDuplicates {
+		var duplicates []Duplicate
+		if duplicates, duplicatesErr = f.FindDuplicates(issue); len(duplicates) > 0 {
+			created, err := f.askDuplicates(issue, duplicates)
+			if created != nil || err != nil {
+				return created, err
+			}
+		}
 	}
 
 	url := fmt.Sprintf("%s/repos/%s/%s/issues", f.config.APIBaseURL, f.config.Owner, f.config.Repo)
 	response, err := f.client.MakeRequest("POST", url, issue)
 	if err != nil {
-		return "", errors.Join(err, errCreate)
+		return nil, errors.Join(err, errCreate)
 	}
 
-	var result map[string]interface{}
-	err = json.Unmarshal(response, &result)
+	created := &domain.Issue{}
+	err = json.Unmarshal(response, created)
 	if err != nil {
-		return "", errProcessing
+		return nil, errProcessing
 	}
 
-	return fmt.Sprintf("Issue created with success!\nNumber: %v\nURL: %v\n", result["number"], result["html_url"]), nil
+	return &Created{Issue: created, DuplicatesErr: duplicatesErr}, nil
+}
+
+// askDuplicates returns the duplicate commented on, or nil to go on creating.
+func (f *CreateFeature) askDuplicates(issue *domain.Issue, duplicates []Duplicate) (*Created, error) {
+	choice, err := AskDuplicate(f.reader, f.writer, duplicates)
+	if err != nil {
+		return nil, err
+	}
+	if choice.Abort {
+		return nil, errCreateAborted
+	}
+	if choice.CommentOn == 0 {
+		return nil, nil
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", f.config.APIBaseURL, f.config.Owner, f.config.Repo, choice.CommentOn)
+	response, err := f.client.MakeJSONRequest("POST", url, map[string]string{"body": duplicateComment(issue)})
+	if err != nil {
+		return nil, errors.Join(errCommentDuplicate, err)
+	}
+	comment := &domain.Comment{}
+	if err = json.Unmarshal(response, comment); err != nil {
+		return nil, errProcessing
+	}
+	for _, d := range duplicates {
+		if d.Issue.Number == choice.CommentOn {
+			return &Created{Issue: &d.Issue, CommentURL: comment.HTMLURL}, nil
+		}
+	}
+	return nil, errNotFound
 }
@@ -18,7 +18,7 @@ func TestMain(m *testing.M) {
 func TestCreate(t *testing.T) {
 	// Arrange
 
-	f := CreateFeature{config: &domain.Config{}}
+	f := CreateFeature{config: &domain.Config{}, CheckDuplicates: false}
 
 	tests := []struct {
 		name       string
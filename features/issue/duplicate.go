@@ -0,0 +1,162 @@
+package issue
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/service/similarity"
+)
+
+const (
+	// DuplicateThreshold is the score from which an issue is shown as a
+	// possible duplicate.
+	DuplicateThreshold = 0.35
+	// DuplicateRecentlyClosed is how long closed issues stay candidates.
+	DuplicateRecentlyClosed = 90 * 24 * time.Hour
+
+	duplicateCandidates = 3
+	duplicateKeywords   = 6
+	duplicateSearchSize = 50
+)
+
+// Duplicate is an existing issue alike the one being created.
+type Duplicate struct {
+	Issue domain.Issue
+	Score float64
+}
+
+// DuplicateChoice is what to do with a new issue that has duplicates.
+type DuplicateChoice struct {
+	// Abort drops the new issue.
+	Abort bool
+	// CommentOn adds the new issue as a comment on that issue instead.
+	CommentOn int
+}
+
+// FindDuplicates searches the open and recently closed issues sharing words
+// with the title and ranks them by similarity with the title and body. The
+// best ones scoring DuplicateThreshold or more are returned.
+func (f *CreateFeature) FindDuplicates(issue *domain.Issue) ([]Duplicate, error) {
+	keywords := similarity.Tokens(issue.Title)
+	if len(keywords) == 0 {
+		return nil, nil
+	}
+	if len(keywords) > duplicateKeywords {
+		keywords = keywords[:duplicateKeywords]
+	}
+
+	q := fmt.Sprintf("repo:%s/%s is:issue %s", f.config.Owner, f.config.Repo, strings.Join(keywords, " OR "))
+	searchURL := fmt.Sprintf("%s/search/issues?q=%s&sort=updated&per_page=%d", f.config.APIBaseURL, url.QueryEscape(q), duplicateSearchSize)
+	response, err := f.client.MakeRequest("GET", searchURL, nil)
+	if err != nil {
+		return nil, errors.Join(errDuplicateSearch, err)
+	}
+	var found struct {
+		Items []domain.Issue `json:"items"`
+	}
+	if err = json.Unmarshal(response, &found); err != nil {
+		return nil, errProcessing
+	}
+
+	var candidates []domain.Issue
+	for _, item := range found.Items {
+		if item.PullRequest == nil && f.recent(&item) {
+			candidates = append(candidates, item)
+		}
+	}
+	return rankDuplicates(issue, candidates), nil
+}
+
+// recent tells open issues and those closed within DuplicateRecentlyClosed
+// from the others.
+func (f *CreateFeature) recent(issue *domain.Issue) bool {
+	if issue.State != "closed" {
+		return true
+	}
+	closedAt, err := time.Parse(time.RFC3339, issue.ClosedAt)
+	return err == nil && f.now().Sub(closedAt) <= DuplicateRecentlyClosed
+}
+
+// rankDuplicates scores the candidates by the TF-IDF similarity of title and
+// body, the title counting twice, or by the words the titles share when that
+// is higher, as short titles say more than long bodies.
+func rankDuplicates(issue *domain.Issue, candidates []domain.Issue) []Duplicate {
+	documents := make([]string, len(candidates))
+	for i, c := range candidates {
+		documents[i] = c.Title + "\n" + c.Title + "\n" + c.Body
+	}
+	scores := make([]float64, len(candidates))
+	for _, match := range similarity.Rank(issue.Title+"\n"+issue.Title+"\n"+issue.Body, documents) {
+		scores[match.Index] = match.Score
+	}
+
+	var duplicates []Duplicate
+	for i, c := range candidates {
+		score := scores[i]
+		if titles := similarity.Jaccard(issue.Title, c.Title); titles > score {
+			score = titles
+		}
+		if score >= DuplicateThreshold {
+			duplicates = append(duplicates, Duplicate{Issue: c, Score: score})
+		}
+	}
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i].Score > duplicates[j].Score
+	})
+	if len(duplicates) > duplicateCandidates {
+		duplicates = duplicates[:duplicateCandidates]
+	}
+	return duplicates
+}
+
+// AskDuplicate lists the duplicates on w and reads from r whether to abort,
+// comment on one of them or create the issue anyway, the default.
+func AskDuplicate(r io.Reader, w io.Writer, duplicates []Duplicate) (DuplicateChoice, error) {
+	fmt.Fprintln(w, "Possible duplicates:")
+	for i, d := range duplicates {
+		state := d.Issue.State
+		if state == "closed" && len(d.Issue.ClosedAt) >= len(dateOnly) {
+			state += " " + d.Issue.ClosedAt[:len(dateOnly)]
+		}
+		fmt.Fprintf(w, "  %d) #%d %s (%s, %.0f%% alike)\n", i+1, d.Issue.Number, d.Issue.Title, state, d.Score*100)
+		if d.Issue.HTMLURL != "" {
+			fmt.Fprintf(w, "     %s\n", d.Issue.HTMLURL)
+		}
+	}
+
+	reader := bufio.NewReader(r)
+	for {
+		fmt.Fprintf(w, "[c]reate anyway, [a]bort, or 1-%d to comment on that issue instead [c]: ", len(duplicates))
+		answer, err := reader.ReadString('\n')
+		if err != nil && answer == "" {
+			if err == io.EOF {
+				return DuplicateChoice{}, nil
+			}
+			return DuplicateChoice{}, err
+		}
+		switch answer = strings.ToLower(strings.TrimSpace(answer)); answer {
+		case "", "c", "create":
+			return DuplicateChoice{}, nil
+		case "a", "abort":
+			return DuplicateChoice{Abort: true}, nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(duplicates) {
+			return DuplicateChoice{CommentOn: duplicates[n-1].Issue.Number}, nil
+		}
+		fmt.Fprintf(w, "unknown answer %q\n", answer)
+	}
+}
+
+// duplicateComment is the new issue turned into a comment, its title on top.
+func duplicateComment(issue *domain.Issue) string {
+	return fmt.Sprintf("**%s**\n\n%s", issue.Title, issue.Body)
+}
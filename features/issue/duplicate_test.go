@@ -0,0 +1,167 @@
+package issue
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+const duplicateSearch = `{"items":[
+	{"number":12,"title":"App crashes on start","body":"Crash when the config file is missing","state":"open","html_url":"https://github.com/o/r/issues/12"},
+	{"number":9,"title":"Crash on start with an empty config","state":"closed","closed_at":"2026-10-01T10:00:00Z"},
+	{"number":3,"title":"App crashes on start","state":"closed","closed_at":"2025-01-01T10:00:00Z"},
+	{"number":7,"title":"App crashes on start","state":"open","pull_request":{"html_url":"https://github.com/o/r/pull/7"}},
+	{"number":5,"title":"Dark mode for the start page","state":"open"}
+]}`
+
+func newDuplicateCreate(answer string, search string, posted *[]string) (*CreateFeature, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	f := &CreateFeature{
+		config: &domain.Config{APIBaseURL: "https://api.github.com", Owner: "o", Repo: "r"},
+		editor: &stubs.EditorStub{
+			GetIssueContentFromEditorFunc: func(issue *domain.Issue) error {
+				issue.Title = "Crash on start"
+				issue.Body = "The app crashes on start when there is no config file"
+				return nil
+			},
+		},
+		client: &stubs.ClientStub{
+			MakeRequestFunc: func(method, u string, data *domain.Issue) ([]byte, error) {
+				if method == "GET" {
+					if search == "" {
+						return nil, domain.ErrApi
+					}
+					return []byte(search), nil
+				}
+				*posted = append(*posted, method+" "+u)
+				return []byte(`{"number":40,"html_url":"https://github.com/o/r/issues/40"}`), nil
+			},
+			MakeJSONRequestFunc: func(method, u string, payload interface{}) ([]byte, error) {
+				*posted = append(*posted, method+" "+u+" "+payload.(map[string]string)["body"])
+				return []byte(`{"html_url":"https://github.com/o/r/issues/12#issuecomment-1"}`), nil
+			},
+		},
+		reader:          strings.NewReader(answer),
+		writer:          out,
+		now:             func() time.Time { return time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC) },
+		CheckDuplicates: true,
+	}
+	return f, out
+}
+
+func TestFindDuplicates(t *testing.T) {
+	var searched string
+	f, _ := newDuplicateCreate("", duplicateSearch, nil)
+	f.client.(*stubs.ClientStub).MakeRequestFunc = func(method, u string, data *domain.Issue) ([]byte, error) {
+		searched = u
+		return []byte(duplicateSearch), nil
+	}
+
+	got, err := f.FindDuplicates(&domain.Issue{Title: "Crash on start", Body: "The app crashes on start when there is no config file"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var numbers []int
+	for _, d := range got {
+		numbers = append(numbers, d.Issue.Number)
+		if d.Score < DuplicateThreshold || d.Score > 1 {
+			t.Errorf("#%d: score %v out of range", d.Issue.Number, d.Score)
+		}
+	}
+	// #3 was closed long ago, #7 is a pull request and #5 is not alike
+	if !reflect.DeepEqual(numbers, []int{12, 9}) {
+		t.Errorf("got duplicates %v want [12 9]", numbers)
+	}
+	parsed, _ := url.Parse(searched)
+	if q := parsed.Query().Get("q"); q != "repo:o/r is:issue crash OR start" {
+		t.Errorf("unexpected search %q", q)
+	}
+}
+
+func TestCreateWithDuplicates(t *testing.T) {
+	tests := []struct {
+		name       string
+		answer     string
+		search     string
+		want       *Created
+		wantPosted []string
+		wantOutput string
+		wantErr    error
+		wantDupErr error
+	}{
+		{
+			name:       "create anyway",
+			answer:     "\n",
+			search:     duplicateSearch,
+			want:       &Created{Issue: &domain.Issue{Number: 40, HTMLURL: "https://github.com/o/r/issues/40"}},
+			wantPosted: []string{"POST https://api.github.com/repos/o/r/issues"},
+			wantOutput: "  1) #12 App crashes on start (open, ",
+		},
+		{
+			name:       "abort",
+			answer:     "a\n",
+			search:     duplicateSearch,
+			wantOutput: "  2) #9 Crash on start with an empty config (closed 2026-10-01, ",
+			wantErr:    errCreateAborted,
+		},
+		{
+			name:   "comment on a duplicate after a wrong answer",
+			answer: "7\n1\n",
+			search: duplicateSearch,
+			want: &Created{
+				Issue:      &domain.Issue{Number: 12, Title: "App crashes on start", Body: "Crash when the config file is missing", State: "open", HTMLURL: "https://github.com/o/r/issues/12"},
+				CommentURL: "https://github.com/o/r/issues/12#issuecomment-1",
+			},
+			wantPosted: []string{"POST https://api.github.com/repos/o/r/issues/12/comments **Crash on start**\n\nThe app crashes on start when there is no config file"},
+			wantOutput: "unknown answer \"7\"\n",
+		},
+		{
+			name:       "no duplicates",
+			search:     `{"items":[]}`,
+			want:       &Created{Issue: &domain.Issue{Number: 40, HTMLURL: "https://github.com/o/r/issues/40"}},
+			wantPosted: []string{"POST https://api.github.com/repos/o/r/issues"},
+		},
+		{
+			name:       "search failure does not stop the issue",
+			want:       &Created{Issue: &domain.Issue{Number: 40, HTMLURL: "https://github.com/o/r/issues/40"}},
+			wantPosted: []string{"POST https://api.github.com/repos/o/r/issues"},
+			wantDupErr: domain.ErrApi,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var posted []string
+			f, out := newDuplicateCreate(tt.answer, tt.search, &posted)
+
+			got, err := f.CreateIssue()
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v want %v", err, tt.wantErr)
+			}
+			if got != nil {
+				if !errors.Is(got.DuplicatesErr, tt.wantDupErr) {
+					t.Errorf("got duplicates error %v want %v", got.DuplicatesErr, tt.wantDupErr)
+				}
+				got.DuplicatesErr = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(posted, tt.wantPosted) {
+				t.Errorf("got requests %q want %q", posted, tt.wantPosted)
+			}
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("output %q does not contain %q", out.String(), tt.wantOutput)
+			}
+		})
+	}
+}
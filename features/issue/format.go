@@ -0,0 +1,155 @@
+package issue
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"git-issues/domain"
+)
+
+// Output formats of list and history. Text is meant for people, json and csv
+// carry the same columns for scripts and audits.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+var Formats = []string{FormatText, FormatJSON, FormatCSV}
+
+const strHistoryFormat = "%s %s %s\n"
+
+type issueRecord struct {
+	Repo      string   `json:"repo,omitempty"`
+	Number    int      `json:"number"`
+	Title     string   `json:"title"`
+	State     string   `json:"state"`
+	Labels    []string `json:"labels"`
+	Assignees []string `json:"assignees"`
+	ThumbsUp  int      `json:"thumbs_up"`
+	Reactions int      `json:"reactions"`
+}
+
+type eventRecord struct {
+	CreatedAt string `json:"created_at"`
+	Actor     string `json:"actor"`
+	Event     string `json:"event"`
+	Detail    string `json:"detail"`
+}
+
+// ValidFormat checks the value of a --format flag.
+func ValidFormat(format string) error {
+	for _, f := range Formats {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q, use one of %s", errFormat, format, strings.Join(Formats, ", "))
+}
+
+// PrintIssuesFormat prints the issues as text, like PrintIssues, or as json or
+// csv records.
+func PrintIssuesFormat(w io.Writer, issues []domain.Issue, format string) error {
+	if format == FormatText || format == "" {
+		return PrintIssues(w, issues)
+	}
+	if err := ValidFormat(format); err != nil {
+		return err
+	}
+
+	records := make([]issueRecord, len(issues))
+	for i, issue := range issues {
+		records[i] = issueRecord{
+			Repo:      issue.Repo,
+			Number:    issue.Number,
+			Title:     issue.Title,
+			State:     issue.State,
+			Labels:    []string{},
+			Assignees: []string{},
+			ThumbsUp:  upvotes(&issues[i]),
+			Reactions: totalReactions(&issues[i]),
+		}
+		for _, label := range issue.Labels {
+			records[i].Labels = append(records[i].Labels, label.Name)
+		}
+		for _, user := range issue.Assignees {
+			records[i].Assignees = append(records[i].Assignees, user.Login)
+		}
+	}
+	if format == FormatJSON {
+		return writeJSON(w, records)
+	}
+
+	// the repo column is only there when the listing spans repositories
+	multiRepo := len(records) > 0 && records[0].Repo != ""
+	header := []string{"number", "title", "state", "labels", "assignees", "thumbs_up", "reactions"}
+	if multiRepo {
+		header = append([]string{"repo"}, header...)
+	}
+	rows := [][]string{header}
+	for _, r := range records {
+		row := []string{
+			strconv.Itoa(r.Number), r.Title, r.State, strings.Join(r.Labels, ","), strings.Join(r.Assignees, ","),
+			strconv.Itoa(r.ThumbsUp), strconv.Itoa(r.Reactions),
+		}
+		if multiRepo {
+			row = append([]string{r.Repo}, row...)
+		}
+		rows = append(rows, row)
+	}
+	return writeCSV(w, rows)
+}
+
+// PrintHistory prints the events with their time, actor and what changed.
+func PrintHistory(w io.Writer, events []domain.Event, format string) error {
+	if format == "" {
+		format = FormatText
+	}
+	if err := ValidFormat(format); err != nil {
+		return err
+	}
+
+	records := make([]eventRecord, len(events))
+	for i, event := range events {
+		records[i] = eventRecord{CreatedAt: event.CreatedAt, Actor: EventActor(event), Event: event.Event, Detail: EventDetail(event)}
+	}
+
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, records)
+	case FormatCSV:
+		rows := [][]string{{"created_at", "actor", "event", "detail"}}
+		for _, r := range records {
+			rows = append(rows, []string{r.CreatedAt, r.Actor, r.Event, r.Detail})
+		}
+		return writeCSV(w, rows)
+	}
+
+	if _, err := fmt.Fprintln(w, "\nHistory:"); err != nil {
+		return err
+	}
+	for _, r := range records {
+		if _, err := fmt.Fprintf(w, strHistoryFormat, r.CreatedAt, r.Actor, r.Detail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func writeCSV(w io.Writer, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
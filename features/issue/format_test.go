@@ -0,0 +1,148 @@
+package issue
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"git-issues/domain"
+)
+
+func TestPrintIssuesFormat(t *testing.T) {
+	issues := []domain.Issue{
+		{Number: 1, Title: "crash, on start", State: "open", Labels: []domain.Label{{Name: "bug"}, {Name: "p1"}}, Assignees: []domain.User{{Login: "a"}}, Reactions: &domain.Reactions{TotalCount: 3, PlusOne: 2, Heart: 1}},
+		{Number: 2, Title: "docs", State: "closed"},
+	}
+
+	tests := []struct {
+		format  string
+		want    string
+		wantErr error
+	}{
+		{format: FormatText, want: "\nIssues:\n#1 - crash, on start (open) 👍 2\n#2 - docs (closed)\n"},
+		{
+			format: FormatJSON,
+			want: `[
+  {
+    "number": 1,
+    "title": "crash, on start",
+    "state": "open",
+    "labels": [
+      "bug",
+      "p1"
+    ],
+    "assignees": [
+      "a"
+    ],
+    "thumbs_up": 2,
+    "reactions": 3
+  },
+  {
+    "number": 2,
+    "title": "docs",
+    "state": "closed",
+    "labels": [],
+    "assignees": [],
+    "thumbs_up": 0,
+    "reactions": 0
+  }
+]
+`,
+		},
+		{format: FormatCSV, want: "number,title,state,labels,assignees,thumbs_up,reactions\n1,\"crash, on start\",open,\"bug,p1\",a,2,3\n2,docs,closed,,,0,0\n"},
+		{format: "xml", wantErr: errFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := PrintIssuesFormat(&buf, issues, tt.format)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got\n%q\nwant\n%q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintIssuesFormatRepos(t *testing.T) {
+	issues := []domain.Issue{
+		{Repo: "a/one", Number: 5, Title: "a5", State: "open"},
+		{Repo: "b/two", Number: 9, Title: "b9", State: "closed"},
+	}
+
+	var buf bytes.Buffer
+	if err := PrintIssuesFormat(&buf, issues, FormatText); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "\nIssues:\na/one#5 - a5 (open)\nb/two#9 - b9 (closed)\n"; buf.String() != want {
+		t.Errorf("text got %q want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := PrintIssuesFormat(&buf, issues, FormatCSV); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "repo,number,title,state,labels,assignees,thumbs_up,reactions\na/one,5,a5,open,,,0,0\nb/two,9,b9,closed,,,0,0\n"; buf.String() != want {
+		t.Errorf("csv got %q want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := PrintIssuesFormat(&buf, issues[:1], FormatJSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"repo": "a/one"`) {
+		t.Errorf("json should carry the repo, got %s", buf.String())
+	}
+}
+
+func TestPrintHistory(t *testing.T) {
+	events := []domain.Event{
+		{Event: "labeled", Actor: &domain.User{Login: "a"}, CreatedAt: "2024-01-02T00:00:00Z", Label: &domain.Label{Name: "bug"}},
+		{Event: "closed", Actor: &domain.User{Login: "b"}, CreatedAt: "2024-01-03T00:00:00Z"},
+	}
+
+	tests := []struct {
+		format  string
+		want    string
+		wantErr error
+	}{
+		{format: FormatText, want: "\nHistory:\n2024-01-02T00:00:00Z @a added label bug\n2024-01-03T00:00:00Z @b closed\n"},
+		{
+			format: FormatJSON,
+			want: `[
+  {
+    "created_at": "2024-01-02T00:00:00Z",
+    "actor": "@a",
+    "event": "labeled",
+    "detail": "added label bug"
+  },
+  {
+    "created_at": "2024-01-03T00:00:00Z",
+    "actor": "@b",
+    "event": "closed",
+    "detail": "closed"
+  }
+]
+`,
+		},
+		{format: FormatCSV, want: "created_at,actor,event,detail\n2024-01-02T00:00:00Z,@a,labeled,added label bug\n2024-01-03T00:00:00Z,@b,closed,closed\n"},
+		{format: "yaml", wantErr: errFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := PrintHistory(&buf, events, tt.format)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got\n%q\nwant\n%q", buf.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package issue
+
+import (
+	"strings"
+)
+
+type language struct {
+	comment  string
+	keywords map[string]bool
+}
+
+var languages = map[string]language{
+	"go":     {comment: "//", keywords: words(`break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var nil true false`)},
+	"js":     {comment: "//", keywords: words(`async await break case catch class const continue default delete do else export extends false finally for function if import in instanceof let new null return super switch this throw true try typeof undefined var void while yield`)},
+	"python": {comment: "#", keywords: words(`and as assert async await break class continue def del elif else except False finally for from global if import in is lambda None nonlocal not or pass raise return True try while with yield`)},
+	"sh":     {comment: "#", keywords: words(`case do done elif else esac export fi for function if in local return then until while`)},
+	"rust":   {comment: "//", keywords: words(`as async await break const continue crate else enum extern false fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while`)},
+	"c":      {comment: "//", keywords: words(`abstract auto bool break case catch char class const continue default delete do double else enum extends extern false final float for if implements import int long new null namespace package private protected public return short static struct switch this throw true try typedef unsigned using void volatile while`)},
+	"yaml":   {comment: "#", keywords: words(`true false null yes no`)},
+	"json":   {keywords: words(`true false null`)},
+}
+
+var languageAliases = map[string]string{
+	"golang": "go", "javascript": "js", "ts": "js", "typescript": "js", "jsx": "js", "tsx": "js",
+	"py": "python", "bash": "sh", "shell": "sh", "zsh": "sh", "console": "sh", "rs": "rust",
+	"cpp": "c", "c++": "c", "h": "c", "java": "c", "cs": "c", "csharp": "c", "kotlin": "c",
+	"yml": "yaml",
+}
+
+func words(list string) map[string]bool {
+	result := map[string]bool{}
+	for _, w := range strings.Fields(list) {
+		result[w] = true
+	}
+	return result
+}
+
+// highlight colours keywords, strings, numbers and line comments of a single
+// line of code. Unknown languages are returned untouched.
+func highlight(lang, line string) string {
+	lang = strings.ToLower(lang)
+	if alias, ok := languageAliases[lang]; ok {
+		lang = alias
+	}
+	l, ok := languages[lang]
+	if !ok {
+		return line
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case l.comment != "" && strings.HasPrefix(line[i:], l.comment):
+			b.WriteString(styleComment + line[i:] + styleReset)
+			return b.String()
+
+		case c == '"' || c == '\'' || c == '`':
+			end := i + 1
+			for end < len(line) && line[end] != c {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(line))
+			b.WriteString(styleString + line[i:end] + styleReset)
+			i = end
+
+		case isDigit(c) && (i == 0 || !isWordByte(line[i-1])):
+			end := i
+			for end < len(line) && (isWordByte(line[end]) || line[end] == '.') {
+				end++
+			}
+			b.WriteString(styleNumber + line[i:end] + styleReset)
+			i = end
+
+		case isWordByte(c):
+			end := i
+			for end < len(line) && isWordByte(line[end]) {
+				end++
+			}
+			word := line[i:end]
+			if l.keywords[word] {
+				word = styleKeyword + word + styleReset
+			}
+			b.WriteString(word)
+			i = end
+
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,142 @@
+package issue
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+)
+
+// historyPageSize is the largest page the timeline endpoint returns.
+const historyPageSize = 100
+
+// HistoryEvents are the timeline events History reports.
+var HistoryEvents = []string{
+	"labeled", "unlabeled", "assigned", "unassigned", "closed", "reopened",
+	"renamed", "referenced", "cross-referenced",
+}
+
+// HistoryIssue reads the timeline of an issue.
+type HistoryIssue interface {
+	History(number int) ([]domain.Event, error)
+}
+
+type HistoryFeature struct {
+	config *domain.Config
+	client client.GitHubClient
+}
+
+func NewHistory(config *domain.Config, client client.GitHubClient) *HistoryFeature {
+	return &HistoryFeature{
+		config: config,
+		client: client,
+	}
+}
+
+// History returns the events of HistoryEvents in chronological order. Every
+// page of the timeline is read.
+func (f *HistoryFeature) History(number int) ([]domain.Event, error) {
+	if number == 0 {
+		return nil, errNumberIsRequered
+	}
+
+	wanted := map[string]bool{}
+	for _, name := range HistoryEvents {
+		wanted[name] = true
+	}
+
+	var events []domain.Event
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/timeline?per_page=%d&page=%d",
+			f.config.APIBaseURL, f.config.Owner, f.config.Repo, number, historyPageSize, page)
+
+		response, err := f.client.MakeRequest("GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		pageEvents := []domain.Event{}
+		if err = json.Unmarshal(response, &pageEvents); err != nil {
+			return nil, errProcessing
+		}
+		for _, event := range pageEvents {
+			if wanted[event.Event] {
+				events = append(events, event)
+			}
+		}
+		if len(pageEvents) < historyPageSize {
+			break
+		}
+	}
+
+	// timestamps are RFC 3339 in UTC, so they sort as strings
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreatedAt < events[j].CreatedAt
+	})
+	return events, nil
+}
+
+// EventDetail describes what the event changed.
+func EventDetail(event domain.Event) string {
+	switch event.Event {
+	case "labeled":
+		return "added label " + labelName(event.Label)
+	case "unlabeled":
+		return "removed label " + labelName(event.Label)
+	case "assigned":
+		return "assigned " + userLogin(event.Assignee)
+	case "unassigned":
+		return "unassigned " + userLogin(event.Assignee)
+	case "closed":
+		if event.StateReason != "" {
+			return "closed as " + event.StateReason
+		}
+		return "closed"
+	case "reopened":
+		return "reopened"
+	case "renamed":
+		if event.Rename == nil {
+			return "renamed"
+		}
+		return fmt.Sprintf("renamed from %q to %q", event.Rename.From, event.Rename.To)
+	case "referenced":
+		return "referenced in commit " + shortCommit(event.CommitID)
+	case "cross-referenced":
+		if event.Source == nil || event.Source.Issue == nil {
+			return "referenced"
+		}
+		if ref, ok := ParseIssueRef(event.Source.Issue.HTMLURL); ok {
+			return "referenced from " + ref.String()
+		}
+		return fmt.Sprintf("referenced from #%d", event.Source.Issue.Number)
+	}
+	return event.Event
+}
+
+// EventActor is the login of who triggered the event.
+func EventActor(event domain.Event) string {
+	return userLogin(event.Actor)
+}
+
+func labelName(label *domain.Label) string {
+	if label == nil {
+		return ""
+	}
+	return label.Name
+}
+
+func userLogin(user *domain.User) string {
+	if user == nil || user.Login == "" {
+		return "ghost"
+	}
+	return "@" + user.Login
+}
+
+func shortCommit(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,110 @@
+package issue
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+func TestHistoryFeature(t *testing.T) {
+	cfg := &domain.Config{APIBaseURL: "https://api.example.com", Owner: "owner", Repo: "repo"}
+
+	// a full first page forces a second request
+	var full []string
+	for i := 0; i < historyPageSize-1; i++ {
+		full = append(full, `{"event":"commented","created_at":"2024-01-01T00:00:00Z"}`)
+	}
+	full = append(full, `{"event":"closed","actor":{"login":"b"},"created_at":"2024-01-03T00:00:00Z"}`)
+	pages := map[int]string{
+		1: "[" + strings.Join(full, ",") + "]",
+		2: `[{"event":"labeled","actor":{"login":"a"},"label":{"name":"bug"},"created_at":"2024-01-02T00:00:00Z"},
+			{"event":"subscribed","created_at":"2024-01-04T00:00:00Z"}]`,
+	}
+
+	var urls []string
+	f := NewHistory(cfg, &stubs.ClientStub{
+		MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+			urls = append(urls, url)
+			var page int
+			fmt.Sscanf(url[strings.LastIndex(url, "page=")+5:], "%d", &page)
+			return []byte(pages[page]), nil
+		},
+	})
+
+	events, err := f.History(7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantURL := "https://api.example.com/repos/owner/repo/issues/7/timeline?per_page=100&page=2"
+	if len(urls) != 2 || urls[1] != wantURL {
+		t.Errorf("unexpected requests: %v", urls)
+	}
+	if len(events) != 2 || events[0].Event != "labeled" || events[1].Event != "closed" {
+		t.Errorf("expected labeled then closed, got %+v", events)
+	}
+}
+
+func TestHistoryFeatureErrors(t *testing.T) {
+	cfg := &domain.Config{APIBaseURL: "https://api.example.com", Owner: "owner", Repo: "repo"}
+	fetchErr := errors.New("network")
+
+	tests := []struct {
+		name     string
+		number   int
+		response string
+		err      error
+		wantErr  error
+	}{
+		{name: "number required", wantErr: errNumberIsRequered},
+		{name: "request error forwarded", number: 1, err: fetchErr, wantErr: fetchErr},
+		{name: "invalid json", number: 1, response: `{`, wantErr: errProcessing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewHistory(cfg, &stubs.ClientStub{
+				MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+					return []byte(tt.response), tt.err
+				},
+			})
+			if _, err := f.History(tt.number); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEventDetail(t *testing.T) {
+	tests := []struct {
+		event domain.Event
+		want  string
+	}{
+		{domain.Event{Event: "labeled", Label: &domain.Label{Name: "bug"}}, "added label bug"},
+		{domain.Event{Event: "unlabeled", Label: &domain.Label{Name: "bug"}}, "removed label bug"},
+		{domain.Event{Event: "assigned", Assignee: &domain.User{Login: "a"}}, "assigned @a"},
+		{domain.Event{Event: "unassigned"}, "unassigned ghost"},
+		{domain.Event{Event: "closed", StateReason: "not_planned"}, "closed as not_planned"},
+		{domain.Event{Event: "closed"}, "closed"},
+		{domain.Event{Event: "reopened"}, "reopened"},
+		{domain.Event{Event: "renamed", Rename: &domain.Rename{From: "a", To: "b"}}, `renamed from "a" to "b"`},
+		{domain.Event{Event: "referenced", CommitID: "0123456789abcdef"}, "referenced in commit 0123456"},
+		{
+			domain.Event{Event: "cross-referenced", Source: &domain.Source{Issue: &domain.Issue{Number: 4, HTMLURL: "https://github.com/o/r/pull/4"}}},
+			"referenced from o/r#4",
+		},
+		{domain.Event{Event: "cross-referenced", Source: &domain.Source{Issue: &domain.Issue{Number: 4}}}, "referenced from #4"},
+		{domain.Event{Event: "milestoned"}, "milestoned"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := EventDetail(tt.event); got != tt.want {
+				t.Errorf("EventDetail() = %q want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package issue
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"git-issues/domain"
+	"git-issues/service/git"
+)
+
+const (
+	// HookName is the git hook install writes.
+	HookName   = "prepare-commit-msg"
+	hookMarker = "# installed by ghissues hooks install"
+	hookScript = "#!/bin/sh\n" + hookMarker + "\nexec %s hooks " + HookName + " \"$@\"\n"
+)
+
+// HookIssue installs and runs the hook that adds the issue of the branch to
+// commit messages.
+type HookIssue interface {
+	Install(command string, force bool) (string, error)
+	PrepareCommitMsg(file string, source string) error
+}
+
+type HookFeature struct {
+	config    *domain.Config
+	git       git.Git
+	namer     BranchNamer
+	namerErr  error
+	readFile  func(name string) ([]byte, error)
+	writeFile func(name string, data []byte, perm os.FileMode) error
+}
+
+func NewHook(config *domain.Config, git git.Git) *HookFeature {
+	f := &HookFeature{
+		config:    config,
+		git:       git,
+		readFile:  os.ReadFile,
+		writeFile: os.WriteFile,
+	}
+	f.namer, f.namerErr = NewBranchNamer(config.BranchTemplate)
+	return f
+}
+
+// Install writes the prepare-commit-msg hook running command, the path of
+// this executable, into the hooks directory of the repository. A hook that
+// was not installed by it is only replaced with force.
+func (f *HookFeature) Install(command string, force bool) (string, error) {
+	dir, err := f.git.HooksDir()
+	if err != nil {
+		return "", errors.Join(errHook, err)
+	}
+	path := filepath.Join(dir, HookName)
+
+	existing, err := f.readFile(path)
+	if err == nil && !force && !strings.Contains(string(existing), hookMarker) {
+		return path, fmt.Errorf("%w: %s", errHookExists, path)
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return path, errors.Join(errHook, err)
+	}
+	script := fmt.Sprintf(hookScript, shellQuote(command))
+	if err = f.writeFile(path, []byte(script), 0755); err != nil {
+		return path, errors.Join(errHook, err)
+	}
+	return path, nil
+}
+
+// PrepareCommitMsg appends Refs #n to the message in file when the branch
+// names issue n and the message does not reference it yet. Merges, squashes
+// and reused messages are left alone, as is any branch without an issue.
+func (f *HookFeature) PrepareCommitMsg(file string, source string) error {
+	switch source {
+	case "merge", "squash", "commit":
+		return nil
+	}
+	if f.namerErr != nil {
+		return f.namerErr
+	}
+	branch, err := f.git.CurrentBranch()
+	if err != nil {
+		// detached HEAD, like during a rebase
+		return nil
+	}
+	number, ok := f.namer.IssueNumber(branch)
+	if !ok {
+		return nil
+	}
+
+	data, err := f.readFile(file)
+	if err != nil {
+		return errors.Join(errHook, err)
+	}
+	message, err := appendRefs(string(data), IssueRef{Owner: f.config.Owner, Repo: f.config.Repo, Number: number})
+	if err != nil {
+		return nil
+	}
+	if err = f.writeFile(file, []byte(message), 0644); err != nil {
+		return errors.Join(errHook, err)
+	}
+	return nil
+}
+
+// appendRefs puts the Refs trailer after the message and before the
+// comments git adds for the editor. It fails when the message already
+// references the issue. An empty message for the editor gets a blank subject
+// line above the trailer, one without the comments is left as it is.
+func appendRefs(data string, ref IssueRef) (string, error) {
+	lines := strings.Split(data, "\n")
+	end := len(lines)
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			end = i
+			break
+		}
+	}
+	message := strings.TrimRight(strings.Join(lines[:end], "\n"), "\n ")
+	comments := strings.Join(lines[end:], "\n")
+
+	for _, r := range ParseReferences(message) {
+		if sameIssue(r.Ref.Resolve(ref.Owner, ref.Repo), ref) {
+			return "", errHookReferenced
+		}
+	}
+
+	trailer := fmt.Sprintf("Refs #%d\n", ref.Number)
+	if message == "" {
+		// without comments no editor opens, and an empty message stays
+		// empty so that git aborts the commit
+		if comments == "" {
+			return data, nil
+		}
+		// the editor opens on a blank subject line above the trailer
+		return "\n\n" + trailer + "\n" + comments, nil
+	}
+	message += "\n\n" + trailer
+	if comments != "" {
+		message += "\n" + comments
+	}
+	return message, nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,145 @@
+package issue
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+func TestHookInstall(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		force    bool
+		wantErr  error
+	}{
+		{name: "new hook"},
+		{name: "replaces its own hook", existing: "#!/bin/sh\n" + hookMarker + "\nexec old\n"},
+		{name: "keeps other hooks", existing: "#!/bin/sh\nlint\n", wantErr: errHookExists},
+		{name: "force", existing: "#!/bin/sh\nlint\n", force: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "hooks")
+			path := filepath.Join(dir, HookName)
+			if tt.existing != "" {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.existing), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			gitStub := &stubs.GitStub{HooksDirFunc: func() (string, error) { return dir, nil }}
+
+			got, err := NewHook(&domain.Config{}, gitStub).Install("/opt/it's/ghissues", tt.force)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v want %v", err, tt.wantErr)
+			}
+			if got != path {
+				t.Errorf("got path %q want %q", got, path)
+			}
+			data, _ := os.ReadFile(path)
+			want := "#!/bin/sh\n" + hookMarker + "\nexec '/opt/it'\\''s/ghissues' hooks prepare-commit-msg \"$@\"\n"
+			if tt.wantErr != nil {
+				want = tt.existing
+			}
+			if string(data) != want {
+				t.Errorf("got hook %q want %q", data, want)
+			}
+		})
+	}
+}
+
+func TestPrepareCommitMsg(t *testing.T) {
+	const comments = "# Please enter the commit message for your changes.\n# On branch 12-fix-login\n"
+
+	tests := []struct {
+		name    string
+		branch  string
+		source  string
+		message string
+		want    string
+	}{
+		{
+			name:    "empty editor message",
+			branch:  "12-fix-login",
+			message: "\n" + comments,
+			want:    "\n\nRefs #12\n\n" + comments,
+		},
+		{
+			name:    "empty message",
+			branch:  "12-fix-login",
+			source:  "message",
+			message: "",
+			want:    "",
+		},
+		{
+			name:    "prefilled editor message",
+			branch:  "12-fix-login",
+			message: "Fix the login\n" + comments,
+			want:    "Fix the login\n\nRefs #12\n\n" + comments,
+		},
+		{
+			name:    "message flag",
+			branch:  "12-fix-login",
+			source:  "message",
+			message: "Fix the login\n",
+			want:    "Fix the login\n\nRefs #12\n",
+		},
+		{
+			name:    "body and comments",
+			branch:  "12-fix-login",
+			source:  "template",
+			message: "Fix the login\n\nThe token expired.\n\n" + comments,
+			want:    "Fix the login\n\nThe token expired.\n\nRefs #12\n\n" + comments,
+		},
+		{
+			name:    "already referenced",
+			branch:  "12-fix-login",
+			source:  "message",
+			message: "Fix the login\n\nFixes #12\n",
+			want:    "Fix the login\n\nFixes #12\n",
+		},
+		{
+			name:    "merge",
+			branch:  "12-fix-login",
+			source:  "merge",
+			message: "Merge branch 'main'\n",
+			want:    "Merge branch 'main'\n",
+		},
+		{
+			name:    "branch without issue",
+			branch:  "main",
+			source:  "message",
+			message: "Update\n",
+			want:    "Update\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+			if err := os.WriteFile(file, []byte(tt.message), 0644); err != nil {
+				t.Fatal(err)
+			}
+			gitStub := &stubs.GitStub{CurrentBranchFunc: func() (string, error) { return tt.branch, nil }}
+
+			err := NewHook(&domain.Config{Owner: "owner", Repo: "repo"}, gitStub).PrepareCommitMsg(file, tt.source)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			data, _ := os.ReadFile(file)
+			if string(data) != tt.want {
+				t.Errorf("got %q want %q", data, tt.want)
+			}
+		})
+	}
+}
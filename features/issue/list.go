@@ -2,12 +2,22 @@ package issue
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
 	"git-issues/domain"
 	"git-issues/service/client"
 )
 
+// listPageSize is the largest page of the issues endpoint, asked for when
+// every issue is fetched.
+const listPageSize = 100
+
 type ListIssue interface {
 	List() ([]domain.Issue, error)
 }
@@ -15,8 +25,26 @@ type ListIssue interface {
 type ListFeature struct {
 	config *domain.Config
 	client client.GitHubClient
+
+	// Filter narrows List and ListRepos, all open issues when empty.
+	Filter ListFilter
+	// Sort is one of ListSorts, the api order when empty. Updated and
+	// created are left to the api.
+	Sort string
+}
+
+// ListFilter holds the filters of the issues endpoint. State is open, closed
+// or all; Assignee a login, @me for the user of the token, none or *; Labels
+// a comma separated list of labels the issues all have.
+type ListFilter struct {
+	State    string
+	Assignee string
+	Labels   string
 }
 
+// ListStates are the states ListFilter takes.
+var ListStates = []string{"open", "closed", "all"}
+
 func NewList(config *domain.Config, client client.GitHubClient) *ListFeature {
 	return &ListFeature{
 		config: config,
@@ -25,9 +53,99 @@ func NewList(config *domain.Config, client client.GitHubClient) *ListFeature {
 }
 
 func (f *ListFeature) List() ([]domain.Issue, error) {
-	url := fmt.Sprintf("%s/repos/%s/%s/issues", f.config.APIBaseURL, f.config.Owner, f.config.Repo)
+	query, err := f.query()
+	if err != nil {
+		return nil, err
+	}
+	issues, err := f.list(f.config.Owner, f.config.Repo, query)
+	if err != nil {
+		return nil, err
+	}
+	if f.Sort == "reactions" {
+		SortIssues(issues, f.Sort)
+	}
+	return issues, nil
+}
+
+// ListRepos lists the issues of several owner/repo repositories at once and
+// merges them ordered by repository, newest first, or by Sort across the
+// repositories. Every issue carries its repository in Repo.
+func (f *ListFeature) ListRepos(repos []string) ([]domain.Issue, error) {
+	for _, repo := range repos {
+		if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("%w: %q", errRepoName, repo)
+		}
+	}
+	query, err := f.query()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]domain.Issue, len(repos))
+	errs := make([]error, len(repos))
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		go func(i int, repo string) {
+			defer wg.Done()
+			owner, name, _ := strings.Cut(repo, "/")
+			issues, err := f.list(owner, name, query)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", repo, err)
+				return
+			}
+			for j := range issues {
+				issues[j].Repo = repo
+			}
+			results[i] = issues
+		}(i, repo)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	var issues []domain.Issue
+	for _, r := range results {
+		issues = append(issues, r...)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Repo != issues[j].Repo {
+			return issues[i].Repo < issues[j].Repo
+		}
+		return issues[i].Number > issues[j].Number
+	})
+	SortIssues(issues, f.Sort)
+	return issues, nil
+}
+
+// list fetches the issues of a repository, query being the encoded filter.
+// The api cannot order by reactions, so to rank them every page is fetched.
+func (f *ListFeature) list(owner, repo, query string) ([]domain.Issue, error) {
+	listURL := fmt.Sprintf("%s/repos/%s/%s/issues", f.config.APIBaseURL, owner, repo)
+	if query != "" {
+		listURL += "?" + query
+	}
+	if f.Sort != "reactions" {
+		return f.page(listURL)
+	}
+
+	issues := []domain.Issue{}
+	for page := 1; ; page++ {
+		found, err := f.page(fmt.Sprintf("%s&page=%d", listURL, page))
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, found...)
+		if len(found) < listPageSize {
+			return issues, nil
+		}
+	}
+}
 
-	response, err := f.client.MakeRequest("GET", url, nil)
+func (f *ListFeature) page(pageURL string) ([]domain.Issue, error) {
+	response, err := f.client.MakeRequest("GET", pageURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -39,3 +157,94 @@ func (f *ListFeature) List() ([]domain.Issue, error) {
 
 	return issues, nil
 }
+
+// query encodes the filter and order, looking up the login @me stands for.
+func (f *ListFeature) query() (string, error) {
+	values := url.Values{}
+	switch f.Sort {
+	case "":
+	case "reactions":
+		values.Set("per_page", strconv.Itoa(listPageSize))
+	case "updated", "created":
+		values.Set("sort", f.Sort)
+		values.Set("direction", "desc")
+	default:
+		return "", fmt.Errorf("%w: %q, use %s", errSort, f.Sort, strings.Join(ListSorts, ", "))
+	}
+	if f.Filter.State != "" {
+		valid := false
+		for _, state := range ListStates {
+			valid = valid || state == f.Filter.State
+		}
+		if !valid {
+			return "", fmt.Errorf("%w: %q, use %s", errState, f.Filter.State, strings.Join(ListStates, ", "))
+		}
+		values.Set("state", f.Filter.State)
+	}
+	if f.Filter.Assignee == "@me" {
+		login, err := me(f.config, f.client)
+		if err != nil {
+			return "", err
+		}
+		f.Filter.Assignee = login
+	}
+	if f.Filter.Assignee != "" {
+		values.Set("assignee", f.Filter.Assignee)
+	}
+	if f.Filter.Labels != "" {
+		values.Set("labels", f.Filter.Labels)
+	}
+	return values.Encode(), nil
+}
+
+// ListSorts are the orders SortIssues knows. Reactions ranks by 👍, then by
+// all reactions; updated and created put the latest first.
+var ListSorts = []string{"reactions", "updated", "created"}
+
+// SortIssues orders the issues in place. An empty order keeps the api order.
+func SortIssues(issues []domain.Issue, by string) error {
+	switch by {
+	case "":
+		return nil
+	case "reactions":
+		sort.SliceStable(issues, func(i, j int) bool {
+			a, b := upvotes(&issues[i]), upvotes(&issues[j])
+			if a != b {
+				return a > b
+			}
+			return totalReactions(&issues[i]) > totalReactions(&issues[j])
+		})
+		return nil
+	case "updated":
+		sort.SliceStable(issues, func(i, j int) bool {
+			return latest(issues[i].UpdatedAt, issues[j].UpdatedAt)
+		})
+		return nil
+	case "created":
+		sort.SliceStable(issues, func(i, j int) bool {
+			return latest(issues[i].CreatedAt, issues[j].CreatedAt)
+		})
+		return nil
+	}
+	return fmt.Errorf("%w: %q, use %s", errSort, by, strings.Join(ListSorts, ", "))
+}
+
+// latest tells whether the timestamp a is after b. Timestamps of the api are
+// RFC 3339 in UTC, so they order as text.
+func latest(a, b string) bool {
+	return a > b
+}
+
+func upvotes(issue *domain.Issue) int {
+	if issue.Reactions == nil {
+		return 0
+	}
+	return issue.Reactions.PlusOne
+}
+
+func totalReactions(issue *domain.Issue) int {
+	if issue.Reactions == nil {
+		return 0
+	}
+	return issue.Reactions.TotalCount
+}
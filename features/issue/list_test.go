@@ -2,6 +2,10 @@ package issue
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
 	"git-issues/domain"
@@ -110,3 +114,169 @@ func TestListFeature(t *testing.T) {
 		})
 	}
 }
+
+func TestListRepos(t *testing.T) {
+	cfg := &domain.Config{APIBaseURL: "https://api.example.com", Owner: "owner", Repo: "repo"}
+
+	responses := map[string]string{
+		"https://api.example.com/repos/b/two/issues":                             `[{"number":3,"title":"b3","state":"open","created_at":"2024-03-01T00:00:00Z"},{"number":9,"title":"b9","state":"open","created_at":"2024-01-01T00:00:00Z"}]`,
+		"https://api.example.com/repos/a/one/issues":                             `[{"number":5,"title":"a5","state":"open","created_at":"2024-02-01T00:00:00Z"}]`,
+		"https://api.example.com/repos/b/two/issues?direction=desc&sort=created": `[{"number":3,"title":"b3","state":"open","created_at":"2024-03-01T00:00:00Z"},{"number":9,"title":"b9","state":"open","created_at":"2024-01-01T00:00:00Z"}]`,
+		"https://api.example.com/repos/a/one/issues?direction=desc&sort=created": `[{"number":5,"title":"a5","state":"open","created_at":"2024-02-01T00:00:00Z"}]`,
+	}
+	var mu sync.Mutex
+	stub := &stubs.ClientStub{
+		MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			response, ok := responses[url]
+			if !ok {
+				return nil, domain.ErrApi
+			}
+			return []byte(response), nil
+		},
+	}
+	f := NewList(cfg, stub)
+
+	issues, err := f.ListRepos([]string{"b/two", "a/one"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, i := range issues {
+		got = append(got, fmt.Sprintf("%s#%d", i.Repo, i.Number))
+	}
+	want := []string{"a/one#5", "b/two#9", "b/two#3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	f.Sort = "created"
+	issues, err = f.ListRepos([]string{"b/two", "a/one"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got = nil
+	for _, i := range issues {
+		got = append(got, fmt.Sprintf("%s#%d", i.Repo, i.Number))
+	}
+	want = []string{"b/two#3", "a/one#5", "b/two#9"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted by created: got %v want %v", got, want)
+	}
+	f.Sort = ""
+
+	if _, err = f.ListRepos([]string{"a/one", "c/missing"}); !errors.Is(err, domain.ErrApi) || !strings.Contains(err.Error(), "c/missing") {
+		t.Errorf("expected the failing repository in the error, got %v", err)
+	}
+	for _, repo := range []string{"noslash", "/x", "a/b/c"} {
+		if _, err = f.ListRepos([]string{repo}); !errors.Is(err, errRepoName) {
+			t.Errorf("%q: expected errRepoName, got %v", repo, err)
+		}
+	}
+}
+
+func TestListReactions(t *testing.T) {
+	cfg := &domain.Config{APIBaseURL: "https://api.example.com", Owner: "owner", Repo: "repo"}
+
+	var first []string
+	for n := 200; n > 100; n-- {
+		first = append(first, fmt.Sprintf(`{"number":%d,"state":"open"}`, n))
+	}
+	pages := map[string]string{
+		"https://api.example.com/repos/owner/repo/issues?per_page=100&page=1": "[" + strings.Join(first, ",") + "]",
+		"https://api.example.com/repos/owner/repo/issues?per_page=100&page=2": `[{"number":7,"state":"open","reactions":{"+1":4,"total_count":4}}]`,
+	}
+	var requests int
+	stub := &stubs.ClientStub{
+		MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+			requests++
+			page, ok := pages[url]
+			if !ok {
+				return nil, domain.ErrApi
+			}
+			return []byte(page), nil
+		},
+	}
+	f := NewList(cfg, stub)
+	f.Sort = "reactions"
+
+	issues, err := f.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 || len(issues) != 101 {
+		t.Fatalf("got %d issues in %d requests, want 101 in 2", len(issues), requests)
+	}
+	if issues[0].Number != 7 || issues[1].Number != 200 {
+		t.Errorf("got #%d then #%d first, want #7 then #200", issues[0].Number, issues[1].Number)
+	}
+}
+
+func TestListFilter(t *testing.T) {
+	cfg := &domain.Config{APIBaseURL: "https://api.example.com", Owner: "owner", Repo: "repo"}
+
+	tests := []struct {
+		name    string
+		filter  ListFilter
+		sort    string
+		wantURL string
+		wantErr error
+	}{
+		{name: "no filter", wantURL: "https://api.example.com/repos/owner/repo/issues"},
+		{
+			name:    "state and assignee",
+			filter:  ListFilter{State: "all", Assignee: "octocat"},
+			wantURL: "https://api.example.com/repos/owner/repo/issues?assignee=octocat&state=all",
+		},
+		{
+			name:    "labels",
+			filter:  ListFilter{Labels: "bug,ui"},
+			wantURL: "https://api.example.com/repos/owner/repo/issues?labels=bug%2Cui",
+		},
+		{
+			name:    "assigned to me",
+			filter:  ListFilter{Assignee: "@me"},
+			wantURL: "https://api.example.com/repos/owner/repo/issues?assignee=mona",
+		},
+		{
+			name:    "recently updated",
+			sort:    "updated",
+			wantURL: "https://api.example.com/repos/owner/repo/issues?direction=desc&sort=updated",
+		},
+		{
+			name:    "reactions pages through every issue",
+			sort:    "reactions",
+			wantURL: "https://api.example.com/repos/owner/repo/issues?per_page=100&page=1",
+		},
+		{name: "unknown state", filter: ListFilter{State: "merged"}, wantErr: errState},
+		{name: "unknown sort", sort: "votes", wantErr: errSort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotURL string
+			stub := &stubs.ClientStub{
+				MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+					if url == "https://api.example.com/user" {
+						return []byte(`{"login":"mona"}`), nil
+					}
+					gotURL = url
+					return []byte(`[]`), nil
+				},
+			}
+			f := NewList(cfg, stub)
+			f.Filter = tt.filter
+			f.Sort = tt.sort
+
+			_, err := f.List()
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v want %v", err, tt.wantErr)
+			}
+			if gotURL != tt.wantURL {
+				t.Errorf("got url %q want %q", gotURL, tt.wantURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package issue
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+)
+
+// LockReasons are the reasons the api accepts when locking an issue.
+var LockReasons = []string{"off-topic", "too heated", "resolved", "spam"}
+
+type LockIssue interface {
+	Lock(number int, reason string) error
+	Unlock(number int) error
+}
+
+type LockFeature struct {
+	config *domain.Config
+	client client.GitHubClient
+}
+
+type lockPayload struct {
+	LockReason string `json:"lock_reason,omitempty"`
+}
+
+func NewLock(config *domain.Config, client client.GitHubClient) *LockFeature {
+	return &LockFeature{
+		config: config,
+		client: client,
+	}
+}
+
+func (f *LockFeature) Lock(number int, reason string) error {
+	if number == 0 {
+		return errNumberIsRequered
+	}
+
+	reason, err := normalizeLockReason(reason)
+	if err != nil {
+		return err
+	}
+
+	var payload interface{}
+	if reason != "" {
+		payload = lockPayload{LockReason: reason}
+	}
+
+	_, err = f.client.MakeJSONRequest("PUT", f.lockURL(number), payload)
+	if err != nil {
+		return errors.Join(errLock, err)
+	}
+	return nil
+}
+
+func (f *LockFeature) Unlock(number int) error {
+	if number == 0 {
+		return errNumberIsRequered
+	}
+
+	_, err := f.client.MakeJSONRequest("DELETE", f.lockURL(number), nil)
+	if err != nil {
+		return errors.Join(errUnlock, err)
+	}
+	return nil
+}
+
+func (f *LockFeature) lockURL(number int) string {
+	return fmt.Sprintf("%s/repos/%s/%s/issues/%d/lock", f.config.APIBaseURL, f.config.Owner, f.config.Repo, number)
+}
+
+// normalizeLockReason accepts the reasons with dashes or spaces, so
+// "too-heated" works without quoting on the command line.
+func normalizeLockReason(reason string) (string, error) {
+	if reason == "" {
+		return "", nil
+	}
+
+	normalized := strings.ToLower(strings.TrimSpace(reason))
+	for _, r := range LockReasons {
+		if normalized == r || normalized == strings.ReplaceAll(r, " ", "-") {
+			return r, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q (use %s)", errLockReason, reason, strings.Join(LockReasons, ", "))
+}
@@ -0,0 +1,113 @@
+package issue
+
+import (
+	"errors"
+	"testing"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+func TestLockFeature(t *testing.T) {
+	cfg := &domain.Config{
+		APIBaseURL: "https://api.example.com",
+		Owner:      "owner",
+		Repo:       "repo",
+	}
+
+	tests := []struct {
+		name        string
+		number      int
+		reason      string
+		unlock      bool
+		requestErr  error
+		wantMethod  string
+		wantPayload interface{}
+		wantErr     error
+	}{
+		{
+			name:       "lock without reason",
+			number:     1,
+			wantMethod: "PUT",
+		},
+		{
+			name:        "lock with dashed reason",
+			number:      1,
+			reason:      "too-heated",
+			wantMethod:  "PUT",
+			wantPayload: lockPayload{LockReason: "too heated"},
+		},
+		{
+			name:        "lock with reason",
+			number:      1,
+			reason:      "Spam",
+			wantMethod:  "PUT",
+			wantPayload: lockPayload{LockReason: "spam"},
+		},
+		{
+			name:    "invalid reason",
+			number:  1,
+			reason:  "boring",
+			wantErr: errLockReason,
+		},
+		{
+			name:    "number required",
+			wantErr: errNumberIsRequered,
+		},
+		{
+			name:       "request error",
+			number:     1,
+			requestErr: domain.ErrApi,
+			wantMethod: "PUT",
+			wantErr:    errLock,
+		},
+		{
+			name:       "unlock",
+			number:     1,
+			unlock:     true,
+			wantMethod: "DELETE",
+		},
+		{
+			name:       "unlock error",
+			number:     1,
+			unlock:     true,
+			requestErr: domain.ErrApi,
+			wantMethod: "DELETE",
+			wantErr:    domain.ErrApi,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotMethod, gotURL string
+			var gotPayload interface{}
+			clientStub := &stubs.ClientStub{
+				MakeJSONRequestFunc: func(method, url string, payload interface{}) ([]byte, error) {
+					gotMethod, gotURL, gotPayload = method, url, payload
+					return nil, tt.requestErr
+				},
+			}
+			f := NewLock(cfg, clientStub)
+
+			var err error
+			if tt.unlock {
+				err = f.Unlock(tt.number)
+			} else {
+				err = f.Lock(tt.number, tt.reason)
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error: got %v want %v", err, tt.wantErr)
+			}
+			if gotMethod != tt.wantMethod {
+				t.Errorf("unexpected method: got %q want %q", gotMethod, tt.wantMethod)
+			}
+			if tt.wantMethod != "" && gotURL != "https://api.example.com/repos/owner/repo/issues/1/lock" {
+				t.Errorf("unexpected url: %q", gotURL)
+			}
+			if gotPayload != tt.wantPayload {
+				t.Errorf("unexpected payload: got %#v want %#v", gotPayload, tt.wantPayload)
+			}
+		})
+	}
+}
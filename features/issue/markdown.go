@@ -0,0 +1,594 @@
+package issue
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	styleReset     = "\x1b[0m"
+	styleBold      = "\x1b[1m"
+	styleDim       = "\x1b[2m"
+	styleItalic    = "\x1b[3m"
+	styleUnderline = "\x1b[4m"
+	styleStrike    = "\x1b[9m"
+	styleHeading   = "\x1b[1;36m"
+	styleLink      = "\x1b[4;34m"
+	styleCode      = "\x1b[33m"
+	styleKeyword   = "\x1b[35m"
+	styleString    = "\x1b[32m"
+	styleNumber    = "\x1b[33m"
+	styleComment   = "\x1b[90m"
+	styleChecked   = "\x1b[32m"
+)
+
+var (
+	reANSI        = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	reHeading     = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	reRule        = regexp.MustCompile(`^ {0,3}([-*_])(\s*([-*_]))*\s*$`)
+	reListItem    = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	reTask        = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	reTableDelim  = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	reFence       = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})\\s*([\\w+#-]*)")
+	bulletMarkers = []string{"•", "◦", "▪"}
+)
+
+// MarkdownOptions controls how issue bodies and comments are rendered for the
+// terminal.
+type MarkdownOptions struct {
+	// Width wraps paragraphs at the given number of columns; zero disables
+	// wrapping.
+	Width int
+	// Color enables ANSI styles and syntax highlighting.
+	Color bool
+}
+
+// RenderMarkdown renders GitHub flavoured markdown as terminal text.
+func RenderMarkdown(src string, opts MarkdownOptions) string {
+	r := &markdownRenderer{opts: opts}
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+	return strings.Join(r.blocks(strings.Split(src, "\n"), opts.Width), "\n")
+}
+
+type markdownRenderer struct {
+	opts MarkdownOptions
+}
+
+// blocks renders a sequence of lines, separating each block with an empty
+// line.
+func (r *markdownRenderer) blocks(lines []string, width int) []string {
+	var blocks [][]string
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+			continue
+
+		case reFence.MatchString(line):
+			match := reFence.FindStringSubmatch(line)
+			fence, lang := match[1], match[2]
+			var code []string
+			i++
+			for ; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+					i++
+					break
+				}
+				code = append(code, lines[i])
+			}
+			blocks = append(blocks, r.code(lang, code))
+			continue
+
+		case reHeading.MatchString(line):
+			match := reHeading.FindStringSubmatch(line)
+			blocks = append(blocks, r.heading(len(match[1]), match[2]))
+			i++
+			continue
+
+		case isRule(line):
+			blocks = append(blocks, []string{r.style(styleDim, strings.Repeat("─", ruleWidth(width)))})
+			i++
+			continue
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(q, " "))
+			}
+			blocks = append(blocks, r.quote(quoted, width))
+			continue
+
+		case isTableRow(line) && i+1 < len(lines) && reTableDelim.MatchString(lines[i+1]) && strings.Contains(lines[i+1], "-"):
+			rows := [][]string{splitTableRow(line)}
+			align := tableAlignment(lines[i+1])
+			for i += 2; i < len(lines) && isTableRow(lines[i]); i++ {
+				rows = append(rows, splitTableRow(lines[i]))
+			}
+			blocks = append(blocks, r.table(rows, align))
+			continue
+
+		case reListItem.MatchString(line):
+			var items []string
+			for ; i < len(lines); i++ {
+				current := lines[i]
+				if strings.TrimSpace(current) == "" {
+					// a blank line only continues the list when another item follows
+					if i+1 < len(lines) && reListItem.MatchString(lines[i+1]) {
+						continue
+					}
+					break
+				}
+				if reListItem.MatchString(current) {
+					items = append(items, current)
+					continue
+				}
+				if len(items) == 0 || startsBlock(current) {
+					break
+				}
+				items[len(items)-1] += " " + strings.TrimSpace(current)
+			}
+			blocks = append(blocks, r.list(items, width))
+			continue
+		}
+
+		var paragraph []string
+		for ; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "" || (len(paragraph) > 0 && startsBlock(lines[i])) {
+				break
+			}
+			paragraph = append(paragraph, lines[i])
+		}
+		blocks = append(blocks, r.paragraph(paragraph, width))
+	}
+
+	var out []string
+	for idx, block := range blocks {
+		if idx > 0 {
+			out = append(out, "")
+		}
+		out = append(out, block...)
+	}
+	return out
+}
+
+func (r *markdownRenderer) heading(level int, text string) []string {
+	text = r.inline(text)
+	if !r.opts.Color {
+		switch level {
+		case 1:
+			return []string{text, strings.Repeat("=", visibleWidth(text))}
+		case 2:
+			return []string{text, strings.Repeat("-", visibleWidth(text))}
+		}
+		return []string{strings.Repeat("#", level) + " " + text}
+	}
+
+	if level == 1 {
+		return []string{styleUnderline + styleHeading + text + styleReset}
+	}
+	if level == 2 {
+		return []string{styleHeading + text + styleReset}
+	}
+	return []string{styleBold + text + styleReset}
+}
+
+func (r *markdownRenderer) paragraph(lines []string, width int) []string {
+	var out []string
+	var current []string
+	for _, line := range lines {
+		hardBreak := strings.HasSuffix(line, "  ") || strings.HasSuffix(line, "\\")
+		current = append(current, strings.TrimSuffix(strings.TrimSpace(line), "\\"))
+		if hardBreak {
+			out = append(out, wrapText(r.inline(strings.Join(current, " ")), width)...)
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		out = append(out, wrapText(r.inline(strings.Join(current, " ")), width)...)
+	}
+	return out
+}
+
+func (r *markdownRenderer) quote(lines []string, width int) []string {
+	bar := r.style(styleDim, "│") + " "
+	inner := r.blocks(lines, width-2)
+
+	out := make([]string, 0, len(inner))
+	for _, line := range inner {
+		if r.opts.Color && line != "" {
+			line = styleItalic + line + styleReset
+		}
+		out = append(out, bar+line)
+	}
+	return out
+}
+
+func (r *markdownRenderer) list(items []string, width int) []string {
+	var out []string
+	counters := map[int]int{}
+
+	for _, item := range items {
+		match := reListItem.FindStringSubmatch(item)
+		level := len(match[1]) / 2
+		marker, text := match[2], match[3]
+
+		for l := range counters {
+			if l > level {
+				delete(counters, l)
+			}
+		}
+
+		switch {
+		case reTask.MatchString(text):
+			task := reTask.FindStringSubmatch(text)
+			text = task[2]
+			if task[1] == " " {
+				marker = "☐"
+			} else {
+				marker = r.style(styleChecked, "☑")
+			}
+		case marker[0] >= '0' && marker[0] <= '9':
+			if _, ok := counters[level]; !ok {
+				n, _ := strconv.Atoi(strings.TrimRight(marker, ".)"))
+				counters[level] = n - 1
+			}
+			counters[level]++
+			marker = strconv.Itoa(counters[level]) + "."
+		default:
+			marker = bulletMarkers[min(level, len(bulletMarkers)-1)]
+		}
+
+		indent := strings.Repeat("  ", level)
+		prefix := indent + marker + " "
+		hanging := strings.Repeat(" ", visibleWidth(prefix))
+
+		wrapped := wrapText(r.inline(text), width-visibleWidth(prefix))
+		for idx, line := range wrapped {
+			if idx == 0 {
+				out = append(out, prefix+line)
+				continue
+			}
+			out = append(out, hanging+line)
+		}
+	}
+	return out
+}
+
+func (r *markdownRenderer) table(rows [][]string, align []string) []string {
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+
+	rendered := make([][]string, len(rows))
+	widths := make([]int, columns)
+	for i, row := range rows {
+		rendered[i] = make([]string, columns)
+		for c := 0; c < columns; c++ {
+			var cell string
+			if c < len(row) {
+				cell = r.inline(row[c])
+			}
+			if i == 0 {
+				cell = r.style(styleBold, cell)
+			}
+			rendered[i][c] = cell
+			widths[c] = max(widths[c], visibleWidth(cell))
+		}
+	}
+
+	var out []string
+	for i, row := range rendered {
+		cells := make([]string, columns)
+		for c, cell := range row {
+			a := ""
+			if c < len(align) {
+				a = align[c]
+			}
+			cells[c] = " " + pad(cell, widths[c], a) + " "
+		}
+		out = append(out, strings.TrimRight(strings.Join(cells, "│"), " "))
+
+		if i == 0 {
+			rules := make([]string, columns)
+			for c := range widths {
+				rules[c] = strings.Repeat("─", widths[c]+2)
+			}
+			out = append(out, strings.Join(rules, "┼"))
+		}
+	}
+	return out
+}
+
+func (r *markdownRenderer) code(lang string, lines []string) []string {
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if r.opts.Color {
+			line = highlight(lang, line)
+		}
+		out = append(out, strings.TrimRight("    "+line, " "))
+	}
+	return out
+}
+
+// inline renders emphasis, code spans and links in a single line of text.
+func (r *markdownRenderer) inline(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		rest := s[i:]
+
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.ContainsRune("\\`*_{}[]()#+-.!|~<>", rune(s[i+1])):
+			b.WriteByte(s[i+1])
+			i += 2
+			continue
+
+		case c == '`':
+			ticks := len(rest) - len(strings.TrimLeft(rest, "`"))
+			fence := rest[:ticks]
+			if end := strings.Index(rest[ticks:], fence); end >= 0 {
+				code := strings.TrimSpace(rest[ticks : ticks+end])
+				if r.opts.Color {
+					b.WriteString(r.style(styleCode, code))
+				} else {
+					b.WriteString("`" + code + "`")
+				}
+				i += ticks*2 + end
+				continue
+			}
+
+		case strings.HasPrefix(rest, "!["), c == '[':
+			image := c == '!'
+			start := 1
+			if image {
+				start = 2
+			}
+			if text, url, n, ok := parseLink(rest[start:]); ok {
+				text = r.inline(text)
+				if image {
+					text = "image: " + text
+				}
+				b.WriteString(r.link(text, url))
+				i += start + n
+				continue
+			}
+
+		case c == '<':
+			if end := strings.IndexByte(rest, '>'); end > 0 {
+				url := rest[1:end]
+				if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+					b.WriteString(r.style(styleLink, url))
+					i += end + 1
+					continue
+				}
+			}
+
+		case strings.HasPrefix(rest, "**"), strings.HasPrefix(rest, "__"):
+			if inner, n, ok := delimited(rest, rest[:2], i == 0 || !isWordByte(s[i-1])); ok {
+				b.WriteString(r.style(styleBold, r.inline(inner)))
+				i += n
+				continue
+			}
+
+		case strings.HasPrefix(rest, "~~"):
+			if inner, n, ok := delimited(rest, "~~", true); ok {
+				if r.opts.Color {
+					b.WriteString(r.style(styleStrike, r.inline(inner)))
+				} else {
+					b.WriteString("~~" + r.inline(inner) + "~~")
+				}
+				i += n
+				continue
+			}
+
+		case c == '*', c == '_':
+			if inner, n, ok := delimited(rest, rest[:1], i == 0 || !isWordByte(s[i-1])); ok {
+				b.WriteString(r.style(styleItalic, r.inline(inner)))
+				i += n
+				continue
+			}
+		}
+
+		b.WriteByte(c)
+		i++
+	}
+	return b.String()
+}
+
+func (r *markdownRenderer) link(text, url string) string {
+	if text == url || url == "" {
+		return r.style(styleLink, text)
+	}
+	return r.style(styleLink, text) + " " + r.style(styleDim, "("+url+")")
+}
+
+// style applies code to every word of text so wrapping never splits a styled
+// run across lines.
+func (r *markdownRenderer) style(code, text string) string {
+	if !r.opts.Color || text == "" {
+		return text
+	}
+	words := strings.Split(text, " ")
+	for i, w := range words {
+		if w != "" {
+			words[i] = code + w + styleReset
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// parseLink parses "text](url)" and returns the text, the url and the number
+// of bytes consumed.
+func parseLink(s string) (string, string, int, bool) {
+	depth := 1
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if i+1 >= len(s) || s[i+1] != '(' {
+				return "", "", 0, false
+			}
+			end := strings.IndexByte(s[i+2:], ')')
+			if end < 0 {
+				return "", "", 0, false
+			}
+			url := strings.TrimSpace(s[i+2 : i+2+end])
+			if idx := strings.IndexByte(url, ' '); idx >= 0 {
+				url = url[:idx]
+			}
+			return s[:i], url, i + 3 + end, true
+		}
+	}
+	return "", "", 0, false
+}
+
+// delimited returns the text between an opening and closing delimiter at the
+// start of s.
+func delimited(s, delim string, leftBoundary bool) (string, int, bool) {
+	if !leftBoundary || len(s) <= len(delim) || s[len(delim)] == ' ' {
+		return "", 0, false
+	}
+	end := strings.Index(s[len(delim):], delim)
+	if end <= 0 {
+		return "", 0, false
+	}
+	closeAt := len(delim) + end
+	if s[closeAt-1] == ' ' {
+		return "", 0, false
+	}
+	after := closeAt + len(delim)
+	if delim[0] == '_' && after < len(s) && isWordByte(s[after]) {
+		return "", 0, false
+	}
+	return s[len(delim):closeAt], after, true
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return reFence.MatchString(line) || reHeading.MatchString(line) || isRule(line) ||
+		strings.HasPrefix(trimmed, ">") || reListItem.MatchString(line)
+}
+
+func isRule(line string) bool {
+	if !reRule.MatchString(line) {
+		return false
+	}
+	compact := strings.ReplaceAll(strings.TrimSpace(line), " ", "")
+	return len(compact) >= 3 && strings.Count(compact, compact[:1]) == len(compact)
+}
+
+func isTableRow(line string) bool {
+	return strings.Contains(strings.TrimSpace(line), "|")
+}
+
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, "\\|") {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == '|' {
+			cell.WriteByte('|')
+			i++
+			continue
+		}
+		if line[i] == '|' {
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(line[i])
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func tableAlignment(line string) []string {
+	var align []string
+	for _, cell := range splitTableRow(line) {
+		switch {
+		case strings.HasPrefix(cell, ":") && strings.HasSuffix(cell, ":"):
+			align = append(align, "center")
+		case strings.HasSuffix(cell, ":"):
+			align = append(align, "right")
+		default:
+			align = append(align, "left")
+		}
+	}
+	return align
+}
+
+func pad(s string, width int, align string) string {
+	gap := width - visibleWidth(s)
+	if gap <= 0 {
+		return s
+	}
+	switch align {
+	case "right":
+		return strings.Repeat(" ", gap) + s
+	case "center":
+		left := gap / 2
+		return strings.Repeat(" ", left) + s + strings.Repeat(" ", gap-left)
+	}
+	return s + strings.Repeat(" ", gap)
+}
+
+func ruleWidth(width int) int {
+	if width <= 0 {
+		return 40
+	}
+	return width
+}
+
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(reANSI.ReplaceAllString(s, ""))
+}
+
+// wrapText breaks text into lines of at most width visible columns. Words
+// longer than the width, like URLs, are kept whole.
+func wrapText(text string, width int) []string {
+	if width <= 0 || visibleWidth(text) <= width {
+		return []string{text}
+	}
+
+	var lines []string
+	var line string
+	lineWidth := 0
+	for _, word := range strings.Fields(text) {
+		w := visibleWidth(word)
+		if lineWidth > 0 && lineWidth+1+w > width {
+			lines = append(lines, line)
+			line, lineWidth = "", 0
+		}
+		if lineWidth > 0 {
+			line += " "
+			lineWidth++
+		}
+		line += word
+		lineWidth += w
+	}
+	return append(lines, line)
+}
@@ -0,0 +1,107 @@
+package issue
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git-issues/domain"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestRenderMarkdownGolden(t *testing.T) {
+	sources, err := filepath.Glob(filepath.Join("testdata", "markdown", "*.md"))
+	if err != nil {
+		t.Fatalf("failed to list sources: %v", err)
+	}
+	if len(sources) == 0 {
+		t.Fatal("no markdown sources found")
+	}
+
+	modes := []struct {
+		name string
+		opts MarkdownOptions
+	}{
+		{name: "plain", opts: MarkdownOptions{Width: 60}},
+		{name: "color", opts: MarkdownOptions{Width: 60, Color: true}},
+	}
+
+	for _, source := range sources {
+		for _, mode := range modes {
+			name := strings.TrimSuffix(filepath.Base(source), ".md") + "." + mode.name
+			t.Run(name, func(t *testing.T) {
+				src, err := os.ReadFile(source)
+				if err != nil {
+					t.Fatalf("failed to read source: %v", err)
+				}
+
+				got := RenderMarkdown(string(src), mode.opts) + "\n"
+
+				golden := filepath.Join("testdata", "markdown", name+".golden")
+				if *updateGolden {
+					if err = os.WriteFile(golden, []byte(got), 0o644); err != nil {
+						t.Fatalf("failed to update golden file: %v", err)
+					}
+				}
+
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatalf("failed to read golden file: %v", err)
+				}
+				if got != string(want) {
+					t.Errorf("output mismatch for %s:\ngot:\n%s\nwant:\n%s", golden, got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestRenderMarkdownWidth(t *testing.T) {
+	src := "a paragraph that is long enough to wrap at twenty columns"
+
+	for _, width := range []int{20, 30} {
+		for _, line := range strings.Split(RenderMarkdown(src, MarkdownOptions{Width: width, Color: true}), "\n") {
+			if visibleWidth(line) > width {
+				t.Errorf("line %q exceeds width %d", line, width)
+			}
+		}
+	}
+
+	if got := RenderMarkdown(src, MarkdownOptions{}); got != src {
+		t.Errorf("zero width should not wrap, got %q", got)
+	}
+}
+
+func TestRenderMarkdownNoColor(t *testing.T) {
+	src := "# Title\n\n**bold** `code` [link](https://example.com)\n\n```go\nfunc main() {}\n```"
+
+	if got := RenderMarkdown(src, MarkdownOptions{Width: 80}); strings.Contains(got, "\x1b[") {
+		t.Errorf("plain output contains escape sequences: %q", got)
+	}
+}
+
+func TestPrintIssueMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	issue := &domain.Issue{Number: 3, Title: "title", State: "open", Body: "**bold** body"}
+	comments := []domain.Comment{{User: domain.User{Login: "octocat"}, CreatedAt: "2024-01-02T03:04:05Z", Body: "- item"}}
+
+	if err := PrintIssueMarkdown(&buf, issue, MarkdownOptions{Width: 80}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := PrintCommentsMarkdown(&buf, comments, MarkdownOptions{Width: 80}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "\nIssue #3\nTitle: title\nState: open\nBody:\nbold body\n" +
+		"\n@octocat commented 2024-01-02T03:04:05Z:\n• item\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output mismatch:\ngot:\n%q\nwant:\n%q", got, want)
+	}
+	if issue.Body != "**bold** body" {
+		t.Errorf("PrintIssueMarkdown modified the issue body: %q", issue.Body)
+	}
+}
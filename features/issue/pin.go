@@ -0,0 +1,90 @@
+package issue
+
+import (
+	"errors"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+)
+
+const (
+	pinMutation   = `mutation($id: ID!) { pinIssue(input: {issueId: $id}) { issue { isPinned } } }`
+	unpinMutation = `mutation($id: ID!) { unpinIssue(input: {issueId: $id}) { issue { isPinned } } }`
+	pinQuery      = `query($owner: String!, $repo: String!, $number: Int!) { repository(owner: $owner, name: $repo) { issue(number: $number) { id isPinned } } }`
+)
+
+// PinIssue pins issues to the top of the repository issue list. Pinning is
+// only available through the GraphQL api.
+type PinIssue interface {
+	Pin(number int) error
+	Unpin(number int) error
+	IsPinned(number int) (bool, error)
+}
+
+type PinFeature struct {
+	config  *domain.Config
+	graphql client.GraphQLClient
+}
+
+type pinState struct {
+	ID       string `json:"id"`
+	IsPinned bool   `json:"isPinned"`
+}
+
+func NewPin(config *domain.Config, graphql client.GraphQLClient) *PinFeature {
+	return &PinFeature{
+		config:  config,
+		graphql: graphql,
+	}
+}
+
+func (f *PinFeature) Pin(number int) error {
+	if err := f.mutate(number, pinMutation); err != nil {
+		return errors.Join(errPin, err)
+	}
+	return nil
+}
+
+func (f *PinFeature) Unpin(number int) error {
+	if err := f.mutate(number, unpinMutation); err != nil {
+		return errors.Join(errUnpin, err)
+	}
+	return nil
+}
+
+func (f *PinFeature) IsPinned(number int) (bool, error) {
+	state, err := f.state(number)
+	if err != nil {
+		return false, err
+	}
+	return state.IsPinned, nil
+}
+
+func (f *PinFeature) state(number int) (*pinState, error) {
+	if number == 0 {
+		return nil, errNumberIsRequered
+	}
+
+	var result struct {
+		Repository struct {
+			Issue *pinState `json:"issue"`
+		} `json:"repository"`
+	}
+	variables := map[string]interface{}{"owner": f.config.Owner, "repo": f.config.Repo, "number": number}
+	if err := f.graphql.Query(pinQuery, variables, &result); err != nil {
+		return nil, err
+	}
+	if result.Repository.Issue == nil {
+		return nil, errNotFound
+	}
+	return result.Repository.Issue, nil
+}
+
+// mutate looks up the node id of the issue and runs mutation on it.
+func (f *PinFeature) mutate(number int, mutation string) error {
+	state, err := f.state(number)
+	if err != nil {
+		return err
+	}
+	return f.graphql.Query(mutation, map[string]interface{}{"id": state.ID}, nil)
+}
@@ -0,0 +1,130 @@
+package issue
+
+import (
+	"errors"
+	"testing"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+func TestPinFeature(t *testing.T) {
+	cfg := &domain.Config{Owner: "owner", Repo: "repo"}
+
+	tests := []struct {
+		name         string
+		number       int
+		unpin        bool
+		state        string
+		mutationErr  error
+		wantMutation string
+		wantErr      error
+	}{
+		{
+			name:         "pin",
+			number:       1,
+			state:        `{"repository":{"issue":{"id":"I_1","isPinned":false}}}`,
+			wantMutation: pinMutation,
+		},
+		{
+			name:         "unpin",
+			number:       1,
+			unpin:        true,
+			state:        `{"repository":{"issue":{"id":"I_1","isPinned":true}}}`,
+			wantMutation: unpinMutation,
+		},
+		{
+			name:    "number required",
+			wantErr: errNumberIsRequered,
+		},
+		{
+			name:    "issue not found",
+			number:  1,
+			state:   `{"repository":{"issue":null}}`,
+			wantErr: errNotFound,
+		},
+		{
+			name:         "mutation error",
+			number:       1,
+			state:        `{"repository":{"issue":{"id":"I_1","isPinned":false}}}`,
+			mutationErr:  domain.ErrGraphQL,
+			wantMutation: pinMutation,
+			wantErr:      errPin,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotMutation string
+			var gotID interface{}
+			graphqlStub := &stubs.GraphQLStub{
+				QueryFunc: func(query string, variables map[string]interface{}, result interface{}) error {
+					if query == pinQuery {
+						if variables["owner"] != "owner" || variables["repo"] != "repo" || variables["number"] != tt.number {
+							t.Errorf("unexpected variables: %v", variables)
+						}
+						return stubs.GraphQLResponse(tt.state)(query, variables, result)
+					}
+					gotMutation, gotID = query, variables["id"]
+					return tt.mutationErr
+				},
+			}
+			f := NewPin(cfg, graphqlStub)
+
+			var err error
+			if tt.unpin {
+				err = f.Unpin(tt.number)
+			} else {
+				err = f.Pin(tt.number)
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error: got %v want %v", err, tt.wantErr)
+			}
+			if gotMutation != tt.wantMutation {
+				t.Errorf("unexpected mutation: got %q want %q", gotMutation, tt.wantMutation)
+			}
+			if tt.wantMutation != "" && gotID != "I_1" {
+				t.Errorf("unexpected node id: %v", gotID)
+			}
+		})
+	}
+}
+
+func TestIsPinned(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    bool
+		wantErr error
+	}{
+		{
+			name: "pinned",
+			data: `{"repository":{"issue":{"id":"I_1","isPinned":true}}}`,
+			want: true,
+		},
+		{
+			name: "not pinned",
+			data: `{"repository":{"issue":{"id":"I_1","isPinned":false}}}`,
+		},
+		{
+			name:    "missing issue",
+			data:    `{"repository":{"issue":null}}`,
+			wantErr: errNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graphqlStub := &stubs.GraphQLStub{QueryFunc: stubs.GraphQLResponse(tt.data)}
+			got, err := NewPin(&domain.Config{}, graphqlStub).IsPinned(1)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error: got %v want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
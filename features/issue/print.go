@@ -3,15 +3,26 @@ package issue
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"git-issues/domain"
 )
 
+// shortHashLength is how much of a commit hash is shown, like git does.
+const shortHashLength = 7
+
 const (
-	strIssueFormat       = "#%v - %s (%s)\n"
+	strIssueFormat       = "%s#%v - %s (%s)\n"
 	strDetailIssueFormat = "\nIssue #%d\nTitle: %s\nState: %s\nBody:\n%s\n"
+	strCommentFormat     = "\n@%s commented %s:\n%s\n"
+	strReactionsFormat   = "Reactions: %s\n"
+	strUpvotesFormat     = "%s#%v - %s (%s) 👍 %d\n"
+	strMentionFormat     = "%s %s (%s %s)\n"
 )
 
+// reactionEmoji matches the order of ReactionContents.
+var reactionEmoji = []string{"👍", "👎", "😄", "🎉", "😕", "❤️", "🚀", "👀"}
+
 func PrintIssues(w io.Writer, issues []domain.Issue) error {
 	_, err := fmt.Fprintln(w, "\nIssues:")
 	if err != nil {
@@ -19,7 +30,11 @@ func PrintIssues(w io.Writer, issues []domain.Issue) error {
 	}
 
 	for _, i := range issues {
-		_, err = fmt.Fprintf(w, strIssueFormat, i.Number, i.Title, i.State)
+		if up := upvotes(&i); up > 0 {
+			_, err = fmt.Fprintf(w, strUpvotesFormat, i.Repo, i.Number, i.Title, i.State, up)
+		} else {
+			_, err = fmt.Fprintf(w, strIssueFormat, i.Repo, i.Number, i.Title, i.State)
+		}
 		if err != nil {
 			return err
 		}
@@ -28,9 +43,176 @@ func PrintIssues(w io.Writer, issues []domain.Issue) error {
 }
 
 func PrintIssue(w io.Writer, issue *domain.Issue) error {
-	_, err := fmt.Fprintf(w, strDetailIssueFormat, issue.Number, issue.Title, issue.State, issue.Body)
+	_, err := fmt.Fprintf(w, strDetailIssueFormat, issue.Number, issue.Title, issueStatus(issue), issue.Body)
+	if err != nil {
+		return err
+	}
+	if summary := ReactionSummary(issue.Reactions); summary != "" {
+		_, err = fmt.Fprintf(w, strReactionsFormat, summary)
+	}
+	return err
+}
+
+func PrintComments(w io.Writer, comments []domain.Comment) error {
+	for _, c := range comments {
+		_, err := fmt.Fprintf(w, strCommentFormat, c.User.Login, c.CreatedAt, c.Body)
+		if err != nil {
+			return err
+		}
+		if summary := ReactionSummary(c.Reactions); summary != "" {
+			if _, err = fmt.Fprintf(w, strReactionsFormat, summary); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ReactionSummary lists the reactions with a count, like "👍 3 ❤️ 1", or is
+// empty when there are none.
+func ReactionSummary(r *domain.Reactions) string {
+	var parts []string
+	for i, count := range reactionCounts(r) {
+		if count > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", reactionEmoji[i], count))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// PrintIssueMarkdown prints the issue like PrintIssue with the body rendered
+// for the terminal.
+func PrintIssueMarkdown(w io.Writer, issue *domain.Issue, opts MarkdownOptions) error {
+	rendered := *issue
+	rendered.Body = RenderMarkdown(issue.Body, opts)
+	return PrintIssue(w, &rendered)
+}
+
+// PrintCommentsMarkdown prints the comments like PrintComments with each body
+// rendered for the terminal.
+func PrintCommentsMarkdown(w io.Writer, comments []domain.Comment, opts MarkdownOptions) error {
+	rendered := make([]domain.Comment, len(comments))
+	for i, c := range comments {
+		rendered[i] = c
+		rendered[i].Body = RenderMarkdown(c.Body, opts)
+	}
+	return PrintComments(w, rendered)
+}
+
+// issueStatus is the state followed by the lock and pin flags, if any.
+func issueStatus(issue *domain.Issue) string {
+	status := issue.State
+	if issue.Locked {
+		status += ", locked"
+		if issue.ActiveLockReason != "" {
+			status += " (" + issue.ActiveLockReason + ")"
+		}
+	}
+	if issue.Pinned {
+		status += ", pinned"
+	}
+	return status
+}
+
+// PrintTree prints the issue hierarchy with a check mark for closed issues
+// and checked tasks. References into the repository of the root are short.
+func PrintTree(w io.Writer, root *TreeNode) error {
+	_, err := fmt.Fprintf(w, "%s%s\n", treeLabel(root, root.Ref), treeCount(root))
+	if err != nil {
+		return err
+	}
+	return printTreeChildren(w, root, root.Ref, "")
+}
+
+func printTreeChildren(w io.Writer, node *TreeNode, root IssueRef, prefix string) error {
+	for i, child := range node.Children {
+		branch, indent := "├── ", "│   "
+		if i == len(node.Children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		mark := "[ ]"
+		if child.Done() {
+			mark = "[x]"
+		}
+		_, err := fmt.Fprintf(w, "%s%s%s %s%s\n", prefix, branch, mark, treeLabel(child, root), treeCount(child))
+		if err != nil {
+			return err
+		}
+		if err = printTreeChildren(w, child, root, prefix+indent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func treeLabel(node *TreeNode, root IssueRef) string {
+	if node.Ref.Number == 0 {
+		return node.Title
+	}
+	ref := node.Ref
+	if ref.Owner == root.Owner && ref.Repo == root.Repo {
+		ref.Owner, ref.Repo = "", ""
+	}
+	label := ref.String()
+	if node.Title != "" {
+		label += " " + node.Title
+	}
+	if node.State != "" {
+		label += " (" + node.State + ")"
+	}
+	return label
+}
+
+func treeCount(node *TreeNode) string {
+	if len(node.Children) == 0 {
+		return ""
+	}
+	done := 0
+	for _, child := range node.Children {
+		if child.Done() {
+			done++
+		}
+	}
+	return fmt.Sprintf(" [%d/%d]", done, len(node.Children))
+}
+
+// PrintProgress prints the completion of the sub-issues and tasks of an
+// issue followed by the items still open.
+func PrintProgress(w io.Writer, p *Progress) error {
+	_, err := fmt.Fprintf(w, "%s\nSub-issues: %d/%d closed\nTasks: %d/%d done\nProgress: %d%%\n",
+		treeLabel(p.Issue, p.Issue.Ref), p.SubIssuesDone, p.SubIssues, p.TasksDone, p.Tasks, p.Percent())
 	if err != nil {
 		return err
 	}
+	if len(p.Open) == 0 {
+		return nil
+	}
+	if _, err = fmt.Fprintln(w, "\nOpen:"); err != nil {
+		return err
+	}
+	for _, item := range p.Open {
+		if _, err = fmt.Fprintf(w, "- %s\n", treeLabel(item, p.Issue.Ref)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintMentions prints a commit per line with the reference it makes, like
+// 1a2b3c4 Fix the login (fixes #12).
+func PrintMentions(w io.Writer, mentions []Mention) error {
+	for _, m := range mentions {
+		hash := m.Commit.Hash
+		if len(hash) > shortHashLength {
+			hash = hash[:shortHashLength]
+		}
+		how := "mentions"
+		if m.Reference.Closes() {
+			how = m.Reference.Keyword
+		}
+		if _, err := fmt.Fprintf(w, strMentionFormat, hash, m.Commit.Subject, how, m.Reference.Ref); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"git-issues/domain"
+	"git-issues/service/git"
 )
 
 type errWriter struct {
@@ -104,6 +105,32 @@ func TestPrintIssue(t *testing.T) {
 				10, "title", "open", "body content"),
 			wantErr: nil,
 		},
+		{
+			name: "locked and pinned",
+			issue: &domain.Issue{
+				Number:           11,
+				Title:            "title",
+				State:            "open",
+				Body:             "body",
+				Locked:           true,
+				ActiveLockReason: "too heated",
+				Pinned:           true,
+			},
+			want:    "\nIssue #11\nTitle: title\nState: open, locked (too heated), pinned\nBody:\nbody\n",
+			wantErr: nil,
+		},
+		{
+			name: "with reactions",
+			issue: &domain.Issue{
+				Number:    12,
+				Title:     "title",
+				State:     "open",
+				Body:      "body",
+				Reactions: &domain.Reactions{TotalCount: 4, PlusOne: 3, Rocket: 1},
+			},
+			want:    "\nIssue #12\nTitle: title\nState: open\nBody:\nbody\nReactions: 👍 3 🚀 1\n",
+			wantErr: nil,
+		},
 		{
 			name:    "writer error",
 			issue:   &domain.Issue{Number: 1, Title: "t", State: "s", Body: "b"},
@@ -140,3 +167,20 @@ func TestPrintIssue(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintMentions(t *testing.T) {
+	mentions := []Mention{
+		{Commit: git.Commit{Hash: "1a2b3c4d5e6f", Subject: "Fix the login"}, Reference: Reference{Ref: IssueRef{Number: 12}, Keyword: "fixes"}},
+		{Commit: git.Commit{Hash: "abc", Subject: "Start"}, Reference: Reference{Ref: IssueRef{Owner: "o", Repo: "r", Number: 3}}},
+	}
+
+	var buf bytes.Buffer
+	if err := PrintMentions(&buf, mentions); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "1a2b3c4 Fix the login (fixes #12)\nabc Start (mentions o/r#3)\n"
+	if buf.String() != want {
+		t.Errorf("got %q want %q", buf.String(), want)
+	}
+}
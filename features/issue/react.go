@@ -0,0 +1,88 @@
+package issue
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+)
+
+// ReactionContents are the reactions the api accepts, in the order GitHub
+// shows them.
+var ReactionContents = []string{"+1", "-1", "laugh", "hooray", "confused", "heart", "rocket", "eyes"}
+
+var reCommentURL = regexp.MustCompile(`#issuecomment-(\d+)$`)
+
+// ReactIssue adds reactions to issues and their comments.
+type ReactIssue interface {
+	React(number int, content string) error
+	ReactComment(comment string, content string) error
+}
+
+type ReactFeature struct {
+	config *domain.Config
+	client client.GitHubClient
+}
+
+type reactionPayload struct {
+	Content string `json:"content"`
+}
+
+func NewReact(config *domain.Config, client client.GitHubClient) *ReactFeature {
+	return &ReactFeature{
+		config: config,
+		client: client,
+	}
+}
+
+func (f *ReactFeature) React(number int, content string) error {
+	if number == 0 {
+		return errNumberIsRequered
+	}
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/reactions", f.config.APIBaseURL, f.config.Owner, f.config.Repo, number)
+	return f.react(url, content)
+}
+
+// ReactComment reacts to a comment given by id or by its url, as copied
+// from the browser.
+func (f *ReactFeature) ReactComment(comment string, content string) error {
+	if match := reCommentURL.FindStringSubmatch(comment); match != nil {
+		comment = match[1]
+	}
+	id, err := strconv.ParseInt(comment, 10, 64)
+	if err != nil || id <= 0 {
+		return errCommentID
+	}
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/comments/%d/reactions", f.config.APIBaseURL, f.config.Owner, f.config.Repo, id)
+	return f.react(url, content)
+}
+
+func (f *ReactFeature) react(url string, content string) error {
+	if !validReaction(content) {
+		return errReaction
+	}
+	if _, err := f.client.MakeJSONRequest("POST", url, reactionPayload{Content: content}); err != nil {
+		return errors.Join(errReact, err)
+	}
+	return nil
+}
+
+func validReaction(content string) bool {
+	for _, c := range ReactionContents {
+		if c == content {
+			return true
+		}
+	}
+	return false
+}
+
+// reactionCounts lists the reaction counts in ReactionContents order.
+func reactionCounts(r *domain.Reactions) []int {
+	if r == nil {
+		return make([]int, len(ReactionContents))
+	}
+	return []int{r.PlusOne, r.MinusOne, r.Laugh, r.Hooray, r.Confused, r.Heart, r.Rocket, r.Eyes}
+}
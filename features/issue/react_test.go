@@ -0,0 +1,117 @@
+package issue
+
+import (
+	"errors"
+	"testing"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+func TestReactFeature(t *testing.T) {
+	cfg := &domain.Config{APIBaseURL: "https://api.example.com", Owner: "owner", Repo: "repo"}
+	apiErr := errors.New("api")
+
+	tests := []struct {
+		name      string
+		number    int
+		comment   string
+		content   string
+		apiErr    error
+		wantURL   string
+		wantErr   error
+		wantCalls int
+	}{
+		{
+			name:      "react to issue",
+			number:    3,
+			content:   "+1",
+			wantURL:   "https://api.example.com/repos/owner/repo/issues/3/reactions",
+			wantCalls: 1,
+		},
+		{
+			name:      "react to comment by id",
+			comment:   "99",
+			content:   "heart",
+			wantURL:   "https://api.example.com/repos/owner/repo/issues/comments/99/reactions",
+			wantCalls: 1,
+		},
+		{
+			name:      "react to comment by url",
+			comment:   "https://github.com/owner/repo/issues/3#issuecomment-123",
+			content:   "eyes",
+			wantURL:   "https://api.example.com/repos/owner/repo/issues/comments/123/reactions",
+			wantCalls: 1,
+		},
+		{name: "number required", content: "+1", wantErr: errNumberIsRequered},
+		{name: "invalid comment", comment: "abc", content: "+1", wantErr: errCommentID},
+		{name: "invalid reaction", number: 3, content: "thumbsup", wantErr: errReaction},
+		{name: "api error", number: 3, content: "-1", apiErr: apiErr, wantErr: errReact, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			f := NewReact(cfg, &stubs.ClientStub{
+				MakeJSONRequestFunc: func(method, url string, payload interface{}) ([]byte, error) {
+					calls++
+					if method != "POST" || (tt.wantURL != "" && url != tt.wantURL) {
+						t.Errorf("unexpected request %s %s", method, url)
+					}
+					if p, ok := payload.(reactionPayload); !ok || p.Content != tt.content {
+						t.Errorf("unexpected payload %#v", payload)
+					}
+					return []byte(`{}`), tt.apiErr
+				},
+			})
+
+			var err error
+			if tt.comment != "" {
+				err = f.ReactComment(tt.comment, tt.content)
+			} else {
+				err = f.React(tt.number, tt.content)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, calls)
+			}
+		})
+	}
+}
+
+func TestSortIssues(t *testing.T) {
+	issues := []domain.Issue{
+		{Number: 1},
+		{Number: 2, Reactions: &domain.Reactions{TotalCount: 2, PlusOne: 1, Heart: 1}},
+		{Number: 3, Reactions: &domain.Reactions{TotalCount: 5, PlusOne: 5}},
+		{Number: 4, Reactions: &domain.Reactions{TotalCount: 1, PlusOne: 1}},
+	}
+
+	if err := SortIssues(issues, "reactions"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, want := range []int{3, 2, 4, 1} {
+		if issues[i].Number != want {
+			t.Fatalf("order = %v, want 3 2 4 1", issues)
+		}
+	}
+
+	if err := SortIssues(issues, ""); err != nil {
+		t.Errorf("empty order should keep the api order, got %v", err)
+	}
+
+	issues[0].UpdatedAt, issues[0].CreatedAt = "2026-10-02T00:00:00Z", "2026-01-01T00:00:00Z"
+	issues[1].UpdatedAt, issues[1].CreatedAt = "2026-10-03T00:00:00Z", "2026-03-01T00:00:00Z"
+	issues[2].UpdatedAt, issues[2].CreatedAt = "2026-10-01T00:00:00Z", "2026-02-01T00:00:00Z"
+	if err := SortIssues(issues, "updated"); err != nil || issues[0].Number != 2 || issues[1].Number != 3 || issues[2].Number != 4 {
+		t.Errorf("updated order = %v, %v", issues, err)
+	}
+	if err := SortIssues(issues, "created"); err != nil || issues[0].Number != 2 || issues[1].Number != 4 || issues[2].Number != 3 {
+		t.Errorf("created order = %v, %v", issues, err)
+	}
+	if err := SortIssues(issues, "votes"); !errors.Is(err, errSort) {
+		t.Errorf("expected errSort, got %v", err)
+	}
+}
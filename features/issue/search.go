@@ -0,0 +1,70 @@
+package issue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+)
+
+const (
+	searchPageSize = 100
+	// searchLimit is the most results the search api returns for a query.
+	searchLimit = 1000
+)
+
+// SearchIssue finds the issues of the repository matching a query in the
+// search syntax of GitHub, like "is:open label:bug assignee:@me".
+type SearchIssue interface {
+	Search(query string) ([]domain.Issue, error)
+}
+
+type SearchFeature struct {
+	config *domain.Config
+	client client.GitHubClient
+}
+
+func NewSearch(config *domain.Config, client client.GitHubClient) *SearchFeature {
+	return &SearchFeature{
+		config: config,
+		client: client,
+	}
+}
+
+// Search returns the matching issues, pull requests left out, in the order
+// of the api: best match first.
+func (f *SearchFeature) Search(query string) ([]domain.Issue, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, errSearchEmpty
+	}
+	q := fmt.Sprintf("repo:%s/%s is:issue %s", f.config.Owner, f.config.Repo, strings.TrimSpace(query))
+
+	var issues []domain.Issue
+	for page := 1; (page-1)*searchPageSize < searchLimit; page++ {
+		searchURL := fmt.Sprintf("%s/search/issues?q=%s&per_page=%d&page=%d", f.config.APIBaseURL, url.QueryEscape(q), searchPageSize, page)
+		response, err := f.client.MakeRequest("GET", searchURL, nil)
+		if err != nil {
+			return nil, errors.Join(errSearch, err)
+		}
+		var found struct {
+			TotalCount int            `json:"total_count"`
+			Items      []domain.Issue `json:"items"`
+		}
+		if err = json.Unmarshal(response, &found); err != nil {
+			return nil, errProcessing
+		}
+		for _, item := range found.Items {
+			if item.PullRequest == nil {
+				issues = append(issues, item)
+			}
+		}
+		if len(found.Items) < searchPageSize || page*searchPageSize >= found.TotalCount {
+			break
+		}
+	}
+	return issues, nil
+}
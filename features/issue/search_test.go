@@ -0,0 +1,63 @@
+package issue
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+func TestSearchFeature(t *testing.T) {
+	cfg := &domain.Config{APIBaseURL: "https://api.example.com", Owner: "owner", Repo: "repo"}
+
+	// a first full page and a second one with a pull request
+	firstPage := make([]string, searchPageSize)
+	for i := range firstPage {
+		firstPage[i] = fmt.Sprintf(`{"number":%d}`, i+1)
+	}
+	pages := map[string]string{
+		"1": `{"total_count":102,"items":[` + strings.Join(firstPage, ",") + `]}`,
+		"2": `{"total_count":102,"items":[{"number":101},{"number":102,"pull_request":{}}]}`,
+	}
+
+	var queries []string
+	stub := &stubs.ClientStub{
+		MakeRequestFunc: func(method, u string, data *domain.Issue) ([]byte, error) {
+			parsed, err := url.Parse(u)
+			if err != nil || parsed.Path != "/search/issues" {
+				return nil, domain.ErrApi
+			}
+			queries = append(queries, parsed.Query().Get("q"))
+			return []byte(pages[parsed.Query().Get("page")]), nil
+		},
+	}
+	f := NewSearch(cfg, stub)
+
+	got, err := f.Search(" is:open label:bug ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 101 || got[100].Number != 101 {
+		t.Errorf("got %d issues, want 101 without the pull request", len(got))
+	}
+	want := []string{"repo:owner/repo is:issue is:open label:bug", "repo:owner/repo is:issue is:open label:bug"}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("got queries %q want %q", queries, want)
+	}
+
+	if _, err = f.Search(" "); !errors.Is(err, errSearchEmpty) {
+		t.Errorf("got %v want errSearchEmpty", err)
+	}
+	stub.MakeRequestFunc = func(method, u string, data *domain.Issue) ([]byte, error) {
+		return nil, domain.ErrApi
+	}
+	if _, err = f.Search("is:open"); !errors.Is(err, errSearch) || !errors.Is(err, domain.ErrApi) {
+		t.Errorf("got %v want errSearch", err)
+	}
+}
@@ -0,0 +1,146 @@
+package issue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+	"git-issues/service/git"
+)
+
+// DefaultInProgressLabel is the label start adds when the config sets none.
+const DefaultInProgressLabel = "in progress"
+
+// StartIssue ties issues to git branches.
+type StartIssue interface {
+	Start(number int, inProgress bool) (*Started, error)
+	Current() (*domain.Issue, string, error)
+}
+
+// Started tells what start did: the branch and whether it was created or
+// already there.
+type Started struct {
+	Issue   *domain.Issue
+	Branch  string
+	Created bool
+	Login   string
+	Label   string
+}
+
+type StartFeature struct {
+	config   *domain.Config
+	client   client.GitHubClient
+	git      git.Git
+	update   UpdateIssue
+	namer    BranchNamer
+	namerErr error
+}
+
+func NewStart(config *domain.Config, client client.GitHubClient, git git.Git) *StartFeature {
+	f := &StartFeature{
+		config: config,
+		client: client,
+		git:    git,
+		update: NewUpdate(config, nil, client),
+	}
+	// an invalid template is reported by the commands that use it
+	f.namer, f.namerErr = NewBranchNamer(config.BranchTemplate)
+	return f
+}
+
+// Start checks out the branch of the issue, creating it from HEAD the first
+// time, and assigns the issue to the user of the token. With inProgress it
+// also adds the in progress label.
+func (f *StartFeature) Start(number int, inProgress bool) (*Started, error) {
+	if f.namerErr != nil {
+		return nil, f.namerErr
+	}
+	issue, err := f.issue(number)
+	if err != nil {
+		return nil, err
+	}
+
+	started := &Started{Issue: issue}
+	if started.Branch, err = f.namer.BranchName(issue); err != nil {
+		return nil, err
+	}
+	exists, err := f.git.BranchExists(started.Branch)
+	if err != nil {
+		return nil, errors.Join(errStart, err)
+	}
+	if exists {
+		err = f.git.Checkout(started.Branch)
+	} else {
+		err = f.git.CreateBranch(started.Branch)
+	}
+	if err != nil {
+		return nil, errors.Join(errStart, err)
+	}
+	started.Created = !exists
+
+	if started.Login, err = me(f.config, f.client); err != nil {
+		return started, errors.Join(errAssign, err)
+	}
+	if err = f.update.Assign(number, started.Login); err != nil {
+		return started, err
+	}
+
+	if inProgress {
+		started.Label = f.config.InProgressLabel
+		if started.Label == "" {
+			started.Label = DefaultInProgressLabel
+		}
+		if err = f.update.AddLabels(number, started.Label); err != nil {
+			return started, err
+		}
+	}
+	return started, nil
+}
+
+// Current returns the issue the checked out branch was named after.
+func (f *StartFeature) Current() (*domain.Issue, string, error) {
+	if f.namerErr != nil {
+		return nil, "", f.namerErr
+	}
+	branch, err := f.git.CurrentBranch()
+	if err != nil {
+		return nil, "", err
+	}
+	number, ok := f.namer.IssueNumber(branch)
+	if !ok {
+		return nil, branch, fmt.Errorf("%w: %s", errNoIssueBranch, branch)
+	}
+	issue, err := f.issue(number)
+	return issue, branch, err
+}
+
+func (f *StartFeature) issue(number int) (*domain.Issue, error) {
+	if number == 0 {
+		return nil, errNumberIsRequered
+	}
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d", f.config.APIBaseURL, f.config.Owner, f.config.Repo, number)
+	response, err := f.client.MakeRequest("GET", url, nil)
+	if err != nil {
+		return nil, errors.Join(errNotFound, err)
+	}
+	issue := &domain.Issue{}
+	if err = json.Unmarshal(response, issue); err != nil {
+		return nil, errProcessing
+	}
+	return issue, nil
+}
+
+// me is the login of the token, what @me stands for in the api.
+func me(config *domain.Config, client client.GitHubClient) (string, error) {
+	response, err := client.MakeRequest("GET", config.APIBaseURL+"/user", nil)
+	if err != nil {
+		return "", err
+	}
+	var user domain.User
+	if err = json.Unmarshal(response, &user); err != nil || user.Login == "" {
+		return "", errProcessing
+	}
+	return user.Login, nil
+}
@@ -0,0 +1,198 @@
+package issue
+
+import (
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"git-issues/domain"
+	"git-issues/service/git"
+	"git-issues/testdata/stubs"
+)
+
+// startAPI serves issue 12, the login of the token and records the patches.
+func startAPI(patched *[]domain.Issue) *stubs.ClientStub {
+	return &stubs.ClientStub{
+		MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+			switch {
+			case strings.HasSuffix(url, "/user"):
+				return []byte(`{"login":"octocat"}`), nil
+			case strings.HasSuffix(url, "/issues/12") && method == "GET":
+				return []byte(`{"number":12,"title":"Fix the login page","state":"open"}`), nil
+			case strings.HasSuffix(url, "/issues/12") && method == "PATCH":
+				*patched = append(*patched, *data)
+				return json.Marshal(data)
+			}
+			return nil, errors.New("404 Not Found")
+		},
+	}
+}
+
+func TestStart(t *testing.T) {
+	cfg := &domain.Config{APIBaseURL: "https://api.example.com", Owner: "owner", Repo: "repo"}
+
+	tests := []struct {
+		name       string
+		config     *domain.Config
+		number     int
+		inProgress bool
+		exists     bool
+		want       *Started
+		wantCalls  []string
+		wantErr    error
+	}{
+		{
+			name:      "new branch",
+			config:    cfg,
+			number:    12,
+			want:      &Started{Branch: "12-fix-the-login-page", Created: true, Login: "octocat"},
+			wantCalls: []string{"exists 12-fix-the-login-page", "create 12-fix-the-login-page"},
+		},
+		{
+			name:       "existing branch in progress",
+			config:     &domain.Config{APIBaseURL: "https://api.example.com", Owner: "owner", Repo: "repo", BranchTemplate: "issue/{{.Number}}", InProgressLabel: "doing"},
+			number:     12,
+			inProgress: true,
+			exists:     true,
+			want:       &Started{Branch: "issue/12", Login: "octocat", Label: "doing"},
+			wantCalls:  []string{"exists issue/12", "checkout issue/12"},
+		},
+		{
+			name:    "missing issue",
+			config:  cfg,
+			number:  13,
+			wantErr: errNotFound,
+		},
+		{
+			name:    "invalid template",
+			config:  &domain.Config{BranchTemplate: "{{.Title}}"},
+			number:  12,
+			wantErr: errBranchNumber,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patched []domain.Issue
+			var calls []string
+			gitStub := &stubs.GitStub{
+				BranchExistsFunc: func(name string) (bool, error) {
+					calls = append(calls, "exists "+name)
+					return tt.exists, nil
+				},
+				CreateBranchFunc: func(name string) error {
+					calls = append(calls, "create "+name)
+					return nil
+				},
+				CheckoutFunc: func(name string) error {
+					calls = append(calls, "checkout "+name)
+					return nil
+				},
+			}
+
+			got, err := NewStart(tt.config, startAPI(&patched), gitStub).Start(tt.number, tt.inProgress)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			got.Issue = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("git calls: got %v want %v", calls, tt.wantCalls)
+			}
+
+			// the stub does not keep the patches, so each one is checked alone
+			if !hasAssignee(&patched[0], "octocat") {
+				t.Errorf("issue not assigned: %+v", patched[0])
+			}
+			if tt.want.Label != "" && (len(patched) != 2 || !hasLabel(&patched[1], tt.want.Label)) {
+				t.Errorf("label %q missing: %+v", tt.want.Label, patched)
+			}
+		})
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	cfg := &domain.Config{APIBaseURL: "https://api.example.com", Owner: "owner", Repo: "repo"}
+
+	tests := []struct {
+		name       string
+		branch     string
+		branchErr  error
+		wantNumber int
+		wantErr    error
+	}{
+		{name: "issue branch", branch: "12-fix-the-login-page", wantNumber: 12},
+		{name: "other branch", branch: "main", wantErr: errNoIssueBranch},
+		{name: "detached head", branchErr: git.ErrGit, wantErr: git.ErrGit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gitStub := &stubs.GitStub{
+				CurrentBranchFunc: func() (string, error) { return tt.branch, tt.branchErr },
+			}
+
+			got, branch, err := NewStart(cfg, startAPI(nil), gitStub).Current()
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v want %v", err, tt.wantErr)
+			}
+			if branch != tt.branch {
+				t.Errorf("got branch %q want %q", branch, tt.branch)
+			}
+			if tt.wantErr == nil && got.Number != tt.wantNumber {
+				t.Errorf("got issue %d want %d", got.Number, tt.wantNumber)
+			}
+		})
+	}
+}
+
+func TestStartInGitRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch", "main"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	cfg := &domain.Config{APIBaseURL: "https://api.example.com", Owner: "owner", Repo: "repo"}
+	var patched []domain.Issue
+	start := NewStart(cfg, startAPI(&patched), git.New(dir))
+
+	first, err := start.Start(12, false)
+	if err != nil || !first.Created {
+		t.Fatalf("first start: got %+v, %v", first, err)
+	}
+	if err = git.New(dir).Checkout("main"); err != nil {
+		t.Fatalf("checkout main: %v", err)
+	}
+	second, err := start.Start(12, false)
+	if err != nil || second.Created {
+		t.Fatalf("second start: got %+v, %v", second, err)
+	}
+
+	issue, branch, err := start.Current()
+	if err != nil {
+		t.Fatalf("current: %v", err)
+	}
+	if branch != "12-fix-the-login-page" || issue.Number != 12 {
+		t.Errorf("current: got %q, #%d", branch, issue.Number)
+	}
+}
@@ -0,0 +1,299 @@
+package issue
+
+import (
+	"errors"
+	"strings"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+)
+
+const (
+	subIssueTreeQuery = `query($owner: String!, $repo: String!, $number: Int!) { repository(owner: $owner, name: $repo) { issue(number: $number) { id number title state body subIssues(first: 100) { nodes { number title state repository { nameWithOwner } } } } } }`
+	subIssueIDQuery   = `query($owner: String!, $repo: String!, $number: Int!) { repository(owner: $owner, name: $repo) { issue(number: $number) { id } } }`
+	addSubIssue       = `mutation($issue: ID!, $sub: ID!) { addSubIssue(input: {issueId: $issue, subIssueId: $sub}) { issue { number } } }`
+	removeSubIssue    = `mutation($issue: ID!, $sub: ID!) { removeSubIssue(input: {issueId: $issue, subIssueId: $sub}) { issue { number } } }`
+
+	// TreeDepth is how many levels below the root issue Tree fetches.
+	TreeDepth = 3
+)
+
+// SubIssue manages the sub-issues of an issue and reports the hierarchy formed
+// by sub-issues and the task list of the body.
+type SubIssue interface {
+	Add(parent int, child string) error
+	Remove(parent int, child string) error
+	Tree(number int, depth int) (*TreeNode, error)
+	Progress(number int) (*Progress, error)
+}
+
+type SubIssueFeature struct {
+	config  *domain.Config
+	graphql client.GraphQLClient
+}
+
+// TreeNode is an issue or a plain task of a task list with its children.
+// Number is zero for plain tasks.
+type TreeNode struct {
+	Ref   IssueRef
+	Title string
+	State string
+	// Task is set for nodes that only come from a task list. Checked holds
+	// the checkbox of task list items.
+	Task     bool
+	Checked  bool
+	Children []*TreeNode
+}
+
+// Progress counts the completed direct children of an issue.
+type Progress struct {
+	Issue         *TreeNode
+	SubIssues     int
+	SubIssuesDone int
+	Tasks         int
+	TasksDone     int
+	Open          []*TreeNode
+}
+
+type treeIssue struct {
+	ID        string `json:"id"`
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	State     string `json:"state"`
+	Body      string `json:"body"`
+	SubIssues struct {
+		Nodes []struct {
+			Number     int    `json:"number"`
+			Title      string `json:"title"`
+			State      string `json:"state"`
+			Repository struct {
+				NameWithOwner string `json:"nameWithOwner"`
+			} `json:"repository"`
+		} `json:"nodes"`
+	} `json:"subIssues"`
+}
+
+func NewSubIssue(config *domain.Config, graphql client.GraphQLClient) *SubIssueFeature {
+	return &SubIssueFeature{
+		config:  config,
+		graphql: graphql,
+	}
+}
+
+// Add makes child, a number or a reference like owner/repo#12, a sub-issue of
+// parent.
+func (f *SubIssueFeature) Add(parent int, child string) error {
+	if err := f.mutate(parent, child, addSubIssue); err != nil {
+		return errors.Join(errSubIssueAdd, err)
+	}
+	return nil
+}
+
+// Remove detaches child from parent.
+func (f *SubIssueFeature) Remove(parent int, child string) error {
+	if err := f.mutate(parent, child, removeSubIssue); err != nil {
+		return errors.Join(errSubIssueRemove, err)
+	}
+	return nil
+}
+
+// Tree fetches the issue with its sub-issues and task list items down to
+// depth levels. Issues already in the tree are not expanded again.
+func (f *SubIssueFeature) Tree(number int, depth int) (*TreeNode, error) {
+	if number == 0 {
+		return nil, errNumberIsRequered
+	}
+	root := IssueRef{Owner: f.config.Owner, Repo: f.config.Repo, Number: number}
+	return f.build(root, depth, map[string]bool{})
+}
+
+// Progress reports the completion of the sub-issues and tasks of an issue.
+// Referenced tasks count as done when checked or when the issue they point
+// to is closed, which is looked up for the unchecked ones.
+func (f *SubIssueFeature) Progress(number int) (*Progress, error) {
+	node, err := f.Tree(number, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &Progress{Issue: node}
+	for _, child := range node.Children {
+		if child.Task && child.Ref.Number != 0 && !child.Checked {
+			// references the token cannot read count as not done
+			if issue, err := f.fetch(child.Ref); err == nil {
+				child.Title, child.State = issue.Title, strings.ToLower(issue.State)
+			}
+		}
+		done := child.Done()
+		if child.Task {
+			progress.Tasks++
+			if done {
+				progress.TasksDone++
+			}
+		} else {
+			progress.SubIssues++
+			if done {
+				progress.SubIssuesDone++
+			}
+		}
+		if !done {
+			progress.Open = append(progress.Open, child)
+		}
+	}
+	return progress, nil
+}
+
+// Done reports whether the issue is closed or the task is checked.
+func (n *TreeNode) Done() bool {
+	return n.Checked || strings.EqualFold(n.State, "closed")
+}
+
+// Percent is the share of completed items, 100 when there are none.
+func (p *Progress) Percent() int {
+	total := p.SubIssues + p.Tasks
+	if total == 0 {
+		return 100
+	}
+	return (p.SubIssuesDone + p.TasksDone) * 100 / total
+}
+
+func (f *SubIssueFeature) build(ref IssueRef, depth int, seen map[string]bool) (*TreeNode, error) {
+	seen[ref.String()] = true
+
+	issue, err := f.fetch(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &TreeNode{Ref: ref, Title: issue.Title, State: strings.ToLower(issue.State)}
+	children := map[string]*TreeNode{}
+	var issues []*TreeNode
+	for _, sub := range issue.SubIssues.Nodes {
+		child := &TreeNode{
+			Ref:   subIssueRef(sub.Repository.NameWithOwner, sub.Number, ref),
+			Title: sub.Title,
+			State: strings.ToLower(sub.State),
+		}
+		children[child.Ref.String()] = child
+		issues = append(issues, child)
+		node.Children = append(node.Children, child)
+	}
+
+	// nested task items hang below the closest less indented item
+	var parents []*TreeNode
+	for _, task := range ParseTaskList(issue.Body) {
+		child := &TreeNode{Title: task.Text, Task: true, Checked: task.Checked}
+		attach := true
+		if task.Ref != nil {
+			child.Ref = task.Ref.Resolve(ref.Owner, ref.Repo)
+			// the rest of the item describes the reference until it is fetched
+			child.Title = strings.TrimSpace(strings.TrimPrefix(task.Text, strings.Fields(task.Text)[0]))
+			if existing, ok := children[child.Ref.String()]; ok {
+				// a sub-issue listed in the task list stays a sub-issue,
+				// and the items nested in it hang below it
+				existing.Checked = task.Checked
+				child, attach = existing, false
+			} else {
+				children[child.Ref.String()] = child
+				issues = append(issues, child)
+			}
+		}
+
+		if task.Depth < len(parents) {
+			parents = parents[:task.Depth]
+		}
+		switch {
+		case !attach:
+		case len(parents) == 0:
+			node.Children = append(node.Children, child)
+		default:
+			parent := parents[len(parents)-1]
+			parent.Children = append(parent.Children, child)
+		}
+		parents = append(parents, child)
+	}
+
+	if depth <= 1 {
+		return node, nil
+	}
+	for _, child := range issues {
+		if seen[child.Ref.String()] {
+			continue
+		}
+		expanded, err := f.build(child.Ref, depth-1, seen)
+		if err != nil {
+			// references the token cannot read stay as plain leaves
+			continue
+		}
+		child.Title, child.State = expanded.Title, expanded.State
+		child.Children = append(child.Children, expanded.Children...)
+	}
+	return node, nil
+}
+
+func (f *SubIssueFeature) fetch(ref IssueRef) (*treeIssue, error) {
+	var result struct {
+		Repository struct {
+			Issue *treeIssue `json:"issue"`
+		} `json:"repository"`
+	}
+	variables := map[string]interface{}{"owner": ref.Owner, "repo": ref.Repo, "number": ref.Number}
+	if err := f.graphql.Query(subIssueTreeQuery, variables, &result); err != nil {
+		return nil, err
+	}
+	if result.Repository.Issue == nil {
+		return nil, errNotFound
+	}
+	return result.Repository.Issue, nil
+}
+
+func (f *SubIssueFeature) nodeID(ref IssueRef) (string, error) {
+	var result struct {
+		Repository struct {
+			Issue *struct {
+				ID string `json:"id"`
+			} `json:"issue"`
+		} `json:"repository"`
+	}
+	variables := map[string]interface{}{"owner": ref.Owner, "repo": ref.Repo, "number": ref.Number}
+	if err := f.graphql.Query(subIssueIDQuery, variables, &result); err != nil {
+		return "", err
+	}
+	if result.Repository.Issue == nil {
+		return "", errNotFound
+	}
+	return result.Repository.Issue.ID, nil
+}
+
+func (f *SubIssueFeature) mutate(parent int, child string, mutation string) error {
+	if parent == 0 {
+		return errNumberIsRequered
+	}
+	childRef, ok := ParseIssueRef(child)
+	if !ok {
+		childRef, ok = ParseIssueRef("#" + child)
+	}
+	if !ok {
+		return errInvalidRef
+	}
+
+	parentID, err := f.nodeID(IssueRef{Owner: f.config.Owner, Repo: f.config.Repo, Number: parent})
+	if err != nil {
+		return err
+	}
+	childID, err := f.nodeID(childRef.Resolve(f.config.Owner, f.config.Repo))
+	if err != nil {
+		return err
+	}
+	return f.graphql.Query(mutation, map[string]interface{}{"issue": parentID, "sub": childID}, nil)
+}
+
+// subIssueRef builds the reference of a sub-issue from its owner/repo name,
+// falling back to the repository of the parent.
+func subIssueRef(nameWithOwner string, number int, parent IssueRef) IssueRef {
+	owner, repo, ok := strings.Cut(nameWithOwner, "/")
+	if !ok {
+		owner, repo = parent.Owner, parent.Repo
+	}
+	return IssueRef{Owner: owner, Repo: repo, Number: number}
+}
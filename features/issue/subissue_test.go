@@ -0,0 +1,214 @@
+package issue
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+// treeStub answers the tree query from a map of owner/repo#number to the
+// issue json.
+func treeStub(t *testing.T, issues map[string]string, calls *[]string) *stubs.GraphQLStub {
+	return &stubs.GraphQLStub{
+		QueryFunc: func(query string, variables map[string]interface{}, result interface{}) error {
+			key := fmt.Sprintf("%v/%v#%v", variables["owner"], variables["repo"], variables["number"])
+			*calls = append(*calls, key)
+			data, ok := issues[key]
+			if !ok {
+				return domain.ErrGraphQL
+			}
+			return stubs.GraphQLResponse(`{"repository":{"issue":`+data+`}}`)(query, variables, result)
+		},
+	}
+}
+
+var treeIssues = map[string]string{
+	"o/r#1": `{"number":1,"title":"Epic","state":"OPEN",
+		"body":"- [ ] #2\n- [x] write docs\n  - [ ] review docs\n- [ ] other/lib#5 upstream fix\n- [ ] #9 gone",
+		"subIssues":{"nodes":[
+			{"number":2,"title":"Child","state":"OPEN","repository":{"nameWithOwner":"o/r"}},
+			{"number":3,"title":"Done","state":"CLOSED","repository":{"nameWithOwner":"o/r"}}]}}`,
+	"o/r#2":       `{"number":2,"title":"Child","state":"OPEN","body":"- [x] #1\n- [ ] step","subIssues":{"nodes":[]}}`,
+	"o/r#3":       `{"number":3,"title":"Done","state":"CLOSED","body":"","subIssues":{"nodes":[]}}`,
+	"other/lib#5": `{"number":5,"title":"Lib bug","state":"CLOSED","body":"","subIssues":{"nodes":[]}}`,
+}
+
+func TestSubIssueTree(t *testing.T) {
+	cfg := &domain.Config{Owner: "o", Repo: "r"}
+
+	var calls []string
+	f := NewSubIssue(cfg, treeStub(t, treeIssues, &calls))
+	root, err := f.Tree(1, TreeDepth)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err = PrintTree(&buf, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `#1 Epic (open) [3/5]
+├── [ ] #2 Child (open) [1/2]
+│   ├── [x] #1
+│   └── [ ] step
+├── [x] #3 Done (closed)
+├── [x] write docs [0/1]
+│   └── [ ] review docs
+├── [x] other/lib#5 Lib bug (closed)
+└── [ ] #9 gone
+`
+	if buf.String() != want {
+		t.Errorf("tree =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	wantCalls := []string{"o/r#1", "o/r#2", "o/r#3", "other/lib#5", "o/r#9"}
+	if fmt.Sprint(calls) != fmt.Sprint(wantCalls) {
+		t.Errorf("calls = %v want %v", calls, wantCalls)
+	}
+}
+
+func TestSubIssueTreeNestedInSubIssue(t *testing.T) {
+	issues := map[string]string{
+		"o/r#10": `{"number":10,"title":"Epic","state":"OPEN",
+			"body":"- [x] write docs\n- [ ] #2\n  - [ ] nested step",
+			"subIssues":{"nodes":[{"number":2,"title":"Child","state":"OPEN","repository":{"nameWithOwner":"o/r"}}]}}`,
+	}
+	var calls []string
+	f := NewSubIssue(&domain.Config{Owner: "o", Repo: "r"}, treeStub(t, issues, &calls))
+	root, err := f.Tree(10, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err = PrintTree(&buf, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `#10 Epic (open) [1/2]
+├── [ ] #2 Child (open) [0/1]
+│   └── [ ] nested step
+└── [x] write docs
+`
+	if buf.String() != want {
+		t.Errorf("tree =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestSubIssueTreeErrors(t *testing.T) {
+	var calls []string
+	f := NewSubIssue(&domain.Config{Owner: "o", Repo: "r"}, treeStub(t, treeIssues, &calls))
+
+	if _, err := f.Tree(0, TreeDepth); !errors.Is(err, errNumberIsRequered) {
+		t.Errorf("expected errNumberIsRequered, got %v", err)
+	}
+	if _, err := f.Tree(42, TreeDepth); !errors.Is(err, domain.ErrGraphQL) {
+		t.Errorf("expected ErrGraphQL, got %v", err)
+	}
+
+	missing := NewSubIssue(&domain.Config{Owner: "o", Repo: "r"}, &stubs.GraphQLStub{
+		QueryFunc: stubs.GraphQLResponse(`{"repository":{"issue":null}}`),
+	})
+	if _, err := missing.Tree(1, TreeDepth); !errors.Is(err, errNotFound) {
+		t.Errorf("expected errNotFound, got %v", err)
+	}
+}
+
+func TestSubIssueProgress(t *testing.T) {
+	var calls []string
+	f := NewSubIssue(&domain.Config{Owner: "o", Repo: "r"}, treeStub(t, treeIssues, &calls))
+
+	progress, err := f.Progress(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the unchecked references are looked up, other/lib#5 is closed
+	wantCalls := []string{"o/r#1", "other/lib#5", "o/r#9"}
+	if fmt.Sprint(calls) != fmt.Sprint(wantCalls) {
+		t.Errorf("calls = %v want %v", calls, wantCalls)
+	}
+	if progress.SubIssues != 2 || progress.SubIssuesDone != 1 || progress.Tasks != 3 || progress.TasksDone != 2 {
+		t.Errorf("unexpected counts: %+v", progress)
+	}
+	if progress.Percent() != 60 {
+		t.Errorf("percent = %d want 60", progress.Percent())
+	}
+
+	var buf bytes.Buffer
+	if err = PrintProgress(&buf, progress); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "#1 Epic (open)\nSub-issues: 1/2 closed\nTasks: 2/3 done\nProgress: 60%\n\nOpen:\n- #2 Child (open)\n- #9 gone\n"
+	if buf.String() != want {
+		t.Errorf("progress =\n%q\nwant\n%q", buf.String(), want)
+	}
+
+	if (&Progress{}).Percent() != 100 {
+		t.Error("an issue without items should be complete")
+	}
+}
+
+func TestSubIssueAddRemove(t *testing.T) {
+	cfg := &domain.Config{Owner: "o", Repo: "r"}
+	ids := map[string]string{"o/r#1": "I_1", "o/r#2": "I_2", "x/y#7": "I_7"}
+
+	tests := []struct {
+		name         string
+		parent       int
+		child        string
+		remove       bool
+		wantMutation string
+		wantSub      string
+		wantErr      error
+	}{
+		{name: "add by number", parent: 1, child: "2", wantMutation: addSubIssue, wantSub: "I_2"},
+		{name: "add by reference", parent: 1, child: "x/y#7", wantMutation: addSubIssue, wantSub: "I_7"},
+		{name: "remove", parent: 1, child: "#2", remove: true, wantMutation: removeSubIssue, wantSub: "I_2"},
+		{name: "parent required", child: "2", wantErr: errNumberIsRequered},
+		{name: "invalid child", parent: 1, child: "two", wantErr: errInvalidRef},
+		{name: "child not found", parent: 1, child: "3", wantErr: errNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotMutation string
+			var gotVars map[string]interface{}
+			f := NewSubIssue(cfg, &stubs.GraphQLStub{
+				QueryFunc: func(query string, variables map[string]interface{}, result interface{}) error {
+					if query != subIssueIDQuery {
+						gotMutation, gotVars = query, variables
+						return nil
+					}
+					key := fmt.Sprintf("%v/%v#%v", variables["owner"], variables["repo"], variables["number"])
+					if id, ok := ids[key]; ok {
+						return stubs.GraphQLResponse(`{"repository":{"issue":{"id":"`+id+`"}}}`)(query, variables, result)
+					}
+					return stubs.GraphQLResponse(`{"repository":{"issue":null}}`)(query, variables, result)
+				},
+			})
+
+			var err error
+			if tt.remove {
+				err = f.Remove(tt.parent, tt.child)
+			} else {
+				err = f.Add(tt.parent, tt.child)
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotMutation != tt.wantMutation || gotVars["issue"] != "I_1" || gotVars["sub"] != tt.wantSub {
+				t.Errorf("got mutation %q with %v", gotMutation, gotVars)
+			}
+		})
+	}
+}
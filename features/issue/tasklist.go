@@ -0,0 +1,112 @@
+package issue
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	reTaskItem  = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.*)$`)
+	reShortRef  = regexp.MustCompile(`^(?:([\w.-]+)/([\w.-]+))?#(\d+)$`)
+	reURLRef    = regexp.MustCompile(`^https?://[^/\s]+/([\w.-]+)/([\w.-]+)/(?:issues|pull)/(\d+)(?:[#?]\S*)?$`)
+	reCodeFence = regexp.MustCompile("^\\s*(```|~~~)")
+)
+
+// Task is a checkbox item of a markdown task list.
+type Task struct {
+	Checked bool
+	Text    string
+	// Depth is the nesting level of the item, zero for top level items.
+	Depth int
+	// Line is the 1-based line of the item in the body.
+	Line int
+	// Ref is set when the item starts with an issue reference like #12,
+	// owner/repo#12 or an issue url.
+	Ref *IssueRef
+}
+
+// IssueRef points to an issue, in another repository when Owner and Repo are
+// set.
+type IssueRef struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// String formats the reference the way GitHub does.
+func (r IssueRef) String() string {
+	if r.Owner == "" {
+		return "#" + strconv.Itoa(r.Number)
+	}
+	return r.Owner + "/" + r.Repo + "#" + strconv.Itoa(r.Number)
+}
+
+// Resolve fills in owner and repo for references to the current repository.
+func (r IssueRef) Resolve(owner, repo string) IssueRef {
+	if r.Owner == "" {
+		r.Owner, r.Repo = owner, repo
+	}
+	return r
+}
+
+// ParseTaskList returns the task list items of a markdown body in order.
+// Items inside fenced code blocks are ignored.
+func ParseTaskList(body string) []Task {
+	var tasks []Task
+	var fence string
+
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	for idx, line := range lines {
+		if match := reCodeFence.FindStringSubmatch(line); match != nil {
+			switch {
+			case fence == "":
+				fence = match[1]
+			case fence == match[1]:
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+
+		match := reTaskItem.FindStringSubmatch(strings.ReplaceAll(line, "\t", "    "))
+		if match == nil {
+			continue
+		}
+
+		task := Task{
+			Checked: match[2] != " ",
+			Text:    strings.TrimSpace(match[3]),
+			Depth:   len(match[1]) / 2,
+			Line:    idx + 1,
+		}
+		if fields := strings.Fields(task.Text); len(fields) > 0 {
+			if ref, ok := ParseIssueRef(fields[0]); ok {
+				task.Ref = ref
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+// ParseIssueRef parses #12, owner/repo#12 or an issue url.
+func ParseIssueRef(s string) (*IssueRef, bool) {
+	s = strings.TrimRight(strings.TrimSpace(s), ".,;:)")
+
+	match := reShortRef.FindStringSubmatch(s)
+	if match == nil {
+		match = reURLRef.FindStringSubmatch(s)
+	}
+	if match == nil {
+		return nil, false
+	}
+
+	number, err := strconv.Atoi(match[3])
+	if err != nil || number == 0 {
+		return nil, false
+	}
+	return &IssueRef{Owner: match[1], Repo: match[2], Number: number}, true
+}
@@ -0,0 +1,114 @@
+package issue
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTaskList(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []Task
+	}{
+		{
+			name: "empty body",
+			body: "",
+		},
+		{
+			name: "plain and checked items",
+			body: "Intro\n\n- [ ] write docs\n- [x] add tests\n* [X] release",
+			want: []Task{
+				{Text: "write docs", Line: 3},
+				{Checked: true, Text: "add tests", Line: 4},
+				{Checked: true, Text: "release", Line: 5},
+			},
+		},
+		{
+			name: "references",
+			body: "- [ ] #12\n- [x] octo/tools#7 follow up\n- [ ] https://github.com/octo/app/issues/99\n- [ ] see #5 later",
+			want: []Task{
+				{Text: "#12", Line: 1, Ref: &IssueRef{Number: 12}},
+				{Checked: true, Text: "octo/tools#7 follow up", Line: 2, Ref: &IssueRef{Owner: "octo", Repo: "tools", Number: 7}},
+				{Text: "https://github.com/octo/app/issues/99", Line: 3, Ref: &IssueRef{Owner: "octo", Repo: "app", Number: 99}},
+				{Text: "see #5 later", Line: 4},
+			},
+		},
+		{
+			name: "nesting and ordered lists",
+			body: "1. [ ] parent\n   - [x] child\n    - [ ] grandchild\n\t- [ ] tab child",
+			want: []Task{
+				{Text: "parent", Line: 1},
+				{Checked: true, Text: "child", Depth: 1, Line: 2},
+				{Text: "grandchild", Depth: 2, Line: 3},
+				{Text: "tab child", Depth: 2, Line: 4},
+			},
+		},
+		{
+			name: "ignores code blocks and non tasks",
+			body: "```md\n- [ ] not a task\n```\n- [] broken\n- [y] broken\n-[ ] broken\n- regular item\n~~~\n- [x] fenced\n~~~\n- [ ] real",
+			want: []Task{
+				{Text: "real", Line: 11},
+			},
+		},
+		{
+			name: "windows line endings",
+			body: "- [ ] one\r\n- [x] two\r\n",
+			want: []Task{
+				{Text: "one", Line: 1},
+				{Checked: true, Text: "two", Line: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseTaskList(tt.body)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTaskList() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIssueRef(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   *IssueRef
+		wantOk bool
+	}{
+		{in: "#1", want: &IssueRef{Number: 1}, wantOk: true},
+		{in: "#42,", want: &IssueRef{Number: 42}, wantOk: true},
+		{in: "octo/repo.js#3", want: &IssueRef{Owner: "octo", Repo: "repo.js", Number: 3}, wantOk: true},
+		{in: "https://github.com/octo/repo/issues/8", want: &IssueRef{Owner: "octo", Repo: "repo", Number: 8}, wantOk: true},
+		{in: "https://ghe.example.com/octo/repo/pull/9#issuecomment-1", want: &IssueRef{Owner: "octo", Repo: "repo", Number: 9}, wantOk: true},
+		{in: "#0"},
+		{in: "#abc"},
+		{in: "12"},
+		{in: "octo#3"},
+		{in: "https://github.com/octo/repo/wiki/8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := ParseIssueRef(tt.in)
+			if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseIssueRef(%q) = %+v, %v want %+v, %v", tt.in, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestIssueRefString(t *testing.T) {
+	if got := (IssueRef{Number: 3}).String(); got != "#3" {
+		t.Errorf("got %q", got)
+	}
+	ref := IssueRef{Number: 3}.Resolve("octo", "repo")
+	if got := ref.String(); got != "octo/repo#3" {
+		t.Errorf("got %q", got)
+	}
+	other := IssueRef{Owner: "a", Repo: "b", Number: 1}.Resolve("octo", "repo")
+	if got := other.String(); got != "a/b#1" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,180 @@
+package issue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+)
+
+const (
+	transferMutation    = `mutation($issue: ID!, $repo: ID!) { transferIssue(input: {issueId: $issue, repositoryId: $repo}) { issue { number url } } }`
+	transferRepoQuery   = `query($owner: String!, $repo: String!) { repository(owner: $owner, name: $repo) { id nameWithOwner hasIssuesEnabled } }`
+	transferIssueQuery  = `query($owner: String!, $repo: String!, $number: Int!) { repository(owner: $owner, name: $repo) { issue(number: $number) { id } } }`
+	transferSearchQuery = `query($q: String!, $cursor: String) { search(query: $q, type: ISSUE, first: 100, after: $cursor) { pageInfo { hasNextPage endCursor } nodes { ... on Issue { number title } } } }`
+)
+
+// TransferIssue moves issues to another repository of an owner the token can
+// write to. Transfers are only available through the GraphQL api.
+type TransferIssue interface {
+	Transfer(number int, target string) (*Transferred, error)
+	Target(name string) (*TransferTarget, error)
+	TransferAll(numbers []int, target *TransferTarget) ([]Transferred, error)
+	Search(query string) ([]domain.Issue, error)
+}
+
+// Transferred is an issue after its transfer: From is its number in the
+// configured repository, Number and URL the ones it has in the target.
+type Transferred struct {
+	From   int    `json:"from"`
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+}
+
+type TransferFeature struct {
+	config  *domain.Config
+	graphql client.GraphQLClient
+}
+
+// TransferTarget is a repository checked to take transferred issues.
+type TransferTarget struct {
+	ID               string `json:"id"`
+	NameWithOwner    string `json:"nameWithOwner"`
+	HasIssuesEnabled bool   `json:"hasIssuesEnabled"`
+}
+
+func NewTransfer(config *domain.Config, graphql client.GraphQLClient) *TransferFeature {
+	return &TransferFeature{
+		config:  config,
+		graphql: graphql,
+	}
+}
+
+func (f *TransferFeature) Transfer(number int, target string) (*Transferred, error) {
+	if number == 0 {
+		return nil, errNumberIsRequered
+	}
+	repo, err := f.Target(target)
+	if err != nil {
+		return nil, err
+	}
+	return f.transfer(number, repo)
+}
+
+// TransferAll transfers the issues in order to the target checked by Target.
+// A failed issue does not stop the others; the failures are returned
+// together with the issues that were transferred.
+func (f *TransferFeature) TransferAll(numbers []int, target *TransferTarget) ([]Transferred, error) {
+	var transferred []Transferred
+	var errs []error
+	for _, number := range numbers {
+		t, err := f.transfer(number, target)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("#%d: %w", number, err))
+			continue
+		}
+		transferred = append(transferred, *t)
+	}
+	return transferred, errors.Join(errs...)
+}
+
+// Search returns the issues of the configured repository matching query, in
+// the search syntax of GitHub, like "label:backend is:open". Pull requests
+// are never matched.
+func (f *TransferFeature) Search(query string) ([]domain.Issue, error) {
+	q := fmt.Sprintf("repo:%s/%s is:issue %s", f.config.Owner, f.config.Repo, strings.TrimSpace(query))
+
+	var issues []domain.Issue
+	err := client.Paginate(f.graphql, transferSearchQuery, map[string]interface{}{"q": q}, func(data json.RawMessage) (client.PageInfo, error) {
+		var page struct {
+			Search struct {
+				PageInfo client.PageInfo `json:"pageInfo"`
+				Nodes    []domain.Issue  `json:"nodes"`
+			} `json:"search"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return client.PageInfo{}, errProcessing
+		}
+		for _, issue := range page.Search.Nodes {
+			// nodes of other types decode to an empty issue
+			if issue.Number != 0 {
+				issues = append(issues, issue)
+			}
+		}
+		return page.Search.PageInfo, nil
+	})
+	if err != nil {
+		return nil, errors.Join(errTransferSearch, err)
+	}
+	return issues, nil
+}
+
+// Target looks up the owner/repo repository the issues go to and checks it
+// can take them.
+func (f *TransferFeature) Target(name string) (*TransferTarget, error) {
+	owner, repo, ok := strings.Cut(name, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return nil, fmt.Errorf("%w: %q", errRepoName, name)
+	}
+	if strings.EqualFold(owner, f.config.Owner) && strings.EqualFold(repo, f.config.Repo) {
+		return nil, errTransferSameRepo
+	}
+
+	var result struct {
+		Repository *TransferTarget `json:"repository"`
+	}
+	variables := map[string]interface{}{"owner": owner, "repo": repo}
+	if err := f.graphql.Query(transferRepoQuery, variables, &result); err != nil {
+		return nil, errors.Join(fmt.Errorf("%w: %s", errTransferTarget, name), err)
+	}
+	if result.Repository == nil {
+		return nil, fmt.Errorf("%w: %s", errTransferTarget, name)
+	}
+	if !result.Repository.HasIssuesEnabled {
+		return nil, fmt.Errorf("%w: %s", errTransferNoIssues, name)
+	}
+	if result.Repository.NameWithOwner == "" {
+		result.Repository.NameWithOwner = name
+	}
+	return result.Repository, nil
+}
+
+func (f *TransferFeature) transfer(number int, target *TransferTarget) (*Transferred, error) {
+	var found struct {
+		Repository struct {
+			Issue *struct {
+				ID string `json:"id"`
+			} `json:"issue"`
+		} `json:"repository"`
+	}
+	variables := map[string]interface{}{"owner": f.config.Owner, "repo": f.config.Repo, "number": number}
+	if err := f.graphql.Query(transferIssueQuery, variables, &found); err != nil {
+		return nil, errors.Join(errTransfer, err)
+	}
+	if found.Repository.Issue == nil {
+		return nil, errNotFound
+	}
+
+	var result struct {
+		TransferIssue struct {
+			Issue struct {
+				Number int    `json:"number"`
+				URL    string `json:"url"`
+			} `json:"issue"`
+		} `json:"transferIssue"`
+	}
+	variables = map[string]interface{}{"issue": found.Repository.Issue.ID, "repo": target.ID}
+	if err := f.graphql.Query(transferMutation, variables, &result); err != nil {
+		return nil, errors.Join(errTransfer, err)
+	}
+	return &Transferred{
+		From:   number,
+		Repo:   target.NameWithOwner,
+		Number: result.TransferIssue.Issue.Number,
+		URL:    result.TransferIssue.Issue.URL,
+	}, nil
+}
@@ -0,0 +1,200 @@
+package issue
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+const transferTargetFound = `{"repository":{"id":"R_2","nameWithOwner":"owner/other","hasIssuesEnabled":true}}`
+
+// transferAPI answers the transfer queries; issue 404 does not exist and the
+// mutation numbers the moved issues from 900.
+func transferAPI(target string, mutations *[]map[string]interface{}) *stubs.GraphQLStub {
+	return &stubs.GraphQLStub{
+		QueryFunc: func(query string, variables map[string]interface{}, result interface{}) error {
+			switch query {
+			case transferRepoQuery:
+				return stubs.GraphQLResponse(target)(query, variables, result)
+			case transferIssueQuery:
+				if variables["number"] == 404 {
+					return stubs.GraphQLResponse(`{"repository":{"issue":null}}`)(query, variables, result)
+				}
+				return stubs.GraphQLResponse(`{"repository":{"issue":{"id":"I_1"}}}`)(query, variables, result)
+			case transferMutation:
+				*mutations = append(*mutations, variables)
+				return stubs.GraphQLResponse(`{"transferIssue":{"issue":{"number":900,"url":"https://github.com/owner/other/issues/900"}}}`)(query, variables, result)
+			}
+			return errors.New("unexpected query")
+		},
+	}
+}
+
+func TestTransferFeature(t *testing.T) {
+	cfg := &domain.Config{Owner: "owner", Repo: "repo"}
+
+	tests := []struct {
+		name          string
+		number        int
+		target        string
+		targetData    string
+		want          *Transferred
+		wantMutations int
+		wantErr       error
+	}{
+		{
+			name:          "transfer",
+			number:        12,
+			target:        "owner/other",
+			targetData:    transferTargetFound,
+			want:          &Transferred{From: 12, Repo: "owner/other", Number: 900, URL: "https://github.com/owner/other/issues/900"},
+			wantMutations: 1,
+		},
+		{
+			name:    "number required",
+			target:  "owner/other",
+			wantErr: errNumberIsRequered,
+		},
+		{
+			name:    "invalid target",
+			number:  12,
+			target:  "other",
+			wantErr: errRepoName,
+		},
+		{
+			name:    "same repository",
+			number:  12,
+			target:  "Owner/Repo",
+			wantErr: errTransferSameRepo,
+		},
+		{
+			name:       "target not found",
+			number:     12,
+			target:     "owner/missing",
+			targetData: `{"repository":null}`,
+			wantErr:    errTransferTarget,
+		},
+		{
+			name:       "issues disabled",
+			number:     12,
+			target:     "owner/other",
+			targetData: `{"repository":{"id":"R_2","nameWithOwner":"owner/other","hasIssuesEnabled":false}}`,
+			wantErr:    errTransferNoIssues,
+		},
+		{
+			name:       "issue not found",
+			number:     404,
+			target:     "owner/other",
+			targetData: transferTargetFound,
+			wantErr:    errNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mutations []map[string]interface{}
+			f := NewTransfer(cfg, transferAPI(tt.targetData, &mutations))
+
+			got, err := f.Transfer(tt.number, tt.target)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v want %+v", got, tt.want)
+			}
+			if len(mutations) != tt.wantMutations {
+				t.Fatalf("got %d mutations want %d", len(mutations), tt.wantMutations)
+			}
+			if tt.wantMutations > 0 && (mutations[0]["issue"] != "I_1" || mutations[0]["repo"] != "R_2") {
+				t.Errorf("unexpected mutation variables: %v", mutations[0])
+			}
+		})
+	}
+}
+
+func TestTransferAll(t *testing.T) {
+	var mutations []map[string]interface{}
+	f := NewTransfer(&domain.Config{Owner: "owner", Repo: "repo"}, transferAPI(transferTargetFound, &mutations))
+
+	target, err := f.Target("owner/other")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := f.TransferAll([]int{1, 404, 3}, target)
+
+	if !errors.Is(err, errNotFound) || !strings.Contains(err.Error(), "#404") {
+		t.Errorf("got error %v want the failure of #404", err)
+	}
+	if len(got) != 2 || got[0].From != 1 || got[1].From != 3 || got[0].Repo != "owner/other" {
+		t.Errorf("got %+v want #1 and #3 transferred", got)
+	}
+}
+
+func TestTransferTarget(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		targetData string
+		want       *TransferTarget
+		wantErr    error
+	}{
+		{name: "found", target: "owner/other", targetData: transferTargetFound, want: &TransferTarget{ID: "R_2", NameWithOwner: "owner/other", HasIssuesEnabled: true}},
+		{name: "missing", target: "owner/missing", targetData: `{"repository":null}`, wantErr: errTransferTarget},
+		{name: "issues disabled", target: "owner/other", targetData: `{"repository":{"id":"R_2","hasIssuesEnabled":false}}`, wantErr: errTransferNoIssues},
+		{name: "same repository", target: "Owner/Repo", wantErr: errTransferSameRepo},
+		{name: "bad name", target: "owner", wantErr: errRepoName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mutations []map[string]interface{}
+			f := NewTransfer(&domain.Config{Owner: "owner", Repo: "repo"}, transferAPI(tt.targetData, &mutations))
+
+			got, err := f.Target(tt.target)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransferSearch(t *testing.T) {
+	pages := []string{
+		`{"search":{"pageInfo":{"hasNextPage":true,"endCursor":"c1"},"nodes":[{"number":1,"title":"One"},{}]}}`,
+		`{"search":{"pageInfo":{"hasNextPage":false},"nodes":[{"number":2,"title":"Two"}]}}`,
+	}
+	var queries []string
+	graphqlStub := &stubs.GraphQLStub{
+		QueryFunc: func(query string, variables map[string]interface{}, result interface{}) error {
+			queries = append(queries, variables["q"].(string))
+			data := pages[0]
+			if variables["cursor"] == "c1" {
+				data = pages[1]
+			}
+			return stubs.GraphQLResponse(data)(query, variables, result)
+		},
+	}
+	f := NewTransfer(&domain.Config{Owner: "owner", Repo: "repo"}, graphqlStub)
+
+	got, err := f.Search(" label:backend is:open ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []domain.Issue{{Number: 1, Title: "One"}, {Number: 2, Title: "Two"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+	if len(queries) != 2 || queries[0] != "repo:owner/repo is:issue label:backend is:open" {
+		t.Errorf("unexpected search queries: %q", queries)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"git-issues/domain"
 	"git-issues/service/client"
@@ -12,6 +13,9 @@ import (
 
 type UpdateIssue interface {
 	Update(number int) error
+	Reopen(number int) error
+	AddLabels(number int, labels ...string) error
+	Assign(number int, logins ...string) error
 }
 
 type UpdateFeature struct {
@@ -64,3 +68,93 @@ func (f *UpdateFeature) Update(number int) error {
 	fmt.Printf("Issue atualizada com sucesso!\nURL: %v\n", result["html_url"])
 	return nil
 }
+
+func (f *UpdateFeature) Reopen(number int) error {
+	issue, err := f.patch(number, func(issue *domain.Issue) {
+		issue.State = "open"
+	})
+	if err != nil {
+		return errors.Join(errReopen, err)
+	}
+
+	if issue.State != "open" {
+		return errReopen
+	}
+	return nil
+}
+
+func (f *UpdateFeature) AddLabels(number int, labels ...string) error {
+	_, err := f.patch(number, func(issue *domain.Issue) {
+		for _, name := range labels {
+			if !hasLabel(issue, name) {
+				issue.Labels = append(issue.Labels, domain.Label{Name: name})
+			}
+		}
+	})
+	if err != nil {
+		return errors.Join(errLabel, err)
+	}
+	return nil
+}
+
+func (f *UpdateFeature) Assign(number int, logins ...string) error {
+	_, err := f.patch(number, func(issue *domain.Issue) {
+		for _, login := range logins {
+			if !hasAssignee(issue, login) {
+				issue.Assignees = append(issue.Assignees, domain.User{Login: login})
+			}
+		}
+	})
+	if err != nil {
+		return errors.Join(errAssign, err)
+	}
+	return nil
+}
+
+// patch fetches the issue, applies change and sends the result back.
+func (f *UpdateFeature) patch(number int, change func(issue *domain.Issue)) (*domain.Issue, error) {
+	if number == 0 {
+		return nil, errNumberIsRequered
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d", f.config.APIBaseURL, f.config.Owner, f.config.Repo, number)
+	response, err := f.client.MakeRequest("GET", url, nil)
+	if err != nil {
+		return nil, errNotFound
+	}
+
+	issue := &domain.Issue{}
+	if err = json.Unmarshal(response, issue); err != nil {
+		return nil, errProcessing
+	}
+
+	change(issue)
+
+	response, err = f.client.MakeRequest("PATCH", url, issue)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(response, issue); err != nil {
+		return nil, errProcessing
+	}
+	return issue, nil
+}
+
+func hasLabel(issue *domain.Issue, name string) bool {
+	for _, l := range issue.Labels {
+		if strings.EqualFold(l.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAssignee(issue *domain.Issue, login string) bool {
+	for _, u := range issue.Assignees {
+		if strings.EqualFold(u.Login, login) {
+			return true
+		}
+	}
+	return false
+}
@@ -101,3 +101,99 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestReopen(t *testing.T) {
+	cfg := &domain.Config{}
+
+	tests := []struct {
+		name       string
+		number     int
+		clientStub *stubs.ClientStub
+		wantErr    error
+	}{
+		{
+			name:   "successful reopen",
+			number: 1,
+			clientStub: &stubs.ClientStub{
+				MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+					if method == "PATCH" && data.State != "open" {
+						t.Errorf("unexpected state sent: %q", data.State)
+					}
+					return []byte(`{"number":1,"state":"open","title":"t"}`), nil
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:       "number required",
+			clientStub: &stubs.ClientStub{},
+			wantErr:    errNumberIsRequered,
+		},
+		{
+			name:   "state not changed",
+			number: 2,
+			clientStub: &stubs.ClientStub{
+				MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+					return []byte(`{"number":2,"state":"closed","title":"t"}`), nil
+				},
+			},
+			wantErr: errReopen,
+		},
+		{
+			name:   "not found",
+			number: 3,
+			clientStub: &stubs.ClientStub{
+				MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+					return nil, domain.ErrApi
+				},
+			},
+			wantErr: errNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewUpdate(cfg, &stubs.EditorStub{}, tt.clientStub)
+			err := f.Reopen(tt.number)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("unexpected error got: %v, want: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddLabelsAndAssign(t *testing.T) {
+	var sent domain.Issue
+	clientStub := &stubs.ClientStub{
+		MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+			if method == "PATCH" {
+				sent = *data
+				sent.Labels = append([]domain.Label(nil), data.Labels...)
+				sent.Assignees = append([]domain.User(nil), data.Assignees...)
+			}
+			return []byte(`{"number":1,"title":"t","labels":[{"name":"bug"}],"assignees":[{"login":"octocat"}]}`), nil
+		},
+	}
+	f := NewUpdate(&domain.Config{}, &stubs.EditorStub{}, clientStub)
+
+	if err := f.AddLabels(1, "bug", "ui"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sent.Labels) != 2 || sent.Labels[1].Name != "ui" {
+		t.Errorf("unexpected labels sent: %v", sent.Labels)
+	}
+
+	if err := f.Assign(1, "OctoCat", "hubot"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sent.Assignees) != 2 || sent.Assignees[1].Login != "hubot" {
+		t.Errorf("unexpected assignees sent: %v", sent.Assignees)
+	}
+
+	if err := f.AddLabels(0, "bug"); !errors.Is(err, errLabel) {
+		t.Errorf("unexpected error got: %v, want: %v", err, errLabel)
+	}
+	if err := f.Assign(0, "hubot"); !errors.Is(err, errNumberIsRequered) {
+		t.Errorf("unexpected error got: %v, want: %v", err, errNumberIsRequered)
+	}
+}
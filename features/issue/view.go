@@ -10,6 +10,7 @@ import (
 
 type ViewIssue interface {
 	View(issueNumber int) (*domain.Issue, error)
+	Comments(issueNumber int) ([]domain.Comment, error)
 }
 
 type ViewFeature struct {
@@ -42,3 +43,22 @@ func (f *ViewFeature) View(issueNumber int) (*domain.Issue, error) {
 	}
 	return issue, nil
 }
+
+func (f *ViewFeature) Comments(issueNumber int) ([]domain.Comment, error) {
+	if issueNumber == 0 {
+		return nil, errNumberIsRequered
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", f.config.APIBaseURL, f.config.Owner, f.config.Repo, issueNumber)
+
+	response, err := f.client.MakeRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := []domain.Comment{}
+	if err = json.Unmarshal(response, &comments); err != nil {
+		return nil, errProcessing
+	}
+	return comments, nil
+}
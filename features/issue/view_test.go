@@ -56,3 +56,53 @@ func TestViewIssue(t *testing.T) {
 		})
 	}
 }
+
+func TestViewComments(t *testing.T) {
+	tests := []struct {
+		name       string
+		number     int
+		clientStub client.GitHubClient
+		wantLen    int
+		wantErr    error
+	}{
+		{
+			name:   "success",
+			number: 1,
+			clientStub: &stubs.ClientStub{
+				MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+					return []byte(`[{"id":1,"user":{"login":"octocat"},"body":"first"},{"id":2,"user":{"login":"hubot"},"body":"second"}]`), nil
+				},
+			},
+			wantLen: 2,
+		},
+		{
+			name:       "number required",
+			clientStub: &stubs.ClientStub{},
+			wantErr:    errNumberIsRequered,
+		},
+		{
+			name:   "invalid json",
+			number: 1,
+			clientStub: &stubs.ClientStub{
+				MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+					return []byte(`{`), nil
+				},
+			},
+			wantErr: errProcessing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewView(&domain.Config{}, tt.clientStub)
+			got, err := f.Comments(tt.number)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("got %d comments, want %d", len(got), tt.wantLen)
+			}
+		})
+	}
+}
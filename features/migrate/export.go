@@ -0,0 +1,258 @@
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+)
+
+// ArchiveVersion is the version of the archive format Export writes. Import
+// refuses archives of newer versions.
+const ArchiveVersion = 1
+
+// pageSize is the largest page the list endpoints return.
+const pageSize = 100
+
+var (
+	errExport         = errors.New("could not export issues")
+	errImport         = errors.New("could not import issues")
+	errArchive        = errors.New("invalid archive")
+	errArchiveVersion = errors.New("unsupported archive version")
+	errMapping        = errors.New("invalid mapping, expected old=new")
+	errMappingFile    = errors.New("could not use the mapping file")
+)
+
+// Migrate moves issues out of a repository into files and back into another
+// repository.
+type Migrate interface {
+	Export() (*Archive, error)
+	Import(archive *Archive, options ImportOptions) (*ImportResult, error)
+}
+
+// Archive holds the issues of a repository with their comments, and the
+// labels and milestones they use.
+type Archive struct {
+	Version    int                `json:"version"`
+	Repository string             `json:"repository"`
+	ExportedAt string             `json:"exported_at"`
+	Labels     []domain.Label     `json:"labels"`
+	Milestones []domain.Milestone `json:"milestones"`
+	Issues     []Issue            `json:"issues"`
+}
+
+// Issue is an exported issue. Labels and Milestone are kept by name, as
+// numbers and ids do not carry over between repositories.
+type Issue struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body"`
+	State       string    `json:"state"`
+	StateReason string    `json:"state_reason,omitempty"`
+	Author      string    `json:"author"`
+	CreatedAt   string    `json:"created_at"`
+	ClosedAt    string    `json:"closed_at,omitempty"`
+	Labels      []string  `json:"labels"`
+	Assignees   []string  `json:"assignees"`
+	Milestone   string    `json:"milestone,omitempty"`
+	Comments    []Comment `json:"comments"`
+}
+
+type Comment struct {
+	Author    string `json:"author"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"created_at"`
+}
+
+type Feature struct {
+	config *domain.Config
+	client client.GitHubClient
+	out    io.Writer
+	now    func() time.Time
+	sleep  func(time.Duration)
+}
+
+func New(config *domain.Config, client client.GitHubClient) *Feature {
+	return &Feature{
+		config: config,
+		client: client,
+		out:    os.Stdout,
+		now:    time.Now,
+		sleep:  time.Sleep,
+	}
+}
+
+// Export reads every issue of the repository, open and closed, oldest
+// first. Pull requests are left out.
+func (f *Feature) Export() (*Archive, error) {
+	archive := &Archive{
+		Version:    ArchiveVersion,
+		Repository: f.config.Owner + "/" + f.config.Repo,
+		ExportedAt: f.now().UTC().Format(time.RFC3339),
+		Labels:     []domain.Label{},
+		Milestones: []domain.Milestone{},
+	}
+
+	var err error
+	if archive.Labels, err = f.labels(); err != nil {
+		return nil, errors.Join(errExport, err)
+	}
+	if archive.Milestones, err = f.milestones(); err != nil {
+		return nil, errors.Join(errExport, err)
+	}
+
+	var issues []domain.Issue
+	err = f.pages(f.repoURL("/issues?state=all&sort=created&direction=asc"), func(data []byte) (int, error) {
+		page := []domain.Issue{}
+		err := json.Unmarshal(data, &page)
+		issues = append(issues, page...)
+		return len(page), err
+	})
+	if err != nil {
+		return nil, errors.Join(errExport, err)
+	}
+
+	comments, err := f.comments()
+	if err != nil {
+		return nil, errors.Join(errExport, err)
+	}
+
+	archive.Issues = []Issue{}
+	for _, issue := range issues {
+		if issue.PullRequest != nil {
+			continue
+		}
+		archive.Issues = append(archive.Issues, newIssue(&issue, comments[issue.Number]))
+	}
+	sort.SliceStable(archive.Issues, func(i, j int) bool {
+		return archive.Issues[i].Number < archive.Issues[j].Number
+	})
+	return archive, nil
+}
+
+func (f *Feature) labels() ([]domain.Label, error) {
+	labels := []domain.Label{}
+	err := f.pages(f.repoURL("/labels?"), func(data []byte) (int, error) {
+		page := []domain.Label{}
+		err := json.Unmarshal(data, &page)
+		labels = append(labels, page...)
+		return len(page), err
+	})
+	return labels, err
+}
+
+func (f *Feature) milestones() ([]domain.Milestone, error) {
+	milestones := []domain.Milestone{}
+	err := f.pages(f.repoURL("/milestones?state=all"), func(data []byte) (int, error) {
+		page := []domain.Milestone{}
+		err := json.Unmarshal(data, &page)
+		milestones = append(milestones, page...)
+		return len(page), err
+	})
+	return milestones, err
+}
+
+// comments reads the comments of every issue at once and groups them by
+// issue number, in the order they were written.
+func (f *Feature) comments() (map[int][]Comment, error) {
+	comments := map[int][]Comment{}
+	err := f.pages(f.repoURL("/issues/comments?sort=created&direction=asc"), func(data []byte) (int, error) {
+		page := []domain.Comment{}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return 0, err
+		}
+		for _, c := range page {
+			number, err := strconv.Atoi(c.IssueURL[strings.LastIndex(c.IssueURL, "/")+1:])
+			if err != nil {
+				continue
+			}
+			comments[number] = append(comments[number], Comment{Author: c.User.Login, Body: c.Body, CreatedAt: c.CreatedAt})
+		}
+		return len(page), nil
+	})
+	return comments, err
+}
+
+// pages requests the url page by page until a page is not full. read
+// decodes a page and returns how many items it had.
+func (f *Feature) pages(url string, read func(data []byte) (int, error)) error {
+	separator := "&"
+	if strings.HasSuffix(url, "?") {
+		separator = ""
+	}
+	for page := 1; ; page++ {
+		response, err := f.client.MakeRequest("GET", fmt.Sprintf("%s%sper_page=%d&page=%d", url, separator, pageSize, page), nil)
+		if err != nil {
+			return err
+		}
+		count, err := read(response)
+		if err != nil {
+			return errors.Join(errArchive, err)
+		}
+		if count < pageSize {
+			return nil
+		}
+	}
+}
+
+func (f *Feature) repoURL(path string) string {
+	return fmt.Sprintf("%s/repos/%s/%s%s", f.config.APIBaseURL, f.config.Owner, f.config.Repo, path)
+}
+
+func newIssue(issue *domain.Issue, comments []Comment) Issue {
+	exported := Issue{
+		Number:      issue.Number,
+		Title:       issue.Title,
+		Body:        issue.Body,
+		State:       issue.State,
+		StateReason: issue.StateReason,
+		CreatedAt:   issue.CreatedAt,
+		ClosedAt:    issue.ClosedAt,
+		Labels:      []string{},
+		Assignees:   []string{},
+		Comments:    comments,
+	}
+	if issue.User != nil {
+		exported.Author = issue.User.Login
+	}
+	if issue.Milestone != nil {
+		exported.Milestone = issue.Milestone.Title
+	}
+	if exported.Comments == nil {
+		exported.Comments = []Comment{}
+	}
+	for _, l := range issue.Labels {
+		exported.Labels = append(exported.Labels, l.Name)
+	}
+	for _, u := range issue.Assignees {
+		exported.Assignees = append(exported.Assignees, u.Login)
+	}
+	return exported
+}
+
+// WriteArchive writes the archive as indented JSON.
+func WriteArchive(w io.Writer, archive *Archive) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(archive)
+}
+
+// ReadArchive reads an archive written by WriteArchive.
+func ReadArchive(r io.Reader) (*Archive, error) {
+	archive := &Archive{}
+	if err := json.NewDecoder(r).Decode(archive); err != nil {
+		return nil, errors.Join(errArchive, err)
+	}
+	if archive.Version < 1 || archive.Version > ArchiveVersion {
+		return nil, fmt.Errorf("%w %d, this version reads up to %d", errArchiveVersion, archive.Version, ArchiveVersion)
+	}
+	return archive, nil
+}
@@ -0,0 +1,115 @@
+package migrate
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+var exportedAt = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// sourceAPI answers the list endpoints of the source repository.
+func sourceAPI(t *testing.T) *stubs.ClientStub {
+	return &stubs.ClientStub{
+		MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+			path := strings.TrimPrefix(url, "https://api.example.com/repos/old/repo")
+			switch path {
+			case "/labels?per_page=100&page=1":
+				return []byte(`[{"name":"bug","color":"d73a4a","description":"Something is wrong"}]`), nil
+			case "/milestones?state=all&per_page=100&page=1":
+				return []byte(`[{"number":3,"title":"v1","state":"open"}]`), nil
+			case "/issues?state=all&sort=created&direction=asc&per_page=100&page=1":
+				return []byte(`[
+					{"number":2,"title":"Add export","body":"b2","state":"open","user":{"login":"bob"},"created_at":"2026-01-02T10:00:00Z","assignees":[{"login":"bob"}]},
+					{"number":1,"title":"Crash","body":"b1","state":"closed","state_reason":"completed","user":{"login":"ann"},"created_at":"2026-01-01T10:00:00Z","closed_at":"2026-01-03T10:00:00Z","labels":[{"name":"bug"}],"milestone":{"number":3,"title":"v1"}},
+					{"number":3,"title":"A pull request","state":"open","pull_request":{"html_url":"https://github.com/old/repo/pull/3"}}
+				]`), nil
+			case "/issues/comments?sort=created&direction=asc&per_page=100&page=1":
+				return []byte(`[
+					{"user":{"login":"bob"},"body":"Seen too","created_at":"2026-01-01T11:00:00Z","issue_url":"https://api.example.com/repos/old/repo/issues/1"},
+					{"user":{"login":"ann"},"body":"Fixed","created_at":"2026-01-03T09:00:00Z","issue_url":"https://api.example.com/repos/old/repo/issues/1"}
+				]`), nil
+			}
+			t.Errorf("unexpected request %s %s", method, url)
+			return nil, errors.New("404 Not Found")
+		},
+	}
+}
+
+func testArchive() *Archive {
+	return &Archive{
+		Version:    ArchiveVersion,
+		Repository: "old/repo",
+		ExportedAt: "2026-03-01T09:00:00Z",
+		Labels:     []domain.Label{{Name: "bug", Color: "d73a4a", Description: "Something is wrong"}},
+		Milestones: []domain.Milestone{{Number: 3, Title: "v1", State: "open"}},
+		Issues: []Issue{
+			{
+				Number: 1, Title: "Crash", Body: "b1", State: "closed", StateReason: "completed", Author: "ann",
+				CreatedAt: "2026-01-01T10:00:00Z", ClosedAt: "2026-01-03T10:00:00Z",
+				Labels: []string{"bug"}, Assignees: []string{}, Milestone: "v1",
+				Comments: []Comment{
+					{Author: "bob", Body: "Seen too", CreatedAt: "2026-01-01T11:00:00Z"},
+					{Author: "ann", Body: "Fixed", CreatedAt: "2026-01-03T09:00:00Z"},
+				},
+			},
+			{
+				Number: 2, Title: "Add export", Body: "b2", State: "open", Author: "bob",
+				CreatedAt: "2026-01-02T10:00:00Z", Labels: []string{}, Assignees: []string{"bob"}, Comments: []Comment{},
+			},
+		},
+	}
+}
+
+func TestExport(t *testing.T) {
+	f := New(&domain.Config{APIBaseURL: "https://api.example.com", Owner: "old", Repo: "repo"}, sourceAPI(t))
+	f.now = func() time.Time { return exportedAt }
+
+	got, err := f.Export()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, testArchive()) {
+		t.Errorf("got %+v\nwant %+v", got, testArchive())
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteArchive(&buf, testArchive()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := ReadArchive(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, testArchive()) {
+		t.Errorf("got %+v\nwant %+v", got, testArchive())
+	}
+}
+
+func TestReadArchiveErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{name: "not json", data: "issues", wantErr: errArchive},
+		{name: "newer version", data: `{"version": 2}`, wantErr: errArchiveVersion},
+		{name: "no version", data: `{"issues": []}`, wantErr: errArchiveVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadArchive(strings.NewReader(tt.data)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
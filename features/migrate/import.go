@@ -0,0 +1,383 @@
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-issues/domain"
+)
+
+// createPause spaces out the requests that create content, which GitHub
+// limits more strictly than others.
+const createPause = time.Second
+
+// defaultLabelColor is used for labels the archive has no color for.
+const defaultLabelColor = "ededed"
+
+// ImportOptions tune Import. LabelMap and MilestoneMap rename the labels and
+// milestones of the archive, by name. MappingFile records what was imported
+// so a second run skips it.
+type ImportOptions struct {
+	LabelMap     map[string]string
+	MilestoneMap map[string]string
+	MappingFile  string
+	DryRun       bool
+}
+
+// Mapping is the content of the mapping file: for each issue number of the
+// source the issue created for it and how far its import got.
+type Mapping struct {
+	Source string                    `json:"source"`
+	Target string                    `json:"target"`
+	Issues map[string]*ImportedIssue `json:"issues"`
+}
+
+// ImportedIssue is pending from just before its issue is created until the
+// number is known, so a run stopped in between looks the issue up instead of
+// creating it twice.
+type ImportedIssue struct {
+	Number   int  `json:"number"`
+	Comments int  `json:"comments"`
+	Closed   bool `json:"closed"`
+	Pending  bool `json:"pending,omitempty"`
+}
+
+// ImportResult counts what Import did, or would do on a dry run.
+type ImportResult struct {
+	Created           int
+	Skipped           int
+	Comments          int
+	LabelsCreated     int
+	MilestonesCreated int
+}
+
+type issuePayload struct {
+	Title     string   `json:"title"`
+	Body      string   `json:"body"`
+	Labels    []string `json:"labels,omitempty"`
+	Milestone int      `json:"milestone,omitempty"`
+}
+
+type statePayload struct {
+	State       string `json:"state"`
+	StateReason string `json:"state_reason,omitempty"`
+}
+
+// Import recreates the issues of the archive in the configured repository,
+// oldest first, with their comments and state. Labels and milestones
+// missing there are created. Each issue and comment starts with who wrote
+// it and where. The mapping file is saved after every step, so a failed run
+// can be repeated and continues where it stopped.
+func (f *Feature) Import(archive *Archive, options ImportOptions) (*ImportResult, error) {
+	target := f.config.Owner + "/" + f.config.Repo
+	mapping, err := loadMapping(options.MappingFile, archive.Repository, target)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{}
+	labels, err := f.importLabels(archive, options, result)
+	if err != nil {
+		return result, errors.Join(errImport, err)
+	}
+	milestones, err := f.importMilestones(archive, options, result)
+	if err != nil {
+		return result, errors.Join(errImport, err)
+	}
+
+	for _, i := range archive.Issues {
+		key := strconv.Itoa(i.Number)
+		imported := mapping.Issues[key]
+		closed := i.State == "closed"
+		if imported != nil && !imported.Pending && imported.Comments >= len(i.Comments) && (imported.Closed || !closed) {
+			result.Skipped++
+			fmt.Fprintf(f.out, "skipped #%d, imported as #%d\n", i.Number, imported.Number)
+			continue
+		}
+
+		if imported == nil || imported.Pending {
+			if options.DryRun {
+				result.Created++
+				result.Comments += len(i.Comments)
+				fmt.Fprintf(f.out, "would create #%d %s (%d comments, %s)\n", i.Number, i.Title, len(i.Comments), i.State)
+				continue
+			}
+			number := 0
+			if imported != nil {
+				if number, err = f.findImported(archive.Repository, &i); err != nil {
+					return result, errors.Join(errImport, fmt.Errorf("issue #%d: %w", i.Number, err))
+				}
+			}
+			if number == 0 {
+				mapping.Issues[key] = &ImportedIssue{Pending: true}
+				if err = saveMapping(options.MappingFile, mapping); err != nil {
+					return result, err
+				}
+				if number, err = f.createIssue(archive.Repository, &i, labels, milestones, options); err != nil {
+					return result, errors.Join(errImport, fmt.Errorf("issue #%d: %w", i.Number, err))
+				}
+				fmt.Fprintf(f.out, "created #%d from #%d %s\n", number, i.Number, i.Title)
+			} else {
+				fmt.Fprintf(f.out, "found #%d created from #%d %s\n", number, i.Number, i.Title)
+			}
+			imported = &ImportedIssue{Number: number}
+			mapping.Issues[key] = imported
+			if err = saveMapping(options.MappingFile, mapping); err != nil {
+				return result, err
+			}
+			result.Created++
+		}
+
+		for _, c := range i.Comments[imported.Comments:] {
+			if options.DryRun {
+				result.Comments++
+				continue
+			}
+			url := f.repoURL(fmt.Sprintf("/issues/%d/comments", imported.Number))
+			body := commentAttribution(&c) + c.Body
+			if _, err := f.client.MakeJSONRequest("POST", url, map[string]string{"body": body}); err != nil {
+				return result, errors.Join(errImport, fmt.Errorf("comment on #%d: %w", i.Number, err))
+			}
+			f.sleep(createPause)
+			imported.Comments++
+			result.Comments++
+			if err := saveMapping(options.MappingFile, mapping); err != nil {
+				return result, err
+			}
+		}
+
+		if closed && !imported.Closed {
+			if options.DryRun {
+				continue
+			}
+			url := f.repoURL(fmt.Sprintf("/issues/%d", imported.Number))
+			if _, err := f.client.MakeJSONRequest("PATCH", url, statePayload{State: "closed", StateReason: i.StateReason}); err != nil {
+				return result, errors.Join(errImport, fmt.Errorf("close #%d: %w", imported.Number, err))
+			}
+			imported.Closed = true
+			if err := saveMapping(options.MappingFile, mapping); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// importLabels creates the labels the issues use that the repository lacks
+// and returns the names labels have there.
+func (f *Feature) importLabels(archive *Archive, options ImportOptions, result *ImportResult) (map[string]string, error) {
+	existing, err := f.labels()
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]string{}
+	for _, l := range existing {
+		names[strings.ToLower(l.Name)] = l.Name
+	}
+	source := map[string]domain.Label{}
+	for _, l := range archive.Labels {
+		source[l.Name] = l
+	}
+
+	for _, i := range archive.Issues {
+		for _, name := range i.Labels {
+			mapped := mapName(options.LabelMap, name)
+			if _, ok := names[strings.ToLower(mapped)]; ok {
+				continue
+			}
+			label := domain.Label{Name: mapped, Color: source[name].Color, Description: source[name].Description}
+			if label.Color == "" {
+				label.Color = defaultLabelColor
+			}
+			if !options.DryRun {
+				if _, err = f.client.MakeJSONRequest("POST", f.repoURL("/labels"), label); err != nil {
+					return nil, fmt.Errorf("label %s: %w", mapped, err)
+				}
+			}
+			names[strings.ToLower(mapped)] = mapped
+			result.LabelsCreated++
+			fmt.Fprintf(f.out, "%s label %s\n", action(options.DryRun), mapped)
+		}
+	}
+	return names, nil
+}
+
+// importMilestones creates the milestones the issues use that the
+// repository lacks and returns the number of each title.
+func (f *Feature) importMilestones(archive *Archive, options ImportOptions, result *ImportResult) (map[string]int, error) {
+	existing, err := f.milestones()
+	if err != nil {
+		return nil, err
+	}
+	numbers := map[string]int{}
+	for _, m := range existing {
+		numbers[m.Title] = m.Number
+	}
+	source := map[string]domain.Milestone{}
+	for _, m := range archive.Milestones {
+		source[m.Title] = m
+	}
+
+	for _, i := range archive.Issues {
+		if i.Milestone == "" {
+			continue
+		}
+		title := mapName(options.MilestoneMap, i.Milestone)
+		if _, ok := numbers[title]; ok {
+			continue
+		}
+		milestone := source[i.Milestone]
+		milestone.Number, milestone.Title = 0, title
+		if !options.DryRun {
+			response, err := f.client.MakeJSONRequest("POST", f.repoURL("/milestones"), milestone)
+			if err != nil {
+				return nil, fmt.Errorf("milestone %s: %w", title, err)
+			}
+			if err = json.Unmarshal(response, &milestone); err != nil {
+				return nil, errors.Join(errArchive, err)
+			}
+		}
+		numbers[title] = milestone.Number
+		result.MilestonesCreated++
+		fmt.Fprintf(f.out, "%s milestone %s\n", action(options.DryRun), title)
+	}
+	return numbers, nil
+}
+
+func (f *Feature) createIssue(source string, i *Issue, labels map[string]string, milestones map[string]int, options ImportOptions) (int, error) {
+	payload := issuePayload{
+		Title: i.Title,
+		Body:  issueAttribution(source, i) + i.Body,
+	}
+	for _, name := range i.Labels {
+		payload.Labels = append(payload.Labels, labels[strings.ToLower(mapName(options.LabelMap, name))])
+	}
+	if i.Milestone != "" {
+		payload.Milestone = milestones[mapName(options.MilestoneMap, i.Milestone)]
+	}
+
+	response, err := f.client.MakeJSONRequest("POST", f.repoURL("/issues"), payload)
+	if err != nil {
+		return 0, err
+	}
+	f.sleep(createPause)
+
+	created := domain.Issue{}
+	if err = json.Unmarshal(response, &created); err != nil || created.Number == 0 {
+		return 0, errors.Join(errArchive, err)
+	}
+	return created.Number, nil
+}
+
+// findImported looks among the newest issues of the repository for the one
+// created from i, by its attribution, and returns its number or 0.
+func (f *Feature) findImported(source string, i *Issue) (int, error) {
+	url := f.repoURL(fmt.Sprintf("/issues?state=all&sort=created&direction=desc&per_page=%d", pageSize))
+	response, err := f.client.MakeRequest("GET", url, nil)
+	if err != nil {
+		return 0, err
+	}
+	var issues []domain.Issue
+	if err = json.Unmarshal(response, &issues); err != nil {
+		return 0, errors.Join(errArchive, err)
+	}
+	attribution := issueAttribution(source, i)
+	for _, found := range issues {
+		if found.PullRequest == nil && strings.HasPrefix(found.Body, attribution) {
+			return found.Number, nil
+		}
+	}
+	return 0, nil
+}
+
+// issueAttribution links back to the original issue, so it reads
+// "Originally owner/repo#12 by @octocat on 2026-01-02."
+func issueAttribution(source string, i *Issue) string {
+	return fmt.Sprintf("_Originally %s#%d by @%s on %s._\n\n", source, i.Number, i.Author, day(i.CreatedAt))
+}
+
+func commentAttribution(c *Comment) string {
+	return fmt.Sprintf("_Originally by @%s on %s._\n\n", c.Author, day(c.CreatedAt))
+}
+
+// day keeps the date of an RFC 3339 timestamp.
+func day(timestamp string) string {
+	if len(timestamp) >= len("2006-01-02") {
+		return timestamp[:len("2006-01-02")]
+	}
+	return timestamp
+}
+
+func mapName(mapping map[string]string, name string) string {
+	if mapped, ok := mapping[name]; ok {
+		return mapped
+	}
+	return name
+}
+
+func action(dryRun bool) string {
+	if dryRun {
+		return "would create"
+	}
+	return "created"
+}
+
+// ParseMapping reads old=new pairs, as given to --label-map and
+// --milestone-map.
+func ParseMapping(pairs []string) (map[string]string, error) {
+	mapping := map[string]string{}
+	for _, pair := range pairs {
+		from, to, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+			return nil, fmt.Errorf("%w: %q", errMapping, pair)
+		}
+		mapping[strings.TrimSpace(from)] = strings.TrimSpace(to)
+	}
+	return mapping, nil
+}
+
+// loadMapping reads the mapping file, or starts a new mapping when there is
+// none. A mapping of other repositories is refused, as its numbers would
+// skip the wrong issues.
+func loadMapping(file, source, target string) (*Mapping, error) {
+	mapping := &Mapping{Source: source, Target: target, Issues: map[string]*ImportedIssue{}}
+	if file == "" {
+		return mapping, nil
+	}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return mapping, nil
+	}
+	if err != nil {
+		return nil, errors.Join(errMappingFile, err)
+	}
+	if err = json.Unmarshal(data, mapping); err != nil {
+		return nil, errors.Join(errMappingFile, err)
+	}
+	if !strings.EqualFold(mapping.Source, source) || !strings.EqualFold(mapping.Target, target) {
+		return nil, fmt.Errorf("%w: %s maps %s to %s, not %s to %s", errMappingFile, file, mapping.Source, mapping.Target, source, target)
+	}
+	if mapping.Issues == nil {
+		mapping.Issues = map[string]*ImportedIssue{}
+	}
+	return mapping, nil
+}
+
+func saveMapping(file string, mapping *Mapping) error {
+	if file == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(mapping, "", "  ")
+	if err != nil {
+		return errors.Join(errMappingFile, err)
+	}
+	if err = os.WriteFile(file, data, 0644); err != nil {
+		return errors.Join(errMappingFile, err)
+	}
+	return nil
+}
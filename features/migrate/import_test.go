@@ -0,0 +1,272 @@
+package migrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+// targetAPI is a repository with a Bug label and no milestones that numbers
+// new issues from 40. issues are the newest issues it lists. failAt makes
+// that request fail, counting from 1.
+type targetAPI struct {
+	requests   []string
+	payloads   []string
+	milestones string
+	issues     string
+	failAt     int
+	next       int
+}
+
+func (a *targetAPI) client() *stubs.ClientStub {
+	return &stubs.ClientStub{
+		MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+			switch {
+			case strings.Contains(url, "/labels?"):
+				return []byte(`[{"name":"Bug"}]`), nil
+			case strings.Contains(url, "/milestones?"):
+				return []byte("[" + a.milestones + "]"), nil
+			case strings.Contains(url, "/issues?"):
+				return []byte("[" + a.issues + "]"), nil
+			}
+			return nil, errors.New("404 Not Found")
+		},
+		MakeJSONRequestFunc: func(method, url string, payload interface{}) ([]byte, error) {
+			path := strings.TrimPrefix(url, "https://api.example.com/repos/new/repo")
+			a.requests = append(a.requests, method+" "+path)
+			if len(a.requests) == a.failAt {
+				return nil, errors.New("502 Bad Gateway")
+			}
+			data, _ := json.Marshal(payload)
+			a.payloads = append(a.payloads, string(data))
+			switch path {
+			case "/milestones":
+				var milestone domain.Milestone
+				_ = json.Unmarshal(data, &milestone)
+				a.milestones = fmt.Sprintf(`{"number":7,"title":%q}`, milestone.Title)
+				return []byte(a.milestones), nil
+			case "/issues":
+				a.next++
+				return []byte(fmt.Sprintf(`{"number":%d}`, 39+a.next)), nil
+			}
+			return []byte(`{}`), nil
+		},
+	}
+}
+
+func newImport(api *targetAPI, out *bytes.Buffer) *Feature {
+	f := New(&domain.Config{APIBaseURL: "https://api.example.com", Owner: "new", Repo: "repo"}, api.client())
+	f.out = out
+	f.sleep = func(time.Duration) {}
+	return f
+}
+
+func TestImport(t *testing.T) {
+	api := &targetAPI{}
+	var out bytes.Buffer
+	mappingFile := filepath.Join(t.TempDir(), "mapping.json")
+	options := ImportOptions{MilestoneMap: map[string]string{"v1": "Version 1"}, MappingFile: mappingFile}
+
+	got, err := newImport(api, &out).Import(testArchive(), options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := &ImportResult{Created: 2, Comments: 2, MilestonesCreated: 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+	wantRequests := []string{
+		"POST /milestones",
+		"POST /issues",
+		"POST /issues/40/comments",
+		"POST /issues/40/comments",
+		"PATCH /issues/40",
+		"POST /issues",
+	}
+	if !reflect.DeepEqual(api.requests, wantRequests) {
+		t.Errorf("got requests %v want %v", api.requests, wantRequests)
+	}
+	wantPayloads := []string{
+		`{"title":"Version 1","state":"open"}`,
+		`{"title":"Crash","body":"_Originally old/repo#1 by @ann on 2026-01-01._\n\nb1","labels":["Bug"],"milestone":7}`,
+		`{"body":"_Originally by @bob on 2026-01-01._\n\nSeen too"}`,
+		`{"body":"_Originally by @ann on 2026-01-03._\n\nFixed"}`,
+		`{"state":"closed","state_reason":"completed"}`,
+		`{"title":"Add export","body":"_Originally old/repo#2 by @bob on 2026-01-02._\n\nb2"}`,
+	}
+	if !reflect.DeepEqual(api.payloads, wantPayloads) {
+		t.Errorf("got payloads\n%v\nwant\n%v", strings.Join(api.payloads, "\n"), strings.Join(wantPayloads, "\n"))
+	}
+
+	// a second run finds everything in the mapping file
+	api.requests = nil
+	out.Reset()
+	got, err = newImport(api, &out).Import(testArchive(), options)
+	if err != nil {
+		t.Fatalf("unexpected error on rerun: %v", err)
+	}
+	if !reflect.DeepEqual(got, &ImportResult{Skipped: 2}) || api.requests != nil {
+		t.Errorf("rerun: got %+v and requests %v", got, api.requests)
+	}
+	if !strings.Contains(out.String(), "skipped #1, imported as #40\n") {
+		t.Errorf("rerun output: %q", out.String())
+	}
+}
+
+func TestImportResumes(t *testing.T) {
+	// the second comment of #1 fails
+	api := &targetAPI{failAt: 4}
+	var out bytes.Buffer
+	mappingFile := filepath.Join(t.TempDir(), "mapping.json")
+	options := ImportOptions{MappingFile: mappingFile}
+
+	if _, err := newImport(api, &out).Import(testArchive(), options); !errors.Is(err, errImport) {
+		t.Fatalf("got %v want errImport", err)
+	}
+
+	api.failAt, api.requests = 0, nil
+	got, err := newImport(api, &out).Import(testArchive(), options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, &ImportResult{Created: 1, Comments: 1}) {
+		t.Errorf("got %+v", got)
+	}
+	wantRequests := []string{"POST /issues/40/comments", "PATCH /issues/40", "POST /issues"}
+	if !reflect.DeepEqual(api.requests, wantRequests) {
+		t.Errorf("got requests %v want %v", api.requests, wantRequests)
+	}
+
+	data, err := os.ReadFile(mappingFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mapping Mapping
+	if err = json.Unmarshal(data, &mapping); err != nil {
+		t.Fatal(err)
+	}
+	wantMapping := Mapping{Source: "old/repo", Target: "new/repo", Issues: map[string]*ImportedIssue{
+		"1": {Number: 40, Comments: 2, Closed: true},
+		"2": {Number: 41},
+	}}
+	if !reflect.DeepEqual(mapping, wantMapping) {
+		t.Errorf("got mapping %+v", mapping)
+	}
+}
+
+func TestImportPendingIssue(t *testing.T) {
+	// creating #1 fails after the milestone
+	api := &targetAPI{failAt: 2}
+	var out bytes.Buffer
+	mappingFile := filepath.Join(t.TempDir(), "mapping.json")
+	options := ImportOptions{MappingFile: mappingFile}
+
+	if _, err := newImport(api, &out).Import(testArchive(), options); !errors.Is(err, errImport) {
+		t.Fatalf("got %v want errImport", err)
+	}
+	data, err := os.ReadFile(mappingFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"pending": true`) {
+		t.Fatalf("no pending entry in %s", data)
+	}
+
+	// as if the issue was created before the run stopped
+	api.failAt, api.requests = 0, nil
+	api.issues = `{"number":45,"body":"_Originally old/repo#2 by @bob on 2026-01-02._\n\nb2"},` +
+		`{"number":44,"body":"_Originally old/repo#1 by @ann on 2026-01-01._\n\nb1"}`
+	out.Reset()
+	got, err := newImport(api, &out).Import(testArchive(), options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, &ImportResult{Created: 2, Comments: 2}) {
+		t.Errorf("got %+v", got)
+	}
+	wantRequests := []string{"POST /issues/44/comments", "POST /issues/44/comments", "PATCH /issues/44", "POST /issues"}
+	if !reflect.DeepEqual(api.requests, wantRequests) {
+		t.Errorf("got requests %v want %v", api.requests, wantRequests)
+	}
+	if !strings.HasPrefix(out.String(), "found #44 created from #1 Crash\n") {
+		t.Errorf("got output %q", out.String())
+	}
+
+	// a pending issue that was not created is created
+	if err = os.WriteFile(mappingFile, []byte(`{"source":"old/repo","target":"new/repo","issues":{"2":{"number":0,"pending":true}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	api.requests, api.issues = nil, ""
+	if _, err = newImport(api, &out).Import(testArchive(), options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantRequests = []string{"POST /issues", "POST /issues/41/comments", "POST /issues/41/comments", "PATCH /issues/41", "POST /issues"}
+	if !reflect.DeepEqual(api.requests, wantRequests) {
+		t.Errorf("got requests %v want %v", api.requests, wantRequests)
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	api := &targetAPI{}
+	var out bytes.Buffer
+	options := ImportOptions{LabelMap: map[string]string{"bug": "defect"}, MappingFile: filepath.Join(t.TempDir(), "mapping.json"), DryRun: true}
+
+	got, err := newImport(api, &out).Import(testArchive(), options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, &ImportResult{Created: 2, Comments: 2, LabelsCreated: 1, MilestonesCreated: 1}) {
+		t.Errorf("got %+v", got)
+	}
+	if api.requests != nil {
+		t.Errorf("dry run sent %v", api.requests)
+	}
+	if _, err = os.Stat(options.MappingFile); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("dry run wrote the mapping file: %v", err)
+	}
+	want := "would create label defect\nwould create milestone v1\n" +
+		"would create #1 Crash (2 comments, closed)\nwould create #2 Add export (0 comments, open)\n"
+	if out.String() != want {
+		t.Errorf("got %q want %q", out.String(), want)
+	}
+}
+
+func TestImportMappingOfOtherRepository(t *testing.T) {
+	mappingFile := filepath.Join(t.TempDir(), "mapping.json")
+	if err := os.WriteFile(mappingFile, []byte(`{"source":"old/repo","target":"other/repo","issues":{}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := newImport(&targetAPI{}, &bytes.Buffer{}).Import(testArchive(), ImportOptions{MappingFile: mappingFile})
+	if !errors.Is(err, errMappingFile) {
+		t.Errorf("got %v want errMappingFile", err)
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	got, err := ParseMapping([]string{"bug=defect", " enhancement = feature "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, map[string]string{"bug": "defect", "enhancement": "feature"}) {
+		t.Errorf("got %v", got)
+	}
+
+	for _, pair := range []string{"bug", "=defect", "bug="} {
+		if _, err = ParseMapping([]string{pair}); !errors.Is(err, errMapping) {
+			t.Errorf("%q: got %v want errMapping", pair, err)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"git-issues/features/issue"
+)
+
+// WriteMarkdown writes each issue of the archive to dir as <number>-<slug>.md,
+// with the fields in YAML front matter, the body and then the comments.
+// It returns the files written.
+func WriteMarkdown(dir string, archive *Archive) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Join(errExport, err)
+	}
+
+	var files []string
+	for _, i := range archive.Issues {
+		name := strconv.Itoa(i.Number)
+		if slug := issue.Slug(i.Title); slug != "" {
+			name += "-" + slug
+		}
+		path := filepath.Join(dir, name+".md")
+		if err := os.WriteFile(path, []byte(issueMarkdown(archive.Repository, &i)), 0644); err != nil {
+			return files, errors.Join(errExport, err)
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+func issueMarkdown(repository string, i *Issue) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	frontMatter(&b, "repository", strconv.Quote(repository))
+	frontMatter(&b, "number", strconv.Itoa(i.Number))
+	frontMatter(&b, "title", strconv.Quote(i.Title))
+	frontMatter(&b, "state", i.State)
+	if i.StateReason != "" {
+		frontMatter(&b, "state_reason", i.StateReason)
+	}
+	frontMatter(&b, "author", strconv.Quote(i.Author))
+	frontMatter(&b, "created_at", i.CreatedAt)
+	if i.ClosedAt != "" {
+		frontMatter(&b, "closed_at", i.ClosedAt)
+	}
+	frontMatter(&b, "labels", quoteList(i.Labels))
+	frontMatter(&b, "assignees", quoteList(i.Assignees))
+	if i.Milestone != "" {
+		frontMatter(&b, "milestone", strconv.Quote(i.Milestone))
+	}
+	b.WriteString("---\n\n")
+
+	b.WriteString(strings.TrimSpace(i.Body))
+	b.WriteString("\n")
+	if len(i.Comments) > 0 {
+		b.WriteString("\n## Comments\n")
+	}
+	for _, c := range i.Comments {
+		fmt.Fprintf(&b, "\n### @%s on %s\n\n%s\n", c.Author, c.CreatedAt, strings.TrimSpace(c.Body))
+	}
+	return b.String()
+}
+
+func frontMatter(b *strings.Builder, key, value string) {
+	fmt.Fprintf(b, "%s: %s\n", key, value)
+}
+
+// quoteList writes a YAML flow sequence of double quoted strings.
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// ReadMarkdown reads the files WriteMarkdown wrote to dir back into an
+// archive, ordered by number. The labels and milestones keep only their
+// names, and bodies lose their surrounding blank lines.
+func ReadMarkdown(dir string) (*Archive, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return nil, errors.Join(errArchive, err)
+	}
+	archive := &Archive{Version: ArchiveVersion, Issues: []Issue{}}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Join(errArchive, err)
+		}
+		repository, i, err := parseMarkdown(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if archive.Repository == "" {
+			archive.Repository = repository
+		} else if !strings.EqualFold(archive.Repository, repository) {
+			return nil, fmt.Errorf("%w: %s is from %s, not %s", errArchive, path, repository, archive.Repository)
+		}
+		archive.Issues = append(archive.Issues, *i)
+	}
+	if len(archive.Issues) == 0 {
+		return nil, fmt.Errorf("%w: no issues in %s", errArchive, dir)
+	}
+	sort.Slice(archive.Issues, func(a, b int) bool {
+		return archive.Issues[a].Number < archive.Issues[b].Number
+	})
+	return archive, nil
+}
+
+// parseMarkdown reads a file of issueMarkdown and returns the repository
+// of its front matter and the issue.
+func parseMarkdown(text string) (string, *Issue, error) {
+	rest, ok := strings.CutPrefix(text, "---\n")
+	if !ok {
+		return "", nil, fmt.Errorf("%w: no front matter", errArchive)
+	}
+	front, rest, ok := strings.Cut(rest, "\n---\n")
+	if !ok {
+		return "", nil, fmt.Errorf("%w: front matter not closed", errArchive)
+	}
+
+	var repository string
+	i := &Issue{Labels: []string{}, Assignees: []string{}, Comments: []Comment{}}
+	for _, line := range strings.Split(front, "\n") {
+		key, value, _ := strings.Cut(line, ": ")
+		var err error
+		switch key {
+		case "repository":
+			repository, err = strconv.Unquote(value)
+		case "number":
+			i.Number, err = strconv.Atoi(value)
+		case "title":
+			i.Title, err = strconv.Unquote(value)
+		case "state":
+			i.State = value
+		case "state_reason":
+			i.StateReason = value
+		case "author":
+			i.Author, err = strconv.Unquote(value)
+		case "created_at":
+			i.CreatedAt = value
+		case "closed_at":
+			i.ClosedAt = value
+		case "labels":
+			i.Labels, err = unquoteList(value)
+		case "assignees":
+			i.Assignees, err = unquoteList(value)
+		case "milestone":
+			i.Milestone, err = strconv.Unquote(value)
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %s: %v", errArchive, key, err)
+		}
+	}
+	if repository == "" || i.Number == 0 {
+		return "", nil, fmt.Errorf("%w: no repository or number", errArchive)
+	}
+
+	body, comments, _ := strings.Cut(rest, "\n## Comments\n")
+	i.Body = strings.TrimSpace(body)
+	for _, section := range strings.Split(comments, "\n### @")[1:] {
+		header, body, _ := strings.Cut(section, "\n")
+		author, createdAt, ok := strings.Cut(header, " on ")
+		if !ok {
+			return "", nil, fmt.Errorf("%w: comment heading %q", errArchive, header)
+		}
+		i.Comments = append(i.Comments, Comment{Author: author, Body: strings.TrimSpace(body), CreatedAt: createdAt})
+	}
+	return repository, i, nil
+}
+
+// unquoteList reads a flow sequence of quoteList.
+func unquoteList(value string) ([]string, error) {
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return nil, strconv.ErrSyntax
+	}
+	rest := value[1 : len(value)-1]
+	values := []string{}
+	for rest != "" {
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return nil, err
+		}
+		unquoted, _ := strconv.Unquote(quoted)
+		values = append(values, unquoted)
+		rest = strings.TrimPrefix(rest[len(quoted):], ", ")
+	}
+	return values, nil
+}
@@ -0,0 +1,111 @@
+package migrate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "issues")
+
+	files, err := WriteMarkdown(dir, testArchive())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantFiles := []string{filepath.Join(dir, "1-crash.md"), filepath.Join(dir, "2-add-export.md")}
+	if !reflect.DeepEqual(files, wantFiles) {
+		t.Fatalf("got files %v want %v", files, wantFiles)
+	}
+
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `---
+repository: "old/repo"
+number: 1
+title: "Crash"
+state: closed
+state_reason: completed
+author: "ann"
+created_at: 2026-01-01T10:00:00Z
+closed_at: 2026-01-03T10:00:00Z
+labels: ["bug"]
+assignees: []
+milestone: "v1"
+---
+
+b1
+
+## Comments
+
+### @bob on 2026-01-01T11:00:00Z
+
+Seen too
+
+### @ann on 2026-01-03T09:00:00Z
+
+Fixed
+`
+	if string(data) != want {
+		t.Errorf("got\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	// written in reverse to check the order by number
+	archive := testArchive()
+	archive.Issues[0], archive.Issues[1] = archive.Issues[1], archive.Issues[0]
+	if _, err := WriteMarkdown(dir, archive); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "1-crash.md.mapping.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadMarkdown(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := testArchive()
+	want.ExportedAt, want.Labels, want.Milestones = "", nil, nil
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestReadMarkdownErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{name: "empty directory"},
+		{name: "no front matter", files: map[string]string{"1.md": "# Crash\n"}},
+		{name: "front matter not closed", files: map[string]string{"1.md": "---\nnumber: 1\n"}},
+		{name: "bad number", files: map[string]string{"1.md": "---\nrepository: \"old/repo\"\nnumber: one\n---\n"}},
+		{name: "bad labels", files: map[string]string{"1.md": "---\nrepository: \"old/repo\"\nnumber: 1\nlabels: [bug]\n---\n"}},
+		{name: "bad comment", files: map[string]string{"1.md": "---\nrepository: \"old/repo\"\nnumber: 1\n---\n\nb1\n\n## Comments\n\n### @bob\n\nSeen\n"}},
+		{name: "two repositories", files: map[string]string{
+			"1.md": "---\nrepository: \"old/repo\"\nnumber: 1\n---\n",
+			"2.md": "---\nrepository: \"other/repo\"\nnumber: 2\n---\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := ReadMarkdown(dir); !errors.Is(err, errArchive) {
+				t.Errorf("got %v want errArchive", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package project
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+)
+
+const (
+	dateLayout       = "2006-01-02"
+	currentIteration = "@current"
+)
+
+type itemsPage struct {
+	Node struct {
+		Items struct {
+			Nodes    []itemNode      `json:"nodes"`
+			PageInfo client.PageInfo `json:"pageInfo"`
+		} `json:"items"`
+	} `json:"node"`
+}
+
+type itemNode struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Content struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		State  string `json:"state"`
+	} `json:"content"`
+	FieldValues struct {
+		Nodes []struct {
+			Name   *string  `json:"name"`
+			Title  *string  `json:"title"`
+			Number *float64 `json:"number"`
+			Date   *string  `json:"date"`
+			Text   *string  `json:"text"`
+			Field  struct {
+				Name string `json:"name"`
+			} `json:"field"`
+		} `json:"nodes"`
+	} `json:"fieldValues"`
+}
+
+func (n itemNode) toDomain() domain.ProjectItem {
+	item := domain.ProjectItem{
+		ID:     n.ID,
+		Type:   n.Type,
+		Number: n.Content.Number,
+		Title:  n.Content.Title,
+		State:  strings.ToLower(n.Content.State),
+		Fields: map[string]string{},
+	}
+
+	for _, v := range n.FieldValues.Nodes {
+		// the title is a text field of every item, it is shown already
+		if v.Field.Name == "" || v.Field.Name == "Title" {
+			continue
+		}
+		switch {
+		case v.Name != nil:
+			item.Fields[v.Field.Name] = *v.Name
+		case v.Title != nil:
+			item.Fields[v.Field.Name] = *v.Title
+		case v.Number != nil:
+			item.Fields[v.Field.Name] = strconv.FormatFloat(*v.Number, 'f', -1, 64)
+		case v.Date != nil:
+			item.Fields[v.Field.Name] = *v.Date
+		case v.Text != nil:
+			item.Fields[v.Field.Name] = *v.Text
+		}
+	}
+	return item
+}
+
+func matches(item domain.ProjectItem, filters []Filter) bool {
+	for _, filter := range filters {
+		var value string
+		for name, v := range item.Fields {
+			if strings.EqualFold(name, filter.Field) {
+				value = v
+			}
+		}
+		if !strings.EqualFold(value, filter.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func findField(fields []domain.ProjectField, name string) (*domain.ProjectField, error) {
+	names := make([]string, 0, len(fields))
+	for i := range fields {
+		if strings.EqualFold(fields[i].Name, name) {
+			return &fields[i], nil
+		}
+		names = append(names, fields[i].Name)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("%w: %q (available: %s)", errFieldNotFound, name, strings.Join(names, ", "))
+}
+
+// fieldValue converts the text given on the command line to the
+// ProjectV2FieldValue input of the field's data type.
+func fieldValue(field *domain.ProjectField, value string, now time.Time) (map[string]interface{}, error) {
+	switch field.DataType {
+	case domain.FieldSingleSelect:
+		names := make([]string, 0, len(field.Options))
+		for _, option := range field.Options {
+			if strings.EqualFold(option.Name, value) {
+				return map[string]interface{}{"singleSelectOptionId": option.ID}, nil
+			}
+			names = append(names, option.Name)
+		}
+		return nil, fmt.Errorf("%w: %q is not an option of %s (%s)", errFieldValue, value, field.Name, strings.Join(names, ", "))
+
+	case domain.FieldIteration:
+		for _, iteration := range field.Iterations {
+			if strings.EqualFold(iteration.Title, value) || (value == currentIteration && isCurrent(iteration, now)) {
+				return map[string]interface{}{"iterationId": iteration.ID}, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: no iteration %q in %s", errFieldValue, value, field.Name)
+
+	case domain.FieldNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s expects a number", errFieldValue, field.Name)
+		}
+		return map[string]interface{}{"number": number}, nil
+
+	case domain.FieldDate:
+		if _, err := time.Parse(dateLayout, value); err != nil {
+			return nil, fmt.Errorf("%w: %s expects a date like 2024-01-31", errFieldValue, field.Name)
+		}
+		return map[string]interface{}{"date": value}, nil
+
+	case domain.FieldText:
+		return map[string]interface{}{"text": value}, nil
+	}
+	return nil, fmt.Errorf("%w: fields of type %s cannot be set", errFieldValue, field.DataType)
+}
+
+func isCurrent(iteration domain.ProjectIteration, now time.Time) bool {
+	start, err := time.Parse(dateLayout, iteration.StartDate)
+	if err != nil {
+		return false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return !today.Before(start) && today.Before(start.AddDate(0, 0, iteration.Duration))
+}
@@ -0,0 +1,69 @@
+package project
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"git-issues/domain"
+)
+
+const (
+	strProjectFormat = "#%d %s%s\n"
+	strItemFormat    = "#%d - %s (%s)%s\n"
+	strDraftFormat   = "draft - %s%s\n"
+)
+
+func PrintProjects(w io.Writer, projects []domain.Project) error {
+	_, err := fmt.Fprintln(w, "\nProjects:")
+	if err != nil {
+		return err
+	}
+
+	for _, p := range projects {
+		closed := ""
+		if p.Closed {
+			closed = " (closed)"
+		}
+		_, err = fmt.Fprintf(w, strProjectFormat, p.Number, p.Title, closed)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func PrintItems(w io.Writer, items []domain.ProjectItem) error {
+	_, err := fmt.Fprintln(w, "\nItems:")
+	if err != nil {
+		return err
+	}
+
+	for _, i := range items {
+		if i.Number == 0 {
+			_, err = fmt.Fprintf(w, strDraftFormat, i.Title, formatFields(i.Fields))
+		} else {
+			_, err = fmt.Fprintf(w, strItemFormat, i.Number, i.Title, i.State, formatFields(i.Fields))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatFields lists the field values sorted by field name.
+func formatFields(fields map[string]string) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %s: %s", name, fields[name])
+	}
+	return b.String()
+}
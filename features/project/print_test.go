@@ -0,0 +1,39 @@
+package project
+
+import (
+	"bytes"
+	"testing"
+
+	"git-issues/domain"
+)
+
+func TestPrintProjects(t *testing.T) {
+	var buf bytes.Buffer
+	projects := []domain.Project{{Number: 1, Title: "Roadmap"}, {Number: 2, Title: "Old", Closed: true}}
+
+	if err := PrintProjects(&buf, projects); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "\nProjects:\n#1 Roadmap\n#2 Old (closed)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output mismatch:\ngot:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestPrintItems(t *testing.T) {
+	var buf bytes.Buffer
+	items := []domain.ProjectItem{
+		{Number: 1, Title: "first", State: "open", Fields: map[string]string{"Status": "Todo", "Points": "3"}},
+		{Type: "DRAFT_ISSUE", Title: "draft"},
+	}
+
+	if err := PrintItems(&buf, items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "\nItems:\n#1 - first (open)  Points: 3  Status: Todo\ndraft - draft\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output mismatch:\ngot:\n%q\nwant:\n%q", got, want)
+	}
+}
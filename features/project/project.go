@@ -0,0 +1,322 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+)
+
+var (
+	errProjectRequired  = errors.New("project is required")
+	errProjectNotFound  = errors.New("project not found")
+	errIssueNotFound    = errors.New("issue not found")
+	errNotInProject     = errors.New("issue is not in any project, use --project")
+	errAmbiguousProject = errors.New("issue is in more than one project, use --project")
+	errFieldNotFound    = errors.New("field not found")
+	errFieldValue       = errors.New("invalid field value")
+	errFieldFormat      = errors.New("expected Field=Value")
+	errAdd              = errors.New("could not add issue to project")
+	errSet              = errors.New("could not set project field")
+)
+
+type Projects interface {
+	List() ([]domain.Project, error)
+	Add(number int, project string) error
+	Set(number int, project, field, value string) error
+	Items(project string, filters []Filter) ([]domain.ProjectItem, error)
+}
+
+// Filter selects project items whose field has the given value. An empty
+// value selects the items where the field is not set.
+type Filter struct {
+	Field string
+	Value string
+}
+
+type Feature struct {
+	config  *domain.Config
+	graphql client.GraphQLClient
+	now     func() time.Time
+}
+
+func New(config *domain.Config, graphql client.GraphQLClient) *Feature {
+	return &Feature{
+		config:  config,
+		graphql: graphql,
+		now:     time.Now,
+	}
+}
+
+// ParseFieldValue splits "Field=Value" as used by --field and --filter.
+func ParseFieldValue(s string) (string, string, error) {
+	field, value, ok := strings.Cut(s, "=")
+	field = strings.TrimSpace(field)
+	if !ok || field == "" {
+		return "", "", fmt.Errorf("%w: %q", errFieldFormat, s)
+	}
+	return field, strings.TrimSpace(value), nil
+}
+
+// List returns the projects of the repository owner.
+func (f *Feature) List() ([]domain.Project, error) {
+	var projects []domain.Project
+	variables := map[string]interface{}{"owner": f.config.Owner}
+
+	err := client.Paginate(f.graphql, projectsQuery, variables, func(data json.RawMessage) (client.PageInfo, error) {
+		var page struct {
+			RepositoryOwner *struct {
+				ProjectsV2 struct {
+					Nodes    []domain.Project `json:"nodes"`
+					PageInfo client.PageInfo  `json:"pageInfo"`
+				} `json:"projectsV2"`
+			} `json:"repositoryOwner"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return client.PageInfo{}, err
+		}
+		if page.RepositoryOwner == nil {
+			return client.PageInfo{}, nil
+		}
+		projects = append(projects, page.RepositoryOwner.ProjectsV2.Nodes...)
+		return page.RepositoryOwner.ProjectsV2.PageInfo, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// Find returns the project with the given title or number.
+func (f *Feature) Find(name string) (*domain.Project, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errProjectRequired
+	}
+
+	projects, err := f.List()
+	if err != nil {
+		return nil, err
+	}
+
+	number, numErr := strconv.Atoi(strings.TrimPrefix(name, "#"))
+	for i := range projects {
+		if strings.EqualFold(projects[i].Title, name) || (numErr == nil && projects[i].Number == number) {
+			return &projects[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", errProjectNotFound, name)
+}
+
+// Fields returns the custom fields of a project with their options and
+// iterations.
+func (f *Feature) Fields(projectID string) ([]domain.ProjectField, error) {
+	var result struct {
+		Node struct {
+			Fields struct {
+				Nodes []struct {
+					domain.ProjectField
+					Configuration *struct {
+						Iterations          []domain.ProjectIteration `json:"iterations"`
+						CompletedIterations []domain.ProjectIteration `json:"completedIterations"`
+					} `json:"configuration"`
+				} `json:"nodes"`
+			} `json:"fields"`
+		} `json:"node"`
+	}
+	if err := f.graphql.Query(fieldsQuery, map[string]interface{}{"id": projectID}, &result); err != nil {
+		return nil, err
+	}
+
+	var fields []domain.ProjectField
+	for _, node := range result.Node.Fields.Nodes {
+		field := node.ProjectField
+		if field.ID == "" {
+			continue
+		}
+		if node.Configuration != nil {
+			field.Iterations = append(node.Configuration.Iterations, node.Configuration.CompletedIterations...)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func (f *Feature) Add(number int, project string) error {
+	if number == 0 {
+		return errIssueNotFound
+	}
+
+	p, err := f.Find(project)
+	if err != nil {
+		return errors.Join(errAdd, err)
+	}
+
+	issue, err := f.issue(number)
+	if err != nil {
+		return errors.Join(errAdd, err)
+	}
+
+	if _, err = f.addItem(p.ID, issue.ID); err != nil {
+		return errors.Join(errAdd, err)
+	}
+	return nil
+}
+
+// Set changes a field of the issue's item. Without a project name the issue
+// must be on exactly one board; with one it is added to the board if needed.
+// An empty value clears the field.
+func (f *Feature) Set(number int, project, field, value string) error {
+	if number == 0 {
+		return errIssueNotFound
+	}
+
+	issue, err := f.issue(number)
+	if err != nil {
+		return errors.Join(errSet, err)
+	}
+
+	projectID, itemID, err := f.item(issue, project)
+	if err != nil {
+		return errors.Join(errSet, err)
+	}
+
+	fields, err := f.Fields(projectID)
+	if err != nil {
+		return errors.Join(errSet, err)
+	}
+	target, err := findField(fields, field)
+	if err != nil {
+		return errors.Join(errSet, err)
+	}
+
+	variables := map[string]interface{}{"project": projectID, "item": itemID, "field": target.ID}
+	if value == "" {
+		err = f.graphql.Query(clearFieldMutation, variables, nil)
+		if err != nil {
+			return errors.Join(errSet, err)
+		}
+		return nil
+	}
+
+	variables["value"], err = fieldValue(target, value, f.now())
+	if err != nil {
+		return errors.Join(errSet, err)
+	}
+	if err = f.graphql.Query(setFieldMutation, variables, nil); err != nil {
+		return errors.Join(errSet, err)
+	}
+	return nil
+}
+
+// Items returns the items of the project that match every filter.
+func (f *Feature) Items(project string, filters []Filter) ([]domain.ProjectItem, error) {
+	p, err := f.Find(project)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []domain.ProjectItem
+	err = client.Paginate(f.graphql, itemsQuery, map[string]interface{}{"id": p.ID}, func(data json.RawMessage) (client.PageInfo, error) {
+		var page itemsPage
+		if err := json.Unmarshal(data, &page); err != nil {
+			return client.PageInfo{}, err
+		}
+		for _, node := range page.Node.Items.Nodes {
+			item := node.toDomain()
+			if matches(item, filters) {
+				items = append(items, item)
+			}
+		}
+		return page.Node.Items.PageInfo, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+type issueItems struct {
+	ID    string
+	Items map[string]string // project id -> item id
+}
+
+func (f *Feature) issue(number int) (*issueItems, error) {
+	var result struct {
+		Repository struct {
+			Issue *struct {
+				ID           string `json:"id"`
+				ProjectItems struct {
+					Nodes []struct {
+						ID      string `json:"id"`
+						Project struct {
+							ID string `json:"id"`
+						} `json:"project"`
+					} `json:"nodes"`
+				} `json:"projectItems"`
+			} `json:"issue"`
+		} `json:"repository"`
+	}
+	variables := map[string]interface{}{"owner": f.config.Owner, "repo": f.config.Repo, "number": number}
+	if err := f.graphql.Query(issueItemsQuery, variables, &result); err != nil {
+		return nil, err
+	}
+	if result.Repository.Issue == nil {
+		return nil, fmt.Errorf("%w: #%d", errIssueNotFound, number)
+	}
+
+	issue := &issueItems{ID: result.Repository.Issue.ID, Items: map[string]string{}}
+	for _, node := range result.Repository.Issue.ProjectItems.Nodes {
+		issue.Items[node.Project.ID] = node.ID
+	}
+	return issue, nil
+}
+
+// item returns the project and item ids of the issue on the requested board.
+func (f *Feature) item(issue *issueItems, project string) (string, string, error) {
+	if project == "" {
+		switch len(issue.Items) {
+		case 0:
+			return "", "", errNotInProject
+		case 1:
+			for projectID, itemID := range issue.Items {
+				return projectID, itemID, nil
+			}
+		}
+		return "", "", errAmbiguousProject
+	}
+
+	p, err := f.Find(project)
+	if err != nil {
+		return "", "", err
+	}
+	if itemID, ok := issue.Items[p.ID]; ok {
+		return p.ID, itemID, nil
+	}
+
+	itemID, err := f.addItem(p.ID, issue.ID)
+	if err != nil {
+		return "", "", err
+	}
+	return p.ID, itemID, nil
+}
+
+func (f *Feature) addItem(projectID, contentID string) (string, error) {
+	var result struct {
+		AddProjectV2ItemById struct {
+			Item struct {
+				ID string `json:"id"`
+			} `json:"item"`
+		} `json:"addProjectV2ItemById"`
+	}
+	variables := map[string]interface{}{"project": projectID, "content": contentID}
+	if err := f.graphql.Query(addItemMutation, variables, &result); err != nil {
+		return "", err
+	}
+	return result.AddProjectV2ItemById.Item.ID, nil
+}
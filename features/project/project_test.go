@@ -0,0 +1,331 @@
+package project
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+const (
+	projectsPage1 = `{"repositoryOwner":{"projectsV2":{"nodes":[{"id":"P_1","number":1,"title":"Roadmap"}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}`
+	projectsPage2 = `{"repositoryOwner":{"projectsV2":{"nodes":[{"id":"P_2","number":2,"title":"Sprint Board"}],"pageInfo":{"hasNextPage":false}}}}`
+	fieldsData    = `{"node":{"fields":{"nodes":[
+		{"id":"F_title","name":"Title","dataType":"TITLE"},
+		{"id":"F_status","name":"Status","dataType":"SINGLE_SELECT","options":[{"id":"O_todo","name":"Todo"},{"id":"O_progress","name":"In Progress"}]},
+		{"id":"F_sprint","name":"Sprint","dataType":"ITERATION","configuration":{
+			"iterations":[{"id":"IT_2","title":"Sprint 2","startDate":"2024-03-15","duration":14}],
+			"completedIterations":[{"id":"IT_1","title":"Sprint 1","startDate":"2024-03-01","duration":14}]}},
+		{"id":"F_points","name":"Points","dataType":"NUMBER"},
+		{"id":"F_due","name":"Due","dataType":"DATE"},
+		{"id":"F_notes","name":"Notes","dataType":"TEXT"}
+	]}}}`
+	itemsData = `{"node":{"items":{"nodes":[
+		{"id":"PI_1","type":"ISSUE","content":{"number":1,"title":"first","state":"OPEN"},"fieldValues":{"nodes":[
+			{"text":"first","field":{"name":"Title"}},
+			{"name":"Todo","field":{"name":"Status"}},
+			{"number":3,"field":{"name":"Points"}}]}},
+		{"id":"PI_2","type":"ISSUE","content":{"number":2,"title":"second","state":"CLOSED"},"fieldValues":{"nodes":[
+			{"name":"In Progress","field":{"name":"Status"}},
+			{"title":"Sprint 2","field":{"name":"Sprint"}},
+			{"date":"2024-04-01","field":{"name":"Due"}}]}},
+		{"id":"PI_3","type":"DRAFT_ISSUE","content":{"title":"draft"},"fieldValues":{"nodes":[]}}
+	],"pageInfo":{"hasNextPage":false}}}}`
+)
+
+type mutation struct {
+	query     string
+	variables map[string]interface{}
+}
+
+// fakeGraphQL answers the project queries and records the mutations.
+func fakeGraphQL(issueItems string, mutations *[]mutation) *stubs.GraphQLStub {
+	return &stubs.GraphQLStub{
+		QueryFunc: func(query string, variables map[string]interface{}, result interface{}) error {
+			var data string
+			switch query {
+			case projectsQuery:
+				data = projectsPage1
+				if variables["cursor"] == "c1" {
+					data = projectsPage2
+				}
+			case fieldsQuery:
+				data = fieldsData
+			case itemsQuery:
+				data = itemsData
+			case issueItemsQuery:
+				data = issueItems
+			case addItemMutation:
+				data = `{"addProjectV2ItemById":{"item":{"id":"PI_new"}}}`
+				*mutations = append(*mutations, mutation{query, variables})
+			default:
+				*mutations = append(*mutations, mutation{query, variables})
+				return nil
+			}
+			return stubs.GraphQLResponse(data)(query, variables, result)
+		},
+	}
+}
+
+func newTestFeature(issueItems string, mutations *[]mutation) *Feature {
+	f := New(&domain.Config{Owner: "owner", Repo: "repo"}, fakeGraphQL(issueItems, mutations))
+	f.now = func() time.Time { return time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC) }
+	return f
+}
+
+const (
+	issueInOne  = `{"repository":{"issue":{"id":"I_1","projectItems":{"nodes":[{"id":"PI_1","project":{"id":"P_1"}}]}}}}`
+	issueInNone = `{"repository":{"issue":{"id":"I_1","projectItems":{"nodes":[]}}}}`
+	issueInTwo  = `{"repository":{"issue":{"id":"I_1","projectItems":{"nodes":[{"id":"PI_1","project":{"id":"P_1"}},{"id":"PI_9","project":{"id":"P_2"}}]}}}}`
+	noIssue     = `{"repository":{"issue":null}}`
+)
+
+func TestList(t *testing.T) {
+	got, err := newTestFeature("", nil).List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []domain.Project{{ID: "P_1", Number: 1, Title: "Roadmap"}, {ID: "P_2", Number: 2, Title: "Sprint Board"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected projects: got %+v want %+v", got, want)
+	}
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		wantID  string
+		wantErr error
+	}{
+		{name: "by title", project: "sprint board", wantID: "P_2"},
+		{name: "by number", project: "#1", wantID: "P_1"},
+		{name: "missing", project: "Backlog", wantErr: errProjectNotFound},
+		{name: "empty", project: "", wantErr: errProjectRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTestFeature("", nil).Find(tt.project)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error: got %v want %v", err, tt.wantErr)
+			}
+			if err == nil && got.ID != tt.wantID {
+				t.Errorf("unexpected project: got %s want %s", got.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestAdd(t *testing.T) {
+	var mutations []mutation
+	err := newTestFeature(issueInNone, &mutations).Add(1, "Roadmap")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []mutation{{addItemMutation, map[string]interface{}{"project": "P_1", "content": "I_1"}}}
+	if !reflect.DeepEqual(mutations, want) {
+		t.Errorf("unexpected mutations: got %+v want %+v", mutations, want)
+	}
+
+	err = newTestFeature(noIssue, &mutations).Add(5, "Roadmap")
+	if !errors.Is(err, errIssueNotFound) {
+		t.Errorf("expected errIssueNotFound, got %v", err)
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name          string
+		issueItems    string
+		project       string
+		field         string
+		value         string
+		wantMutations []mutation
+		wantErr       error
+	}{
+		{
+			name:       "single select on the only project",
+			issueItems: issueInOne,
+			field:      "status",
+			value:      "in progress",
+			wantMutations: []mutation{{setFieldMutation, map[string]interface{}{
+				"project": "P_1", "item": "PI_1", "field": "F_status",
+				"value": map[string]interface{}{"singleSelectOptionId": "O_progress"},
+			}}},
+		},
+		{
+			name:       "current iteration",
+			issueItems: issueInOne,
+			field:      "Sprint",
+			value:      "@current",
+			wantMutations: []mutation{{setFieldMutation, map[string]interface{}{
+				"project": "P_1", "item": "PI_1", "field": "F_sprint",
+				"value": map[string]interface{}{"iterationId": "IT_2"},
+			}}},
+		},
+		{
+			name:       "number",
+			issueItems: issueInOne,
+			field:      "Points",
+			value:      "5",
+			wantMutations: []mutation{{setFieldMutation, map[string]interface{}{
+				"project": "P_1", "item": "PI_1", "field": "F_points",
+				"value": map[string]interface{}{"number": float64(5)},
+			}}},
+		},
+		{
+			name:       "date",
+			issueItems: issueInOne,
+			field:      "Due",
+			value:      "2024-05-01",
+			wantMutations: []mutation{{setFieldMutation, map[string]interface{}{
+				"project": "P_1", "item": "PI_1", "field": "F_due",
+				"value": map[string]interface{}{"date": "2024-05-01"},
+			}}},
+		},
+		{
+			name:       "clear",
+			issueItems: issueInOne,
+			field:      "Notes",
+			wantMutations: []mutation{{clearFieldMutation, map[string]interface{}{
+				"project": "P_1", "item": "PI_1", "field": "F_notes",
+			}}},
+		},
+		{
+			name:       "adds the issue to the named project first",
+			issueItems: issueInOne,
+			project:    "Sprint Board",
+			field:      "Status",
+			value:      "Todo",
+			wantMutations: []mutation{
+				{addItemMutation, map[string]interface{}{"project": "P_2", "content": "I_1"}},
+				{setFieldMutation, map[string]interface{}{
+					"project": "P_2", "item": "PI_new", "field": "F_status",
+					"value": map[string]interface{}{"singleSelectOptionId": "O_todo"},
+				}},
+			},
+		},
+		{
+			name:       "not in a project",
+			issueItems: issueInNone,
+			field:      "Status",
+			value:      "Todo",
+			wantErr:    errNotInProject,
+		},
+		{
+			name:       "ambiguous project",
+			issueItems: issueInTwo,
+			field:      "Status",
+			value:      "Todo",
+			wantErr:    errAmbiguousProject,
+		},
+		{
+			name:       "unknown field",
+			issueItems: issueInOne,
+			field:      "Priority",
+			value:      "High",
+			wantErr:    errFieldNotFound,
+		},
+		{
+			name:       "unknown option",
+			issueItems: issueInOne,
+			field:      "Status",
+			value:      "Done",
+			wantErr:    errFieldValue,
+		},
+		{
+			name:       "invalid date",
+			issueItems: issueInOne,
+			field:      "Due",
+			value:      "tomorrow",
+			wantErr:    errFieldValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mutations []mutation
+			err := newTestFeature(tt.issueItems, &mutations).Set(1, tt.project, tt.field, tt.value)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error: got %v want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(mutations, tt.wantMutations) {
+				t.Errorf("unexpected mutations:\ngot:  %+v\nwant: %+v", mutations, tt.wantMutations)
+			}
+		})
+	}
+}
+
+func TestItems(t *testing.T) {
+	tests := []struct {
+		name        string
+		filters     []Filter
+		wantNumbers []int
+	}{
+		{name: "no filter", wantNumbers: []int{1, 2, 0}},
+		{name: "single select", filters: []Filter{{Field: "status", Value: "in progress"}}, wantNumbers: []int{2}},
+		{name: "number", filters: []Filter{{Field: "Points", Value: "3"}}, wantNumbers: []int{1}},
+		{name: "unset", filters: []Filter{{Field: "Status", Value: ""}}, wantNumbers: []int{0}},
+		{name: "all filters must match", filters: []Filter{{Field: "Status", Value: "Todo"}, {Field: "Sprint", Value: "Sprint 2"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := newTestFeature("", nil).Items("Roadmap", tt.filters)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var numbers []int
+			for _, i := range items {
+				numbers = append(numbers, i.Number)
+			}
+			if !reflect.DeepEqual(numbers, tt.wantNumbers) {
+				t.Errorf("unexpected items: got %v want %v", numbers, tt.wantNumbers)
+			}
+		})
+	}
+
+	items, _ := newTestFeature("", nil).Items("Roadmap", nil)
+	want := domain.ProjectItem{
+		ID: "PI_2", Type: "ISSUE", Number: 2, Title: "second", State: "closed",
+		Fields: map[string]string{"Status": "In Progress", "Sprint": "Sprint 2", "Due": "2024-04-01"},
+	}
+	if !reflect.DeepEqual(items[1], want) {
+		t.Errorf("unexpected item: got %+v want %+v", items[1], want)
+	}
+}
+
+func TestParseFieldValue(t *testing.T) {
+	tests := []struct {
+		in        string
+		wantField string
+		wantValue string
+		wantErr   error
+	}{
+		{in: "Status=In Progress", wantField: "Status", wantValue: "In Progress"},
+		{in: "Notes=", wantField: "Notes"},
+		{in: "Expr=a=b", wantField: "Expr", wantValue: "a=b"},
+		{in: "Status", wantErr: errFieldFormat},
+		{in: "=x", wantErr: errFieldFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			field, value, err := ParseFieldValue(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error: got %v want %v", err, tt.wantErr)
+			}
+			if field != tt.wantField || value != tt.wantValue {
+				t.Errorf("got %q=%q want %q=%q", field, value, tt.wantField, tt.wantValue)
+			}
+		})
+	}
+}
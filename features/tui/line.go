@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"git-issues/domain"
+	"git-issues/features/issue"
+)
+
+// runLineMode is the fallback for dumb terminals and redirected input: it
+// prints the list and reads one command per line.
+func (f *Feature) runLineMode() error {
+	in := newLineReader(f.reader)
+
+	if err := issue.PrintIssues(f.writer, f.visible()); err != nil {
+		return err
+	}
+
+	for {
+		fmt.Fprint(f.writer, "\ntui> ")
+		line, err := in.ReadString('\n')
+		if err != nil && line == "" {
+			if err == io.EOF {
+				fmt.Fprintln(f.writer)
+				return nil
+			}
+			return err
+		}
+
+		quit, cmdErr := f.execute(strings.TrimSpace(line))
+		if cmdErr != nil {
+			fmt.Fprintf(f.writer, "error: %v\n", cmdErr)
+		}
+		if quit {
+			return nil
+		}
+		if f.status != "" {
+			fmt.Fprintln(f.writer, f.status)
+			f.status = ""
+		}
+	}
+}
+
+func (f *Feature) execute(line string) (bool, error) {
+	if line == "" {
+		return false, nil
+	}
+
+	if strings.HasPrefix(line, "/") {
+		f.filter = strings.TrimSpace(line[1:])
+		return false, issue.PrintIssues(f.writer, f.visible())
+	}
+
+	if number, err := strconv.Atoi(line); err == nil {
+		return false, f.show(number)
+	}
+
+	fields := strings.Fields(line)
+	command, args := fields[0], fields[1:]
+
+	switch command {
+	case "q", "quit", "exit":
+		return true, nil
+	case "help", "?":
+		fmt.Fprintln(f.writer, strLineHelp)
+		return false, nil
+	case "refresh":
+		if err := f.refresh(); err != nil {
+			return false, err
+		}
+		return false, issue.PrintIssues(f.writer, f.visible())
+	}
+
+	if len(args) == 0 {
+		return false, errIssueArgument
+	}
+	number, err := strconv.Atoi(args[0])
+	if err != nil {
+		return false, errIssueArgument
+	}
+	rest := strings.Join(args[1:], " ")
+
+	switch command {
+	case "close":
+		return false, f.closeIssue(number)
+	case "reopen":
+		return false, f.reopenIssue(number)
+	case "label":
+		return false, f.labelIssue(number, rest)
+	case "assign":
+		return false, f.assignIssue(number, rest)
+	case "edit":
+		if err = f.update.Update(number); err != nil {
+			return false, err
+		}
+		return false, f.refresh()
+	case "show", "view":
+		return false, f.show(number)
+	}
+	return false, fmt.Errorf("%w: %s", errUnknownCommand, command)
+}
+
+func (f *Feature) show(number int) error {
+	var i *domain.Issue
+	if i = f.find(number); i == nil {
+		var err error
+		if i, err = f.view.View(number); err != nil {
+			return err
+		}
+	}
+
+	if err := issue.PrintIssue(f.writer, i); err != nil {
+		return err
+	}
+
+	if err := f.loadComments(number); err != nil {
+		return err
+	}
+	for _, c := range f.comments[number] {
+		if _, err := fmt.Fprintf(f.writer, "\n@%s %s\n%s\n", c.User.Login, c.CreatedAt, c.Body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
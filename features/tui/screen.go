@@ -0,0 +1,376 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+type keyCode int
+
+const (
+	keyRune keyCode = iota
+	keyUp
+	keyDown
+	keyHome
+	keyEnd
+	keyPageUp
+	keyPageDown
+	keyEnter
+	keyEsc
+	keyBackspace
+	keyCtrlC
+	keyUnknown
+)
+
+type key struct {
+	code keyCode
+	r    rune
+}
+
+func (f *Feature) runFullScreen() (err error) {
+	in := newLineReader(f.reader)
+
+	fmt.Fprint(f.writer, enterAltScreen)
+	defer func() {
+		fmt.Fprint(f.writer, leaveAltScreen)
+		if restoreErr := f.restore(); err == nil {
+			err = restoreErr
+		}
+	}()
+
+	for {
+		f.render()
+
+		k, err := readKey(in)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if f.filtering {
+			f.handleFilterKey(k)
+			continue
+		}
+
+		quit, err := f.handleKey(k, in)
+		if err != nil {
+			return err
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+func (f *Feature) handleFilterKey(k key) {
+	switch k.code {
+	case keyEnter:
+		f.filtering = false
+	case keyEsc, keyCtrlC:
+		f.filtering = false
+		f.filter = ""
+	case keyBackspace:
+		if f.filter != "" {
+			_, size := utf8.DecodeLastRuneInString(f.filter)
+			f.filter = f.filter[:len(f.filter)-size]
+		}
+	case keyRune:
+		f.filter += string(k.r)
+	}
+	f.cursor = 0
+	f.offset = 0
+}
+
+func (f *Feature) handleKey(k key, in *bufio.Reader) (bool, error) {
+	_, height := f.size()
+	f.status = ""
+
+	switch k.code {
+	case keyCtrlC:
+		return true, nil
+	case keyUp:
+		f.move(-1)
+	case keyDown:
+		f.move(1)
+	case keyHome:
+		f.cursor = 0
+	case keyEnd:
+		f.move(len(f.issues))
+	case keyPageUp:
+		f.move(-listHeight(height))
+	case keyPageDown:
+		f.move(listHeight(height))
+	case keyEnter:
+		if i := f.selected(); i != nil {
+			f.report(f.loadComments(i.Number))
+		}
+	case keyEsc:
+		f.filter = ""
+		f.clampCursor()
+	case keyRune:
+		return f.handleRune(k.r, in)
+	}
+	return false, nil
+}
+
+func (f *Feature) handleRune(r rune, in *bufio.Reader) (bool, error) {
+	_, height := f.size()
+
+	switch r {
+	case 'q':
+		return true, nil
+	case 'j':
+		f.move(1)
+	case 'k':
+		f.move(-1)
+	case 'g':
+		f.cursor = 0
+	case 'G':
+		f.move(len(f.issues))
+	case ' ':
+		f.move(listHeight(height))
+	case '/':
+		f.filtering = true
+	case '?':
+		f.status = strKeysHelp
+	case 'r':
+		f.report(f.refresh())
+	}
+
+	i := f.selected()
+	if i == nil {
+		return false, nil
+	}
+	number := i.Number
+
+	switch r {
+	case 'c':
+		f.report(f.closeIssue(number))
+	case 'o':
+		f.report(f.reopenIssue(number))
+	case 'l':
+		if input, ok := f.prompt(in, "labels"); ok {
+			f.report(f.labelIssue(number, input))
+		}
+	case 'a':
+		if input, ok := f.prompt(in, "assignees"); ok {
+			f.report(f.assignIssue(number, input))
+		}
+	case 'e':
+		return false, f.edit(number)
+	}
+	return false, nil
+}
+
+// edit hands the terminal over to the editor and takes it back afterwards.
+func (f *Feature) edit(number int) error {
+	fmt.Fprint(f.writer, leaveAltScreen)
+	if err := f.restore(); err != nil {
+		return err
+	}
+
+	updateErr := f.update.Update(number)
+
+	restore, err := f.makeRaw()
+	if err != nil {
+		f.restore = func() error { return nil }
+		return err
+	}
+	f.restore = restore
+	fmt.Fprint(f.writer, enterAltScreen)
+
+	if updateErr != nil {
+		f.report(updateErr)
+		return nil
+	}
+	f.report(f.refresh())
+	if f.status == "" {
+		f.status = fmt.Sprintf("issue #%d updated", number)
+	}
+	return nil
+}
+
+// prompt reads a line of text on the status bar. It returns false when the
+// user cancels with escape.
+func (f *Feature) prompt(in *bufio.Reader, label string) (string, bool) {
+	var input []rune
+	for {
+		f.status = fmt.Sprintf("%s: %s", label, string(input))
+		f.render()
+
+		k, err := readKey(in)
+		if err != nil {
+			f.status = ""
+			return "", false
+		}
+
+		switch k.code {
+		case keyEnter:
+			f.status = ""
+			return string(input), true
+		case keyEsc, keyCtrlC:
+			f.status = ""
+			return "", false
+		case keyBackspace:
+			if len(input) > 0 {
+				input = input[:len(input)-1]
+			}
+		case keyRune:
+			input = append(input, k.r)
+		}
+	}
+}
+
+func (f *Feature) report(err error) {
+	if err != nil {
+		f.status = "error: " + firstLine(err.Error())
+	}
+}
+
+func (f *Feature) render() {
+	width, height := f.size()
+	rows := listHeight(height)
+	visible := f.visible()
+
+	if f.cursor < f.offset {
+		f.offset = f.cursor
+	}
+	if f.cursor >= f.offset+rows {
+		f.offset = f.cursor - rows + 1
+	}
+
+	var lines []string
+
+	header := fmt.Sprintf("ghissues - %d of %d issues", len(visible), len(f.issues))
+	if f.filtering || f.filter != "" {
+		header += "  filter: /" + f.filter
+	}
+	lines = append(lines, truncate(header, width))
+
+	for row := 0; row < rows; row++ {
+		idx := f.offset + row
+		if idx >= len(visible) {
+			lines = append(lines, "")
+			continue
+		}
+		i := visible[idx]
+		line := truncate(fmt.Sprintf("  #%d %s (%s)", i.Number, i.Title, i.State), width)
+		if idx == f.cursor {
+			line = reverseVideo + ">" + line[1:] + resetStyle
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, strings.Repeat("-", width))
+
+	previewRows := max(height-len(lines)-1, 0)
+	preview := f.preview(width)
+	if len(preview) > previewRows {
+		preview = preview[:previewRows]
+	}
+	lines = append(lines, preview...)
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+
+	status := f.status
+	if status == "" {
+		status = "? help  q quit"
+	}
+	lines = append(lines, truncate(status, width))
+
+	fmt.Fprint(f.writer, clearScreen+strings.Join(lines, "\r\n"))
+}
+
+func (f *Feature) preview(width int) []string {
+	i := f.selected()
+	if i == nil {
+		return []string{"no issues"}
+	}
+
+	var lines []string
+	lines = append(lines, truncate(fmt.Sprintf("#%d %s", i.Number, i.Title), width))
+	lines = append(lines, truncate("State: "+i.State+labelsAndAssignees(i), width))
+	lines = append(lines, "")
+	lines = append(lines, renderBody(i.Body, width)...)
+
+	comments, ok := f.comments[i.Number]
+	if !ok {
+		lines = append(lines, "", truncate("(enter to load comments)", width))
+		return lines
+	}
+	for _, c := range comments {
+		lines = append(lines, "", truncate(fmt.Sprintf("@%s %s", c.User.Login, c.CreatedAt), width))
+		lines = append(lines, renderBody(c.Body, width)...)
+	}
+	return lines
+}
+
+func readKey(in *bufio.Reader) (key, error) {
+	r, _, err := in.ReadRune()
+	if err != nil {
+		return key{}, err
+	}
+
+	switch r {
+	case '\r', '\n':
+		return key{code: keyEnter}, nil
+	case 127, 8:
+		return key{code: keyBackspace}, nil
+	case 3:
+		return key{code: keyCtrlC}, nil
+	case 27:
+		if in.Buffered() == 0 {
+			return key{code: keyEsc}, nil
+		}
+		return readEscape(in)
+	}
+	return key{code: keyRune, r: r}, nil
+}
+
+func readEscape(in *bufio.Reader) (key, error) {
+	prefix, err := in.ReadByte()
+	if err != nil {
+		return key{}, err
+	}
+	if prefix != '[' && prefix != 'O' {
+		return key{code: keyUnknown}, nil
+	}
+
+	b, err := in.ReadByte()
+	if err != nil {
+		return key{}, err
+	}
+	switch b {
+	case 'A':
+		return key{code: keyUp}, nil
+	case 'B':
+		return key{code: keyDown}, nil
+	case 'H':
+		return key{code: keyHome}, nil
+	case 'F':
+		return key{code: keyEnd}, nil
+	case '5', '6':
+		if _, err = in.ReadByte(); err != nil {
+			return key{}, err
+		}
+		if b == '5' {
+			return key{code: keyPageUp}, nil
+		}
+		return key{code: keyPageDown}, nil
+	}
+	return key{code: keyUnknown}, nil
+}
+
+func listHeight(height int) int {
+	rows := (height - 3) / 2
+	if rows < 1 {
+		return 1
+	}
+	return rows
+}
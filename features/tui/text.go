@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"git-issues/domain"
+	"git-issues/features/issue"
+)
+
+func labelsAndAssignees(i *domain.Issue) string {
+	var result string
+	if len(i.Labels) > 0 {
+		names := make([]string, 0, len(i.Labels))
+		for _, l := range i.Labels {
+			names = append(names, l.Name)
+		}
+		result += "  Labels: " + strings.Join(names, ", ")
+	}
+	if len(i.Assignees) > 0 {
+		logins := make([]string, 0, len(i.Assignees))
+		for _, u := range i.Assignees {
+			logins = append(logins, "@"+u.Login)
+		}
+		result += "  Assignees: " + strings.Join(logins, ", ")
+	}
+	return result
+}
+
+// truncate cuts s to at most width runes.
+func truncate(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width])
+}
+
+// renderBody renders markdown without styles, as lines are truncated by rune
+// count and that would cut escape sequences. Long words and urls the wrap
+// cannot break are cut at width.
+func renderBody(body string, width int) []string {
+	lines := strings.Split(issue.RenderMarkdown(body, issue.MarkdownOptions{Width: width}), "\n")
+	for i, line := range lines {
+		lines[i] = truncate(line, width)
+	}
+	return lines
+}
+
+func firstLine(s string) string {
+	if idx := strings.IndexByte(s, '\n'); idx >= 0 {
+		return s[:idx]
+	}
+	return s
+}
@@ -0,0 +1,251 @@
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"git-issues/domain"
+	"git-issues/features/issue"
+	"git-issues/service/terminal"
+)
+
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	leaveAltScreen = "\x1b[?25h\x1b[?1049l"
+	clearScreen    = "\x1b[H\x1b[2J"
+	reverseVideo   = "\x1b[7m"
+	resetStyle     = "\x1b[0m"
+
+	strKeysHelp = "j/k move  enter comments  / filter  c close  o reopen  l label  a assign  e edit  r refresh  q quit"
+	strLineHelp = `commands:
+  <n>                 show issue n with its comments
+  /<text>             filter the list (a single / clears it)
+  close <n>           close issue n
+  reopen <n>          reopen issue n
+  label <n> <a,b>     add labels to issue n
+  assign <n> <a,b>    assign users to issue n
+  edit <n>            edit issue n in the editor
+  refresh             reload the list
+  help                show this help
+  quit                leave`
+)
+
+var (
+	errUnknownCommand = errors.New("unknown command")
+	errIssueArgument  = errors.New("please provide a valid issue number")
+)
+
+type Feature struct {
+	list   issue.ListIssue
+	view   issue.ViewIssue
+	update issue.UpdateIssue
+	closer issue.CloseIssue
+
+	reader      io.Reader
+	writer      io.Writer
+	interactive bool
+	makeRaw     func() (func() error, error)
+	size        func() (int, int)
+	restore     func() error
+
+	issues    []domain.Issue
+	comments  map[int][]domain.Comment
+	filter    string
+	filtering bool
+	cursor    int
+	offset    int
+	status    string
+}
+
+func New(list issue.ListIssue, view issue.ViewIssue, update issue.UpdateIssue, closer issue.CloseIssue) *Feature {
+	return &Feature{
+		list:        list,
+		view:        view,
+		update:      update,
+		closer:      closer,
+		reader:      os.Stdin,
+		writer:      os.Stdout,
+		interactive: terminal.IsTerminal(os.Stdin) && terminal.IsTerminal(os.Stdout) && !terminal.IsDumb(),
+		makeRaw:     terminal.MakeRaw,
+		size:        terminal.Size,
+		comments:    map[int][]domain.Comment{},
+	}
+}
+
+// Run loads the issues and starts the full-screen browser. When the terminal
+// cannot be switched to raw mode it falls back to a line based prompt.
+func (f *Feature) Run() error {
+	if err := f.refresh(); err != nil {
+		return err
+	}
+
+	if f.interactive {
+		restore, err := f.makeRaw()
+		if err == nil {
+			f.restore = restore
+			return f.runFullScreen()
+		}
+	}
+	return f.runLineMode()
+}
+
+func (f *Feature) refresh() error {
+	issues, err := f.list.List()
+	if err != nil {
+		return err
+	}
+	f.issues = issues
+	f.comments = map[int][]domain.Comment{}
+	f.clampCursor()
+	return nil
+}
+
+// visible returns the issues matching the current filter.
+func (f *Feature) visible() []domain.Issue {
+	if f.filter == "" {
+		return f.issues
+	}
+
+	needle := strings.ToLower(f.filter)
+	var result []domain.Issue
+	for _, i := range f.issues {
+		if strings.Contains(strings.ToLower(searchText(i)), needle) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func (f *Feature) selected() *domain.Issue {
+	visible := f.visible()
+	if f.cursor < 0 || f.cursor >= len(visible) {
+		return nil
+	}
+	return f.find(visible[f.cursor].Number)
+}
+
+// find returns the loaded issue with the given number so changes made by
+// actions are reflected in the list without a refresh.
+func (f *Feature) find(number int) *domain.Issue {
+	for idx := range f.issues {
+		if f.issues[idx].Number == number {
+			return &f.issues[idx]
+		}
+	}
+	return nil
+}
+
+func (f *Feature) move(delta int) {
+	f.cursor += delta
+	f.clampCursor()
+}
+
+func (f *Feature) clampCursor() {
+	count := len(f.visible())
+	if f.cursor >= count {
+		f.cursor = count - 1
+	}
+	if f.cursor < 0 {
+		f.cursor = 0
+	}
+}
+
+func (f *Feature) loadComments(number int) error {
+	if _, ok := f.comments[number]; ok {
+		return nil
+	}
+	comments, err := f.view.Comments(number)
+	if err != nil {
+		return err
+	}
+	f.comments[number] = comments
+	return nil
+}
+
+func (f *Feature) closeIssue(number int) error {
+	if err := f.closer.Close(number); err != nil {
+		return err
+	}
+	if i := f.find(number); i != nil {
+		i.State = "closed"
+	}
+	f.status = fmt.Sprintf("issue #%d closed", number)
+	return nil
+}
+
+func (f *Feature) reopenIssue(number int) error {
+	if err := f.update.Reopen(number); err != nil {
+		return err
+	}
+	if i := f.find(number); i != nil {
+		i.State = "open"
+	}
+	f.status = fmt.Sprintf("issue #%d reopened", number)
+	return nil
+}
+
+func (f *Feature) labelIssue(number int, input string) error {
+	labels := splitList(input)
+	if len(labels) == 0 {
+		return nil
+	}
+	if err := f.update.AddLabels(number, labels...); err != nil {
+		return err
+	}
+	if i := f.find(number); i != nil {
+		for _, name := range labels {
+			i.Labels = append(i.Labels, domain.Label{Name: name})
+		}
+	}
+	f.status = fmt.Sprintf("issue #%d labeled %s", number, strings.Join(labels, ", "))
+	return nil
+}
+
+func (f *Feature) assignIssue(number int, input string) error {
+	logins := splitList(input)
+	if len(logins) == 0 {
+		return nil
+	}
+	if err := f.update.Assign(number, logins...); err != nil {
+		return err
+	}
+	if i := f.find(number); i != nil {
+		for _, login := range logins {
+			i.Assignees = append(i.Assignees, domain.User{Login: login})
+		}
+	}
+	f.status = fmt.Sprintf("issue #%d assigned to %s", number, strings.Join(logins, ", "))
+	return nil
+}
+
+func searchText(i domain.Issue) string {
+	parts := []string{fmt.Sprintf("#%d", i.Number), i.Title, i.State}
+	for _, l := range i.Labels {
+		parts = append(parts, l.Name)
+	}
+	for _, u := range i.Assignees {
+		parts = append(parts, u.Login)
+	}
+	return strings.Join(parts, " ")
+}
+
+func splitList(input string) []string {
+	var result []string
+	for _, part := range strings.Split(input, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+func newLineReader(r io.Reader) *bufio.Reader {
+	if br, ok := r.(*bufio.Reader); ok {
+		return br
+	}
+	return bufio.NewReader(r)
+}
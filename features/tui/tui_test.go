@@ -0,0 +1,317 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"git-issues/domain"
+	"git-issues/features/issue"
+	"git-issues/testdata/stubs"
+)
+
+const listURL = "https://api.example.com/repos/owner/repo/issues"
+
+// fakeRepo answers the requests made by the issue features from an in
+// memory list of issues.
+type fakeRepo struct {
+	issues  map[int]*domain.Issue
+	order   []int
+	patches []domain.Issue
+}
+
+func newFakeRepo(issues ...domain.Issue) *fakeRepo {
+	r := &fakeRepo{issues: map[int]*domain.Issue{}}
+	for idx := range issues {
+		i := issues[idx]
+		r.issues[i.Number] = &i
+		r.order = append(r.order, i.Number)
+	}
+	return r
+}
+
+func (r *fakeRepo) stub() *stubs.ClientStub {
+	return &stubs.ClientStub{
+		MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+			if url == listURL {
+				list := []domain.Issue{}
+				for _, n := range r.order {
+					list = append(list, *r.issues[n])
+				}
+				return json.Marshal(list)
+			}
+			if strings.HasSuffix(url, "/comments") {
+				return []byte(`[{"user":{"login":"hubot"},"body":"a comment"}]`), nil
+			}
+
+			var number int
+			for n := range r.issues {
+				if strings.HasSuffix(url, "/issues/"+itoa(n)) {
+					number = n
+				}
+			}
+			i, ok := r.issues[number]
+			if !ok {
+				return nil, domain.ErrApi
+			}
+			if method == "PATCH" {
+				r.patches = append(r.patches, *data)
+				i.State = data.State
+				i.Labels = data.Labels
+				i.Assignees = data.Assignees
+			}
+			return json.Marshal(i)
+		},
+	}
+}
+
+func itoa(n int) string {
+	b, _ := json.Marshal(n)
+	return string(b)
+}
+
+func newTestFeature(repo *fakeRepo, input string, interactive bool) (*Feature, *bytes.Buffer) {
+	cfg := &domain.Config{APIBaseURL: "https://api.example.com", Owner: "owner", Repo: "repo"}
+	client := repo.stub()
+	editor := &stubs.EditorStub{
+		GetIssueContentFromEditorFunc: func(i *domain.Issue) error {
+			i.Title = "edited"
+			return nil
+		},
+	}
+
+	f := New(issue.NewList(cfg, client), issue.NewView(cfg, client), issue.NewUpdate(cfg, editor, client), issue.NewClose(cfg, client))
+	out := &bytes.Buffer{}
+	f.reader = strings.NewReader(input)
+	f.writer = out
+	f.interactive = interactive
+	f.makeRaw = func() (func() error, error) { return func() error { return nil }, nil }
+	f.size = func() (int, int) { return 80, 24 }
+	return f, out
+}
+
+func sampleRepo() *fakeRepo {
+	return newFakeRepo(
+		domain.Issue{Number: 1, Title: "first bug", State: "open", Body: "first body"},
+		domain.Issue{Number: 2, Title: "second feature", State: "open", Body: "second body"},
+		domain.Issue{Number: 3, Title: "third bug", State: "open", Body: "third body"},
+	)
+}
+
+func TestFullScreenActions(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantPatch domain.Issue
+	}{
+		{
+			name:      "move down and close",
+			input:     "jcq",
+			wantPatch: domain.Issue{Number: 2, Title: "second feature", State: "closed"},
+		},
+		{
+			name:      "arrow keys move",
+			input:     "\x1b[B\x1b[B\x1b[Acq",
+			wantPatch: domain.Issue{Number: 2, Title: "second feature", State: "closed"},
+		},
+		{
+			name:      "incremental filter",
+			input:     "/third\rcq",
+			wantPatch: domain.Issue{Number: 3, Title: "third bug", State: "closed"},
+		},
+		{
+			name:      "close then reopen",
+			input:     "coq",
+			wantPatch: domain.Issue{Number: 1, Title: "first bug", State: "open"},
+		},
+		{
+			name:      "add labels",
+			input:     "lbug, ui\rq",
+			wantPatch: domain.Issue{Number: 1, Title: "first bug", State: "open", Labels: []domain.Label{{Name: "bug"}, {Name: "ui"}}},
+		},
+		{
+			name:      "assign",
+			input:     "Gaoctocat\rq",
+			wantPatch: domain.Issue{Number: 3, Title: "third bug", State: "open", Assignees: []domain.User{{Login: "octocat"}}},
+		},
+		{
+			name:      "edit",
+			input:     "eq",
+			wantPatch: domain.Issue{Number: 1, Title: "edited", State: "open"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := sampleRepo()
+			f, out := newTestFeature(repo, tt.input, true)
+
+			if err := f.Run(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(repo.patches) == 0 {
+				t.Fatalf("expected a PATCH request")
+			}
+			assertPatch(t, repo.patches[len(repo.patches)-1], tt.wantPatch)
+			if !strings.HasPrefix(out.String(), enterAltScreen) || !strings.HasSuffix(out.String(), leaveAltScreen) {
+				t.Errorf("alternate screen not entered and left")
+			}
+		})
+	}
+}
+
+func TestFullScreenPreview(t *testing.T) {
+	repo := sampleRepo()
+	f, out := newTestFeature(repo, "j\r", true)
+
+	if err := f.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{"#2 second feature", "second body", "@hubot", "a comment"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+}
+
+func TestFullScreenSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  int
+		height int
+	}{
+		{name: "few rows", width: 80, height: 3},
+		{name: "narrow", width: 20, height: 24},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo(domain.Issue{Number: 1, Title: "long", State: "open", Body: "see https://example.com/a/very/long/path/that/cannot/wrap"})
+			f, out := newTestFeature(repo, "q", true)
+			f.size = func() (int, int) { return tt.width, tt.height }
+
+			if err := f.Run(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			screen := strings.TrimSuffix(out.String(), leaveAltScreen)
+			screen = screen[strings.LastIndex(screen, clearScreen)+len(clearScreen):]
+			for _, line := range strings.Split(screen, "\r\n") {
+				line = strings.TrimSuffix(strings.TrimPrefix(line, reverseVideo), resetStyle)
+				if n := utf8.RuneCountInString(line); n > tt.width {
+					t.Errorf("line of %d runes wider than %d: %q", n, tt.width, line)
+				}
+			}
+		})
+	}
+}
+
+func TestFallbackWhenRawModeFails(t *testing.T) {
+	repo := sampleRepo()
+	f, out := newTestFeature(repo, "quit\n", true)
+	f.makeRaw = func() (func() error, error) { return nil, errors.New("no stty") }
+
+	if err := f.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out.String(), enterAltScreen) {
+		t.Errorf("alternate screen used on fallback")
+	}
+	if !strings.Contains(out.String(), "#1 - first bug (open)") {
+		t.Errorf("expected plain list, got %q", out.String())
+	}
+}
+
+func TestLineMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantOut   []string
+		wantPatch *domain.Issue
+	}{
+		{
+			name:    "filter",
+			input:   "/bug\n",
+			wantOut: []string{"#3 - third bug (open)"},
+		},
+		{
+			name:    "show issue with comments",
+			input:   "2\n",
+			wantOut: []string{"Title: second feature", "@hubot", "a comment"},
+		},
+		{
+			name:      "close",
+			input:     "close 1\n",
+			wantOut:   []string{"issue #1 closed"},
+			wantPatch: &domain.Issue{Number: 1, Title: "first bug", State: "closed"},
+		},
+		{
+			name:      "label",
+			input:     "label 2 bug,ui\n",
+			wantOut:   []string{"issue #2 labeled bug, ui"},
+			wantPatch: &domain.Issue{Number: 2, Title: "second feature", State: "open", Labels: []domain.Label{{Name: "bug"}, {Name: "ui"}}},
+		},
+		{
+			name:    "invalid number",
+			input:   "close x\n",
+			wantOut: []string{"error: " + errIssueArgument.Error()},
+		},
+		{
+			name:    "unknown command",
+			input:   "frobnicate 1\n",
+			wantOut: []string{"unknown command: frobnicate"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := sampleRepo()
+			f, out := newTestFeature(repo, tt.input, false)
+
+			if err := f.Run(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.wantOut {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, out.String())
+				}
+			}
+			if tt.wantPatch != nil {
+				if len(repo.patches) != 1 {
+					t.Fatalf("expected one PATCH, got %d", len(repo.patches))
+				}
+				assertPatch(t, repo.patches[0], *tt.wantPatch)
+			}
+		})
+	}
+}
+
+func TestListError(t *testing.T) {
+	f, _ := newTestFeature(sampleRepo(), "", false)
+	f.list = issue.NewList(&domain.Config{}, &stubs.ClientStub{
+		MakeRequestFunc: func(method, url string, data *domain.Issue) ([]byte, error) {
+			return nil, domain.ErrApi
+		},
+	})
+
+	if err := f.Run(); !errors.Is(err, domain.ErrApi) {
+		t.Errorf("expected ErrApi, got %v", err)
+	}
+}
+
+// assertPatch compares the fields the actions change, ignoring the body.
+func assertPatch(t *testing.T, got, want domain.Issue) {
+	t.Helper()
+	got.Body, want.Body = "", ""
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("unexpected patch:\ngot:  %s\nwant: %s", gotJSON, wantJSON)
+	}
+}
@@ -0,0 +1,71 @@
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	FormatText   = "text"
+	FormatNDJSON = "ndjson"
+)
+
+// Formats are the formats PrintChange writes.
+var Formats = []string{FormatText, FormatNDJSON}
+
+// commentWidth is how much of a comment the text format shows.
+const commentWidth = 72
+
+var errFormat = errors.New("unknown output format")
+
+// ValidFormat checks a format of PrintChange.
+func ValidFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q, use %s", errFormat, format, strings.Join(Formats, ", "))
+}
+
+// PrintChange writes a change as a line of text, in local time, or as a JSON
+// object per line with ndjson.
+func PrintChange(w io.Writer, format string, change Change) error {
+	if format == FormatNDJSON {
+		return json.NewEncoder(w).Encode(change)
+	}
+
+	at := change.Time
+	if t, err := time.Parse(time.RFC3339, change.Time); err == nil {
+		at = t.Local().Format("2006-01-02 15:04:05")
+	}
+	what := change.Kind
+	if change.Label != "" {
+		what += " " + change.Label
+	}
+	line := fmt.Sprintf("%s  #%d %s: %s", at, change.Number, what, change.Title)
+	if change.Actor != "" {
+		line += " (" + change.Actor + ")"
+	}
+	if _, err := fmt.Fprintln(w, line); err != nil {
+		return err
+	}
+	if change.Comment == "" {
+		return nil
+	}
+	_, err := fmt.Fprintf(w, "    %s\n", excerpt(change.Comment, commentWidth))
+	return err
+}
+
+// excerpt is the text on one line, cut to width runes.
+func excerpt(text string, width int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) <= width {
+		return string(runes)
+	}
+	return string(runes[:width-1]) + "…"
+}
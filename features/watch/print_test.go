@@ -0,0 +1,63 @@
+package watch
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrintChange(t *testing.T) {
+	at := "2026-10-19T12:01:00Z"
+	local, _ := time.Parse(time.RFC3339, at)
+	stamp := local.Local().Format("2006-01-02 15:04:05")
+
+	tests := []struct {
+		name   string
+		format string
+		change Change
+		want   string
+	}{
+		{
+			name:   "opened",
+			format: FormatText,
+			change: Change{Time: at, Kind: "opened", Number: 2, Title: "Crash", Actor: "mona"},
+			want:   stamp + "  #2 opened: Crash (mona)\n",
+		},
+		{
+			name:   "labeled",
+			format: FormatText,
+			change: Change{Time: at, Kind: "labeled", Label: "ui", Number: 2, Title: "Crash"},
+			want:   stamp + "  #2 labeled ui: Crash\n",
+		},
+		{
+			name:   "commented",
+			format: FormatText,
+			change: Change{Time: at, Kind: "commented", Number: 2, Title: "Crash", Actor: "hubot", Comment: "Same\n\nhere " + strings.Repeat("x", 80)},
+			want:   stamp + "  #2 commented: Crash (hubot)\n    Same here " + strings.Repeat("x", 61) + "…\n",
+		},
+		{
+			name:   "ndjson",
+			format: FormatNDJSON,
+			change: Change{Time: at, Kind: "labeled", Label: "ui", Number: 2, Title: "Crash", Labels: []string{"ui"}},
+			want:   `{"time":"2026-10-19T12:01:00Z","event":"labeled","number":2,"title":"Crash","label":"ui","labels":["ui"]}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := PrintChange(&b, tt.format, tt.change); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("got %q\nwant %q", b.String(), tt.want)
+			}
+		})
+	}
+
+	if err := ValidFormat("csv"); !errors.Is(err, errFormat) {
+		t.Errorf("got %v want errFormat", err)
+	}
+}
@@ -0,0 +1,375 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/service/client"
+	"git-issues/service/host"
+)
+
+// DefaultInterval is the wait between polls until GitHub sends X-Poll-Interval.
+const DefaultInterval = time.Minute
+
+// pageSize is the largest page of the events endpoints; more changes than
+// that between two polls are only reported in part.
+const pageSize = 100
+
+// Kinds are the changes watch reports.
+var Kinds = []string{"opened", "commented", "closed", "reopened", "labeled", "unlabeled"}
+
+var (
+	errWatch  = errors.New("could not poll the repository")
+	errCursor = errors.New("could not save the watch cursor")
+	errKind   = errors.New("unknown change")
+)
+
+// Change is a new issue, comment, state or label change. Label is set for
+// labeled and unlabeled, Comment for commented.
+type Change struct {
+	Time      string   `json:"time"`
+	Kind      string   `json:"event"`
+	Number    int      `json:"number"`
+	Title     string   `json:"title"`
+	Actor     string   `json:"actor,omitempty"`
+	Label     string   `json:"label,omitempty"`
+	Comment   string   `json:"comment,omitempty"`
+	URL       string   `json:"url,omitempty"`
+	State     string   `json:"state,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+}
+
+// Filter keeps the changes of the Kinds listed, on issues carrying every
+// label of Labels and assigned to Assignee, made by Actor. Empty fields keep
+// everything.
+type Filter struct {
+	Kinds    []string
+	Labels   []string
+	Assignee string
+	Actor    string
+}
+
+// Cursor is the position of the watch in both event streams, saved after
+// every poll. The etags make the next poll conditional.
+type Cursor struct {
+	Repository      string    `json:"repository"`
+	StartedAt       time.Time `json:"started_at"`
+	EventID         int64     `json:"event_id"`
+	EventsETag      string    `json:"events_etag,omitempty"`
+	IssueEventID    int64     `json:"issue_event_id"`
+	IssueEventsETag string    `json:"issue_events_etag,omitempty"`
+}
+
+// repoEvent is an item of the repository events endpoint.
+type repoEvent struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Actor     domain.User `json:"actor"`
+	CreatedAt string      `json:"created_at"`
+	Payload   struct {
+		Action  string          `json:"action"`
+		Issue   *domain.Issue   `json:"issue"`
+		Comment *domain.Comment `json:"comment"`
+	} `json:"payload"`
+}
+
+type Feature struct {
+	config    *domain.Config
+	client    client.ConditionalClient
+	now       func() time.Time
+	after     func(d time.Duration) <-chan time.Time
+	readFile  func(name string) ([]byte, error)
+	writeFile func(name string, data []byte, perm os.FileMode) error
+
+	// Filter narrows the changes Poll returns.
+	Filter Filter
+	// Interval is the least wait between polls, raised by X-Poll-Interval.
+	Interval time.Duration
+	// CursorFile keeps the cursor, by default in the ghissues directory of
+	// the user cache dir, named after the host and repository.
+	CursorFile string
+}
+
+func New(config *domain.Config, client client.ConditionalClient) *Feature {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	name := host.FileName("watch", host.FromAPIBaseURL(config.APIBaseURL).Name, config.Owner, config.Repo)
+
+	return &Feature{
+		config:     config,
+		client:     client,
+		now:        time.Now,
+		after:      time.After,
+		readFile:   os.ReadFile,
+		writeFile:  os.WriteFile,
+		Interval:   DefaultInterval,
+		CursorFile: filepath.Join(dir, "ghissues", name+".json"),
+	}
+}
+
+// ValidKinds checks the kinds of a filter.
+func ValidKinds(kinds []string) error {
+	for _, kind := range kinds {
+		valid := false
+		for _, k := range Kinds {
+			valid = valid || k == kind
+		}
+		if !valid {
+			return fmt.Errorf("%w: %q, use %s", errKind, kind, strings.Join(Kinds, ", "))
+		}
+	}
+	return nil
+}
+
+// Watch polls until ctx is done and hands every change to emit as it comes.
+// A failed poll goes to report and the watch goes on; an error of emit, like
+// a closed pipe, ends it.
+func (f *Feature) Watch(ctx context.Context, emit func(Change) error, report func(error)) error {
+	for {
+		changes, wait, err := f.Poll()
+		if err != nil {
+			report(err)
+		}
+		for _, change := range changes {
+			if err = emit(change); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-f.after(wait):
+		}
+	}
+}
+
+// Poll returns the changes since the cursor, oldest first, with the time to
+// wait before the next poll. The repository events carry new issues,
+// comments and state changes but not label changes, which come from the
+// issue events. The first poll of a repository only sets the cursor, so
+// watching starts from now.
+func (f *Feature) Poll() ([]Change, time.Duration, error) {
+	repository := f.config.Owner + "/" + f.config.Repo
+	cursor := f.loadCursor(repository)
+	first := cursor.StartedAt.IsZero()
+	if first {
+		cursor.StartedAt = f.now().UTC()
+	}
+	wait := f.Interval
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", f.config.APIBaseURL, f.config.Owner, f.config.Repo)
+
+	var changes []Change
+	var errs []error
+
+	body, etag, interval, err := f.get(fmt.Sprintf("%s/events?per_page=%d", repoURL, pageSize), cursor.EventsETag)
+	wait = max(wait, interval)
+	if err != nil {
+		errs = append(errs, err)
+	} else if body != nil {
+		var events []repoEvent
+		if err = json.Unmarshal(body, &events); err != nil {
+			errs = append(errs, errors.Join(errWatch, domain.ErrEncoding, err))
+		} else {
+			var latest int64
+			changes, latest = fromRepoEvents(events, cursor.EventID)
+			cursor.EventID = max(cursor.EventID, latest)
+			cursor.EventsETag = etag
+		}
+	}
+
+	body, etag, interval, err = f.get(fmt.Sprintf("%s/issues/events?per_page=%d", repoURL, pageSize), cursor.IssueEventsETag)
+	wait = max(wait, interval)
+	if err != nil {
+		errs = append(errs, err)
+	} else if body != nil {
+		var events []domain.Event
+		if err = json.Unmarshal(body, &events); err != nil {
+			errs = append(errs, errors.Join(errWatch, domain.ErrEncoding, err))
+		} else {
+			labelChanges, latest := fromIssueEvents(events, cursor.IssueEventID)
+			changes = append(changes, labelChanges...)
+			cursor.IssueEventID = max(cursor.IssueEventID, latest)
+			cursor.IssueEventsETag = etag
+		}
+	}
+
+	// a first poll that failed in part starts over, as the ids it lacks
+	// would replay the past
+	if first && len(errs) > 0 {
+		return nil, wait, errors.Join(errs...)
+	}
+	if err = f.saveCursor(cursor); err != nil {
+		errs = append(errs, err)
+	}
+	if first {
+		return nil, wait, errors.Join(errs...)
+	}
+
+	// timestamps are RFC 3339 in UTC, so they sort as strings
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Time < changes[j].Time
+	})
+	kept := changes[:0]
+	for _, change := range changes {
+		if f.Filter.Match(change) {
+			kept = append(kept, change)
+		}
+	}
+	return kept, wait, errors.Join(errs...)
+}
+
+// get sends a conditional request and returns the body, nil when not
+// modified, with the etag and poll interval of the answer.
+func (f *Feature) get(url, etag string) ([]byte, string, time.Duration, error) {
+	body, header, modified, err := f.client.MakeConditionalRequest(url, etag)
+	if err != nil {
+		return nil, "", 0, errors.Join(errWatch, err)
+	}
+	var interval time.Duration
+	if seconds, err := strconv.Atoi(header.Get("X-Poll-Interval")); err == nil && seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+	if !modified {
+		return nil, etag, interval, nil
+	}
+	return body, header.Get("ETag"), interval, nil
+}
+
+// fromRepoEvents maps the events newer than after, and returns the id of the
+// newest event.
+func fromRepoEvents(events []repoEvent, after int64) ([]Change, int64) {
+	var changes []Change
+	latest := after
+	for _, event := range events {
+		id, err := strconv.ParseInt(event.ID, 10, 64)
+		if err != nil || id <= after {
+			continue
+		}
+		latest = max(latest, id)
+
+		issue := event.Payload.Issue
+		if issue == nil || issue.PullRequest != nil {
+			continue
+		}
+		change := newChange(issue, event.CreatedAt, event.Actor.Login)
+		switch {
+		case event.Type == "IssuesEvent" && (event.Payload.Action == "opened" || event.Payload.Action == "closed" || event.Payload.Action == "reopened"):
+			change.Kind = event.Payload.Action
+		case event.Type == "IssueCommentEvent" && event.Payload.Action == "created" && event.Payload.Comment != nil:
+			change.Kind = "commented"
+			change.Comment = event.Payload.Comment.Body
+			change.URL = event.Payload.Comment.HTMLURL
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes, latest
+}
+
+// fromIssueEvents maps the label events newer than after, and returns the id
+// of the newest event.
+func fromIssueEvents(events []domain.Event, after int64) ([]Change, int64) {
+	var changes []Change
+	latest := after
+	for _, event := range events {
+		if event.ID <= after {
+			continue
+		}
+		latest = max(latest, event.ID)
+
+		if (event.Event != "labeled" && event.Event != "unlabeled") || event.Label == nil || event.Issue == nil || event.Issue.PullRequest != nil {
+			continue
+		}
+		actor := ""
+		if event.Actor != nil {
+			actor = event.Actor.Login
+		}
+		change := newChange(event.Issue, event.CreatedAt, actor)
+		change.Kind = event.Event
+		change.Label = event.Label.Name
+		changes = append(changes, change)
+	}
+	return changes, latest
+}
+
+func newChange(issue *domain.Issue, at, actor string) Change {
+	change := Change{
+		Time:   at,
+		Number: issue.Number,
+		Title:  issue.Title,
+		Actor:  actor,
+		URL:    issue.HTMLURL,
+		State:  issue.State,
+	}
+	for _, l := range issue.Labels {
+		change.Labels = append(change.Labels, l.Name)
+	}
+	for _, u := range issue.Assignees {
+		change.Assignees = append(change.Assignees, u.Login)
+	}
+	return change
+}
+
+// Match tells whether the filter keeps the change.
+func (filter Filter) Match(change Change) bool {
+	if len(filter.Kinds) > 0 && !contains(filter.Kinds, change.Kind) {
+		return false
+	}
+	for _, label := range filter.Labels {
+		if !contains(change.Labels, label) {
+			return false
+		}
+	}
+	if filter.Assignee != "" && !contains(change.Assignees, filter.Assignee) {
+		return false
+	}
+	return filter.Actor == "" || strings.EqualFold(filter.Actor, change.Actor)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadCursor reads the cursor of the repository; a missing or unreadable
+// file, or one of another repository, starts over.
+func (f *Feature) loadCursor(repository string) Cursor {
+	var cursor Cursor
+	data, err := f.readFile(f.CursorFile)
+	if err != nil || json.Unmarshal(data, &cursor) != nil || cursor.Repository != repository {
+		return Cursor{Repository: repository}
+	}
+	return cursor
+}
+
+func (f *Feature) saveCursor(cursor Cursor) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return errors.Join(errCursor, err)
+	}
+	if err = os.MkdirAll(filepath.Dir(f.CursorFile), 0700); err != nil {
+		return errors.Join(errCursor, err)
+	}
+	if err = f.writeFile(f.CursorFile, data, 0600); err != nil {
+		return errors.Join(errCursor, err)
+	}
+	return nil
+}
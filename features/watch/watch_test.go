@@ -0,0 +1,235 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+const (
+	eventsURL      = "https://api.github.com/repos/owner/repo/events?per_page=100"
+	issueEventsURL = "https://api.github.com/repos/owner/repo/issues/events?per_page=100"
+)
+
+// server answers like GitHub: 304 while the etag matches the body.
+type server struct {
+	bodies   map[string]string
+	fail     bool
+	requests []string
+}
+
+func (s *server) client() *stubs.ClientStub {
+	return &stubs.ClientStub{
+		MakeConditionalRequestFunc: func(url, etag string) ([]byte, http.Header, bool, error) {
+			s.requests = append(s.requests, url+" "+etag)
+			if s.fail {
+				return nil, nil, false, domain.ErrApi
+			}
+			body, ok := s.bodies[url]
+			if !ok {
+				return nil, nil, false, domain.ErrApi
+			}
+			header := http.Header{"Etag": {`"` + body + `"`}}
+			if url == eventsURL {
+				header.Set("X-Poll-Interval", "90")
+			}
+			if etag == header.Get("ETag") {
+				return nil, header, false, nil
+			}
+			return []byte(body), header, true, nil
+		},
+	}
+}
+
+func newTestFeature(t *testing.T, s *server, file string) *Feature {
+	t.Helper()
+	config := &domain.Config{APIBaseURL: "https://api.github.com", Owner: "owner", Repo: "repo"}
+	f := New(config, s.client())
+	f.CursorFile = file
+	f.now = func() time.Time { return time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC) }
+	return f
+}
+
+func TestPoll(t *testing.T) {
+	s := &server{bodies: map[string]string{
+		eventsURL:      `[{"id":"100","type":"IssuesEvent","payload":{"action":"opened","issue":{"number":1,"title":"Old"}}}]`,
+		issueEventsURL: `[{"id":50,"event":"labeled","label":{"name":"bug"},"issue":{"number":1,"title":"Old"}}]`,
+	}}
+	file := filepath.Join(t.TempDir(), "cursor.json")
+	f := newTestFeature(t, s, file)
+
+	// the first poll starts from now
+	changes, wait, err := f.Poll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 0 || wait != 90*time.Second {
+		t.Errorf("got %+v waiting %v, want no change and the poll interval", changes, wait)
+	}
+
+	// nothing new is not modified
+	changes, _, err = f.Poll()
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("got %+v, %v, want no change", changes, err)
+	}
+	if !strings.HasSuffix(s.requests[2], `"`+s.bodies[eventsURL]+`"`) || !strings.HasSuffix(s.requests[3], `"`+s.bodies[issueEventsURL]+`"`) {
+		t.Errorf("polls are not conditional: %q", s.requests[2:])
+	}
+
+	s.bodies[eventsURL] = `[
+		{"id":"104","type":"IssueCommentEvent","actor":{"login":"hubot"},"created_at":"2026-10-19T12:04:00Z","payload":{"action":"created","issue":{"number":1,"title":"Old","labels":[{"name":"bug"}]},"comment":{"body":"Same here","html_url":"https://github.com/owner/repo/issues/1#issuecomment-9"}}},
+		{"id":"103","type":"IssueCommentEvent","created_at":"2026-10-19T12:03:00Z","payload":{"action":"created","issue":{"number":5,"title":"A pull","pull_request":{}},"comment":{"body":"LGTM"}}},
+		{"id":"102","type":"IssuesEvent","actor":{"login":"mona"},"created_at":"2026-10-19T12:01:00Z","payload":{"action":"opened","issue":{"number":2,"title":"Crash","state":"open","html_url":"https://github.com/owner/repo/issues/2","assignees":[{"login":"mona"}]}}},
+		{"id":"101","type":"WatchEvent","created_at":"2026-10-19T12:00:30Z","payload":{"action":"started"}},
+		{"id":"100","type":"IssuesEvent","payload":{"action":"opened","issue":{"number":1,"title":"Old"}}}
+	]`
+	s.bodies[issueEventsURL] = `[
+		{"id":52,"event":"labeled","actor":{"login":"mona"},"created_at":"2026-10-19T12:02:00Z","label":{"name":"ui"},"issue":{"number":2,"title":"Crash","labels":[{"name":"ui"}]}},
+		{"id":51,"event":"closed","created_at":"2026-10-19T12:02:00Z","issue":{"number":1,"title":"Old"}},
+		{"id":50,"event":"labeled","label":{"name":"bug"},"issue":{"number":1,"title":"Old"}}
+	]`
+
+	changes, _, err = f.Poll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Change{
+		{Time: "2026-10-19T12:01:00Z", Kind: "opened", Number: 2, Title: "Crash", Actor: "mona", URL: "https://github.com/owner/repo/issues/2", State: "open", Assignees: []string{"mona"}},
+		{Time: "2026-10-19T12:02:00Z", Kind: "labeled", Number: 2, Title: "Crash", Actor: "mona", Label: "ui", Labels: []string{"ui"}},
+		{Time: "2026-10-19T12:04:00Z", Kind: "commented", Number: 1, Title: "Old", Actor: "hubot", Comment: "Same here", URL: "https://github.com/owner/repo/issues/1#issuecomment-9", Labels: []string{"bug"}},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("got %+v\nwant %+v", changes, want)
+	}
+
+	// a restart reads the cursor and replays nothing
+	changes, _, err = newTestFeature(t, s, file).Poll()
+	if err != nil || len(changes) != 0 {
+		t.Errorf("got %+v, %v after a restart, want no change", changes, err)
+	}
+}
+
+func TestPollErrors(t *testing.T) {
+	s := &server{bodies: map[string]string{eventsURL: `[]`, issueEventsURL: `[]`}, fail: true}
+	file := filepath.Join(t.TempDir(), "cursor.json")
+	f := newTestFeature(t, s, file)
+
+	// a failed first poll keeps no cursor
+	if _, wait, err := f.Poll(); !errors.Is(err, errWatch) || wait != DefaultInterval {
+		t.Fatalf("got %v waiting %v, want errWatch and the default interval", err, wait)
+	}
+	if _, err := f.readFile(file); err == nil {
+		t.Error("a failed first poll saved the cursor")
+	}
+
+	s.fail = false
+	if _, _, err := f.Poll(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the events of another repository start over
+	f.config.Repo = "other"
+	if cursor := f.loadCursor("owner/other"); !cursor.StartedAt.IsZero() {
+		t.Errorf("got the cursor %+v of another repository", cursor)
+	}
+
+	f.CursorFile = filepath.Join(file, "in-a-file")
+	f.config.Repo = "repo"
+	if _, _, err := f.Poll(); !errors.Is(err, errCursor) {
+		t.Errorf("got %v want errCursor", err)
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	change := Change{Kind: "labeled", Actor: "Mona", Labels: []string{"bug", "ui"}, Assignees: []string{"hubot"}}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{name: "empty", want: true},
+		{name: "kind", filter: Filter{Kinds: []string{"opened", "labeled"}}, want: true},
+		{name: "other kind", filter: Filter{Kinds: []string{"commented"}}},
+		{name: "labels", filter: Filter{Labels: []string{"bug", "UI"}}, want: true},
+		{name: "missing label", filter: Filter{Labels: []string{"bug", "docs"}}},
+		{name: "assignee", filter: Filter{Assignee: "hubot"}, want: true},
+		{name: "other assignee", filter: Filter{Assignee: "mona"}},
+		{name: "actor", filter: Filter{Actor: "mona"}, want: true},
+		{name: "other actor", filter: Filter{Actor: "hubot"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(change); got != tt.want {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := ValidKinds([]string{"opened", "merged"}); !errors.Is(err, errKind) {
+		t.Errorf("got %v want errKind", err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s := &server{bodies: map[string]string{eventsURL: `[]`, issueEventsURL: `[]`}}
+	f := newTestFeature(t, s, filepath.Join(t.TempDir(), "cursor.json"))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	polls := 0
+	var waits []time.Duration
+	f.after = func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		polls++
+		switch polls {
+		case 1:
+			s.bodies[eventsURL] = `[{"id":"7","type":"IssuesEvent","created_at":"2026-10-19T12:01:00Z","payload":{"action":"closed","issue":{"number":3,"title":"Done"}}}]`
+		case 2:
+			s.fail = true
+		default:
+			cancel()
+			return nil
+		}
+		ch := make(chan time.Time, 1)
+		ch <- time.Time{}
+		return ch
+	}
+
+	var got []Change
+	var reported []error
+	err := f.Watch(ctx, func(c Change) error {
+		got = append(got, c)
+		return nil
+	}, func(err error) {
+		reported = append(reported, err)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].Kind != "closed" || got[0].Number != 3 {
+		t.Errorf("got %+v, want the closed issue", got)
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], errWatch) {
+		t.Errorf("got reports %v, want the failed poll", reported)
+	}
+	if waits[0] != 90*time.Second {
+		t.Errorf("got waits %v, want the poll interval", waits)
+	}
+
+	// an error of emit ends the watch
+	s.fail = false
+	s.bodies[eventsURL] = `[{"id":"8","type":"IssuesEvent","created_at":"2026-10-19T12:05:00Z","payload":{"action":"reopened","issue":{"number":3,"title":"Done"}}}]`
+	err = f.Watch(context.Background(), func(Change) error { return errors.New("broken pipe") }, func(error) {})
+	if err == nil || err.Error() != "broken pipe" {
+		t.Errorf("got %v want the emit error", err)
+	}
+}
@@ -1,17 +1,24 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"git-issues/application"
 	"git-issues/domain"
 	"git-issues/features/conf"
 	"git-issues/features/help"
 	"git-issues/features/issue"
+	"git-issues/features/project"
+	"git-issues/features/tui"
 	"git-issues/service/client"
 	"git-issues/service/editor"
+	"git-issues/service/host"
+	"git-issues/service/terminal"
 )
 
 func main() {
@@ -23,6 +30,17 @@ func main() {
 	var err error
 	featureConfig := conf.New()
 
+	// --profile applies to every command, so it is taken out before them
+	profile, args := profileFlag(os.Args[1:])
+	if args, err = expandAlias(args); err != nil {
+		fmt.Printf("error on expand alias: %v\n", err)
+		return
+	}
+	os.Args = append(os.Args[:1], args...)
+	if len(os.Args) < 2 {
+		help.PrintHelp()
+		return
+	}
 	command := os.Args[1]
 
 	if command == "init" {
@@ -33,15 +51,58 @@ func main() {
 		return
 	}
 
-	config, err := application.LoadConfig(domain.ConfigFile)
+	if command == "config" {
+		runConfig(featureConfig, os.Args[2:])
+		return
+	}
+
+	if command == "completion" {
+		runCompletion(os.Args[2:])
+		return
+	}
+
+	if command == completeCommand {
+		runComplete(profile, os.Args[2:])
+		return
+	}
+
+	if command == "alias" {
+		runAlias(featureConfig, os.Args[2:])
+		return
+	}
+
+	if command == "query" && (len(os.Args) < 3 || os.Args[2] != "run") {
+		runQuery(featureConfig, os.Args[2:])
+		return
+	}
+
+	if command == "auth" {
+		runAuth(featureConfig, profile, os.Args[2:])
+		return
+	}
+
+	if command == "hooks" {
+		runHooks(profile, os.Args[2:])
+		return
+	}
+
+	if command == "doctor" {
+		runDoctor(profile, os.Args[2:])
+		return
+	}
+
+	config, err := application.LoadProfile(domain.ConfigFile, profile)
+	if errors.Is(err, application.ErrProfileNotFound) {
+		fmt.Printf("could not load conf: %v\n", err)
+		fmt.Println("see 'ghissues config list' for the profiles.")
+		return
+	}
 	if err != nil {
 		fmt.Printf("could not load conf: %v\n", err)
 		fmt.Println("please run 'git_issues init' to configure.")
 		return
 	}
 
-	var response string
-
 	textEditor := editor.New(config)
 	serviceClient := client.New(config)
 	create := issue.NewCreate(config, textEditor, serviceClient)
@@ -51,23 +112,115 @@ func main() {
 
 	switch command {
 	case "create":
-		response, err = create.Create()
+		flags := flag.NewFlagSet("create", flag.ContinueOnError)
+		projectName := flags.String("project", "", "add the new issue to this project")
+		noCheck := flags.Bool("no-duplicate-check", false, "create without looking for similar issues")
+		web := flags.Bool("web", false, "open the new issue form in the browser instead")
+		printURL := flags.Bool("print-url", false, "print the url of the new issue form")
+		var form issue.NewIssueForm
+		var labels stringList
+		flags.StringVar(&form.Title, "title", "", "title of the new issue form")
+		flags.StringVar(&form.Body, "body", "", "body of the new issue form")
+		flags.Var(&labels, "label", "comma separated labels of the new issue form (repeatable)")
+		flags.StringVar(&form.Template, "template", "", "issue template of the new issue form")
+		if _, err = parseFlags(flags, os.Args[2:]); err != nil {
+			return
+		}
+		if !*web && !*printURL && (form.Title != "" || form.Body != "" || len(labels) > 0 || form.Template != "") {
+			fmt.Println("--title, --body, --label and --template fill the form of --web or --print-url")
+			return
+		}
+		if *web || *printURL {
+			for _, l := range labels {
+				form.Labels = append(form.Labels, splitList(l)...)
+			}
+			openWeb(config, *printURL, func(b *issue.BrowseFeature) (string, error) {
+				return b.NewIssueURL(form)
+			})
+			return
+		}
+		create.CheckDuplicates = !*noCheck
+		created, err := create.CreateIssue()
 		if err != nil {
 			fmt.Printf("error on create issue: %v\n", err)
 			return
 		}
-		fmt.Println(response)
+		if created.DuplicatesErr != nil {
+			fmt.Printf("could not check for duplicates: %v\n", created.DuplicatesErr)
+		}
+		if created.CommentURL != "" {
+			fmt.Printf("Comment added to issue #%v\nURL: %v\n", created.Number, created.CommentURL)
+			return
+		}
+		url := created.HTMLURL
+		if url == "" {
+			url = host.FromAPIBaseURL(config.APIBaseURL).IssueURL(config.Owner, config.Repo, created.Number)
+		}
+		fmt.Printf("Issue created with success!\nNumber: %v\nURL: %v\n\n", created.Number, url)
+		if *projectName != "" {
+			err = project.New(config, serviceClient).Add(created.Number, *projectName)
+			if err != nil {
+				fmt.Printf("error on add issue to project: %v\n", err)
+				return
+			}
+			fmt.Println("issue added to project")
+		}
 	case "list":
-		issues, err := list.List()
-		if err != nil {
-			fmt.Printf("error on list issues: %v\n", err)
+		flags := flag.NewFlagSet("list", flag.ContinueOnError)
+		noPager := flags.Bool("no-pager", false, "do not pipe the output through a pager")
+		format := flags.String("format", issue.FormatText, "output format: text, json or csv")
+		flags.StringVar(&list.Sort, "sort", "", "order of the issues: "+strings.Join(issue.ListSorts, ", "))
+		repos := flags.String("repos", "", "comma separated owner/repo list to list together")
+		flags.StringVar(&list.Filter.State, "state", "", "open, closed or all, open if empty")
+		flags.StringVar(&list.Filter.Assignee, "assignee", "", "login, @me, none or *")
+		flags.StringVar(&list.Filter.Labels, "label", "", "comma separated labels the issues all have")
+		query := flags.String("query", "", "list the matches of the saved query instead")
+		web := flags.Bool("web", false, "open the list in the browser")
+		printURL := flags.Bool("print-url", false, "print the url of the list in the browser")
+		if _, err = parseFlags(flags, os.Args[2:]); err != nil {
+			return
+		}
+		if *web || *printURL {
+			if *repos != "" {
+				fmt.Println("--web lists a single repository, leave out --repos")
+				return
+			}
+			openWeb(config, *printURL, func(b *issue.BrowseFeature) (string, error) {
+				text := ""
+				if *query != "" {
+					if text, err = conf.Query(config, *query); err != nil {
+						return "", err
+					}
+				}
+				return b.SearchURL(list.Filter, text, list.Sort)
+			})
 			return
 		}
-		err = issue.PrintIssues(w, issues)
+		if err = issue.ValidFormat(*format); err != nil {
+			fmt.Println(err)
+			return
+		}
+		var issues []domain.Issue
+		// List and ListRepos return the issues in order, the matches of a
+		// query are sorted here
+		sortBy := ""
+		switch {
+		case *query != "":
+			var text string
+			if text, err = conf.Query(config, *query); err == nil {
+				issues, err = issue.NewSearch(config, serviceClient).Search(text)
+			}
+			sortBy = list.Sort
+		case *repos != "":
+			issues, err = list.ListRepos(splitList(*repos))
+		default:
+			issues, err = list.List()
+		}
 		if err != nil {
-			fmt.Printf("error on print issues: %v\n", err)
+			fmt.Printf("error on list issues: %v\n", err)
 			return
 		}
+		printIssueList(config, issues, *format, sortBy, *noPager)
 
 	case "update":
 		if len(os.Args) < 3 {
@@ -90,22 +243,70 @@ func main() {
 		fmt.Println("issue updated")
 
 	case "view":
-		if len(os.Args) < 3 {
+		flags := flag.NewFlagSet("view", flag.ContinueOnError)
+		raw := flags.Bool("raw", false, "print the body without markdown rendering")
+		noPager := flags.Bool("no-pager", false, "do not pipe the output through a pager")
+		tree := flags.Bool("tree", false, "print the sub-issues and task list as a tree")
+		web := flags.Bool("web", false, "open the issue in the browser")
+		printURL := flags.Bool("print-url", false, "print the url of the issue in the browser")
+		args, err := parseFlags(flags, os.Args[2:])
+		if err != nil {
+			return
+		}
+		if len(args) < 1 {
 			fmt.Println("please provide an issue number")
 			return
 		}
-		number, err := strconv.Atoi(os.Args[2])
+		number, err := strconv.Atoi(args[0])
 		if err != nil {
 			fmt.Println("please provide a valid issue number")
 			return
 		}
+		if *web || *printURL {
+			openWeb(config, *printURL, func(b *issue.BrowseFeature) (string, error) {
+				return b.IssueURL(number)
+			})
+			return
+		}
+		if *tree {
+			root, err := issue.NewSubIssue(config, serviceClient).Tree(number, issue.TreeDepth)
+			if err != nil {
+				fmt.Printf("error on view issue tree: %v\n", err)
+				return
+			}
+			if err = issue.PrintTree(w, root); err != nil {
+				fmt.Printf("error on print issue tree: %v\n", err)
+			}
+			return
+		}
 		view := issue.NewView(config, serviceClient)
 		issueData, err := view.View(number)
 		if err != nil {
 			fmt.Printf("error on view issue: %v\n", err)
 			return
 		}
-		err = issue.PrintIssue(w, issueData)
+		// the pin state needs GraphQL; a token without access just omits it
+		issueData.Pinned, _ = issue.NewPin(config, serviceClient).IsPinned(number)
+		comments, err := view.Comments(number)
+		if err != nil {
+			fmt.Printf("error on view comments: %v\n", err)
+			return
+		}
+
+		out := startPager(config, w, *noPager)
+		if *raw || !terminal.IsTerminal(w) {
+			err = issue.PrintIssue(out, issueData)
+			if err == nil {
+				err = issue.PrintComments(out, comments)
+			}
+		} else {
+			opts := markdownOptions()
+			err = issue.PrintIssueMarkdown(out, issueData, opts)
+			if err == nil {
+				err = issue.PrintCommentsMarkdown(out, comments, opts)
+			}
+		}
+		out.Close()
 		if err != nil {
 			fmt.Printf("error on print issue: %v\n", err)
 			return
@@ -130,6 +331,146 @@ func main() {
 		}
 		fmt.Println("issue closed successfully")
 
+	case "lock":
+		flags := flag.NewFlagSet("lock", flag.ContinueOnError)
+		reason := flags.String("reason", "", "lock reason: off-topic, too heated, resolved or spam")
+		args, err := parseFlags(flags, os.Args[2:])
+		if err != nil {
+			return
+		}
+		number, ok := issueNumber(args)
+		if !ok {
+			return
+		}
+		err = issue.NewLock(config, serviceClient).Lock(number, *reason)
+		if err != nil {
+			fmt.Printf("error on lock issue: %v\n", err)
+			return
+		}
+		fmt.Println("issue locked")
+
+	case "unlock":
+		number, ok := issueNumber(os.Args[2:])
+		if !ok {
+			return
+		}
+		err = issue.NewLock(config, serviceClient).Unlock(number)
+		if err != nil {
+			fmt.Printf("error on unlock issue: %v\n", err)
+			return
+		}
+		fmt.Println("issue unlocked")
+
+	case "pin":
+		number, ok := issueNumber(os.Args[2:])
+		if !ok {
+			return
+		}
+		err = issue.NewPin(config, serviceClient).Pin(number)
+		if err != nil {
+			fmt.Printf("error on pin issue: %v\n", err)
+			return
+		}
+		fmt.Println("issue pinned")
+
+	case "unpin":
+		number, ok := issueNumber(os.Args[2:])
+		if !ok {
+			return
+		}
+		err = issue.NewPin(config, serviceClient).Unpin(number)
+		if err != nil {
+			fmt.Printf("error on unpin issue: %v\n", err)
+			return
+		}
+		fmt.Println("issue unpinned")
+
+	case "history":
+		flags := flag.NewFlagSet("history", flag.ContinueOnError)
+		noPager := flags.Bool("no-pager", false, "do not pipe the output through a pager")
+		format := flags.String("format", issue.FormatText, "output format: text, json or csv")
+		args, err := parseFlags(flags, os.Args[2:])
+		if err != nil {
+			return
+		}
+		number, ok := issueNumber(args)
+		if !ok {
+			return
+		}
+		if err = issue.ValidFormat(*format); err != nil {
+			fmt.Println(err)
+			return
+		}
+		events, err := issue.NewHistory(config, serviceClient).History(number)
+		if err != nil {
+			fmt.Printf("error on issue history: %v\n", err)
+			return
+		}
+		out := startPager(config, w, *noPager || *format != issue.FormatText)
+		err = issue.PrintHistory(out, events, *format)
+		out.Close()
+		if err != nil {
+			fmt.Printf("error on print history: %v\n", err)
+			return
+		}
+
+	case "react":
+		runReact(config, serviceClient, os.Args[2:])
+
+	case "subissue":
+		runSubIssue(config, serviceClient, os.Args[2:])
+
+	case "progress":
+		runProgress(config, serviceClient, os.Args[2:])
+
+	case "start":
+		runStart(config, serviceClient, os.Args[2:])
+
+	case "current":
+		runCurrent(config, serviceClient)
+
+	case "commits":
+		runCommits(config, os.Args[2:])
+
+	case "verify-closes":
+		runVerifyCloses(config, os.Args[2:])
+
+	case "changelog":
+		runChangelog(config, serviceClient, os.Args[2:])
+
+	case "search":
+		runSearch(config, serviceClient, os.Args[2:], false)
+
+	case "query":
+		runSearch(config, serviceClient, os.Args[3:], true)
+
+	case "browse":
+		runBrowse(config, os.Args[2:])
+
+	case "transfer":
+		runTransfer(config, serviceClient, os.Args[2:])
+
+	case "watch":
+		runWatch(config, serviceClient, os.Args[2:])
+
+	case "export":
+		runExport(config, serviceClient, os.Args[2:])
+
+	case "import":
+		runImport(config, serviceClient, os.Args[2:])
+
+	case "project":
+		runProject(config, serviceClient, os.Args[2:])
+
+	case "tui":
+		view := issue.NewView(config, serviceClient)
+		closer := issue.NewClose(config, serviceClient)
+		err = tui.New(list, view, update, closer).Run()
+		if err != nil {
+			fmt.Printf("error on tui: %v\n", err)
+			return
+		}
+
 	default:
 		fmt.Printf("command not found: %s\n", command)
 		help.PrintHelp()
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"git-issues/domain"
+	"git-issues/features/migrate"
+	"git-issues/service/client"
+)
+
+const (
+	exportJSON     = "json"
+	exportMarkdown = "markdown"
+)
+
+func runExport(config *domain.Config, githubClient client.GitHubClient, args []string) {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", exportJSON, "archive format: json or markdown")
+	output := flags.String("output", "", "file for json, directory for markdown")
+	if _, err := parseFlags(flags, args); err != nil {
+		return
+	}
+	if *format != exportJSON && *format != exportMarkdown {
+		fmt.Printf("unknown format %q, expected json or markdown\n", *format)
+		return
+	}
+	if *format == exportMarkdown && *output == "" {
+		fmt.Println("please provide the directory to write with --output")
+		return
+	}
+
+	archive, err := migrate.New(config, githubClient).Export()
+	if err != nil {
+		fmt.Printf("error on export: %v\n", err)
+		return
+	}
+
+	if *format == exportMarkdown {
+		files, err := migrate.WriteMarkdown(*output, archive)
+		if err != nil {
+			fmt.Printf("error on export: %v\n", err)
+			return
+		}
+		fmt.Printf("exported %d issues to %s\n", len(files), *output)
+		return
+	}
+
+	if *output == "" {
+		if err = migrate.WriteArchive(os.Stdout, archive); err != nil {
+			fmt.Printf("error on export: %v\n", err)
+		}
+		return
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		fmt.Printf("error on export: %v\n", err)
+		return
+	}
+	defer file.Close()
+	if err = migrate.WriteArchive(file, archive); err != nil {
+		fmt.Printf("error on export: %v\n", err)
+		return
+	}
+	fmt.Printf("exported %d issues to %s\n", len(archive.Issues), *output)
+}
+
+func runImport(config *domain.Config, githubClient client.GitHubClient, args []string) {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	var labelMap, milestoneMap stringList
+	flags.Var(&labelMap, "label-map", "rename a label, old=new (repeatable)")
+	flags.Var(&milestoneMap, "milestone-map", "rename a milestone, old=new (repeatable)")
+	mappingFile := flags.String("mapping", "", "mapping file, <archive>.mapping.json if empty")
+	dryRun := flags.Bool("dry-run", false, "print what would be imported without changing anything")
+	args, err := parseFlags(flags, args)
+	if err != nil {
+		return
+	}
+	if len(args) < 1 {
+		fmt.Println("please provide the archive file or markdown directory")
+		return
+	}
+
+	options := migrate.ImportOptions{MappingFile: *mappingFile, DryRun: *dryRun}
+	if options.MappingFile == "" {
+		options.MappingFile = filepath.Clean(args[0]) + ".mapping.json"
+	}
+	if options.LabelMap, err = migrate.ParseMapping(labelMap); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if options.MilestoneMap, err = migrate.ParseMapping(milestoneMap); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	archive, err := readArchive(args[0])
+	if err != nil {
+		fmt.Printf("error on import: %v\n", err)
+		return
+	}
+
+	result, err := migrate.New(config, githubClient).Import(archive, options)
+	if result != nil {
+		verb := "created"
+		if *dryRun {
+			verb = "would create"
+		}
+		fmt.Printf("%s %d issues, %d comments, %d labels and %d milestones; skipped %d already imported\n",
+			verb, result.Created, result.Comments, result.LabelsCreated, result.MilestonesCreated, result.Skipped)
+	}
+	if err != nil {
+		fmt.Printf("error on import: %v\n", err)
+		fmt.Printf("run the same command again to resume, progress is kept in %s\n", options.MappingFile)
+	}
+}
+
+// readArchive reads a JSON archive, or the directory of an export to
+// markdown.
+func readArchive(path string) (*migrate.Archive, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return migrate.ReadMarkdown(path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return migrate.ReadArchive(file)
+}
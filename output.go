@@ -0,0 +1,28 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"git-issues/domain"
+	"git-issues/features/issue"
+	"git-issues/service/pager"
+	"git-issues/service/terminal"
+)
+
+// startPager pipes long output through the configured pager unless disabled.
+func startPager(config *domain.Config, w io.Writer, disabled bool) io.WriteCloser {
+	if disabled {
+		return pager.Passthrough(w)
+	}
+	return pager.New(config).Start(w)
+}
+
+// markdownOptions sizes the rendering to the terminal and honours NO_COLOR.
+func markdownOptions() issue.MarkdownOptions {
+	width, _ := terminal.Size()
+	return issue.MarkdownOptions{
+		Width: width,
+		Color: os.Getenv("NO_COLOR") == "" && !terminal.IsDumb(),
+	}
+}
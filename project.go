@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"git-issues/domain"
+	"git-issues/features/project"
+	"git-issues/service/client"
+)
+
+const strProjectUsage = `usage:
+  ghissues project list
+  ghissues project add <n> --project <name>
+  ghissues project set <n> [--project <name>] --field <Field=Value>
+  ghissues project items --project <name> [--filter <Field=Value>]...`
+
+func runProject(config *domain.Config, graphql client.GraphQLClient, args []string) {
+	if len(args) < 1 {
+		fmt.Println(strProjectUsage)
+		return
+	}
+
+	projects := project.New(config, graphql)
+	w := os.Stdout
+
+	flags := flag.NewFlagSet("project "+args[0], flag.ContinueOnError)
+	name := flags.String("project", "", "project title or number")
+	var fields, filters stringList
+	flags.Var(&fields, "field", "field to set as Field=Value, may be repeated")
+	flags.Var(&filters, "filter", "only items whose field matches, as Field=Value, may be repeated")
+	positional, err := parseFlags(flags, args[1:])
+	if err != nil {
+		return
+	}
+
+	switch args[0] {
+	case "list":
+		list, err := projects.List()
+		if err != nil {
+			fmt.Printf("error on list projects: %v\n", err)
+			return
+		}
+		if err = project.PrintProjects(w, list); err != nil {
+			fmt.Printf("error on print projects: %v\n", err)
+		}
+
+	case "add":
+		number, ok := issueNumber(positional)
+		if !ok {
+			return
+		}
+		if err = projects.Add(number, *name); err != nil {
+			fmt.Printf("error on add issue to project: %v\n", err)
+			return
+		}
+		fmt.Println("issue added to project")
+
+	case "set":
+		number, ok := issueNumber(positional)
+		if !ok {
+			return
+		}
+		if len(fields) == 0 {
+			fmt.Println("please provide a field with --field Field=Value")
+			return
+		}
+		for _, assignment := range fields {
+			field, value, err := project.ParseFieldValue(assignment)
+			if err == nil {
+				err = projects.Set(number, *name, field, value)
+			}
+			if err != nil {
+				fmt.Printf("error on set project field: %v\n", err)
+				return
+			}
+		}
+		fmt.Println("project fields updated")
+
+	case "items":
+		var parsed []project.Filter
+		for _, f := range filters {
+			field, value, err := project.ParseFieldValue(f)
+			if err != nil {
+				fmt.Printf("error on parse filter: %v\n", err)
+				return
+			}
+			parsed = append(parsed, project.Filter{Field: field, Value: value})
+		}
+		items, err := projects.Items(*name, parsed)
+		if err != nil {
+			fmt.Printf("error on list project items: %v\n", err)
+			return
+		}
+		if err = project.PrintItems(w, items); err != nil {
+			fmt.Printf("error on print project items: %v\n", err)
+		}
+
+	default:
+		fmt.Printf("unknown project command: %s\n", args[0])
+		fmt.Println(strProjectUsage)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"git-issues/domain"
+	"git-issues/features/conf"
+	"git-issues/features/issue"
+	"git-issues/service/client"
+)
+
+const strQueryUsage = `usage:
+  ghissues query save <name> '<search>'
+  ghissues query run <name> [--format text|json|csv] [--sort <order>] [--no-pager]
+  ghissues query list
+  ghissues query delete <name>`
+
+// runQuery handles the subcommands that only touch the config; run needs
+// the api and goes through runSearch.
+func runQuery(featureConfig *conf.Feature, args []string) {
+	if len(args) < 1 {
+		fmt.Println(strQueryUsage)
+		return
+	}
+
+	switch args[0] {
+	case "save":
+		if len(args) < 3 {
+			fmt.Println(strQueryUsage)
+			return
+		}
+		if err := featureConfig.SaveQuery(args[1], strings.Join(args[2:], " ")); err != nil {
+			fmt.Printf("error on save query: %v\n", err)
+			return
+		}
+		fmt.Printf("query %s saved\n", args[1])
+
+	case "list":
+		queries, names, err := featureConfig.Queries()
+		if err != nil {
+			fmt.Printf("error on list queries: %v\n", err)
+			return
+		}
+		for _, name := range names {
+			fmt.Printf("%s\t%s\n", name, queries[name])
+		}
+
+	case "delete":
+		if len(args) != 2 {
+			fmt.Println(strQueryUsage)
+			return
+		}
+		if err := featureConfig.DeleteQuery(args[1]); err != nil {
+			fmt.Printf("error on delete query: %v\n", err)
+			return
+		}
+		fmt.Printf("query %s deleted\n", args[1])
+
+	default:
+		fmt.Println(strQueryUsage)
+	}
+}
+
+// runSearch lists the issues matching the words of args, or the saved query
+// named by the first of them when saved is set.
+func runSearch(config *domain.Config, githubClient client.GitHubClient, args []string, saved bool) {
+	flags := flag.NewFlagSet("search", flag.ContinueOnError)
+	noPager := flags.Bool("no-pager", false, "do not pipe the output through a pager")
+	format := flags.String("format", issue.FormatText, "output format: text, json or csv")
+	sortBy := flags.String("sort", "", "order of the issues: "+strings.Join(issue.ListSorts, ", "))
+	args, err := parseFlags(flags, args)
+	if err != nil {
+		return
+	}
+	if err = issue.ValidFormat(*format); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if len(args) < 1 {
+		if saved {
+			fmt.Println(strQueryUsage)
+		} else {
+			fmt.Println("please provide a search query")
+		}
+		return
+	}
+
+	query := strings.Join(args, " ")
+	if saved {
+		if len(args) != 1 {
+			fmt.Println(strQueryUsage)
+			return
+		}
+		if query, err = conf.Query(config, args[0]); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
+	issues, err := issue.NewSearch(config, githubClient).Search(query)
+	if err != nil {
+		fmt.Printf("error on search issues: %v\n", err)
+		return
+	}
+	printIssueList(config, issues, *format, *sortBy, *noPager)
+}
+
+// printIssueList sorts and prints issues the way list does, through the
+// pager for text. The format is checked by the caller, before fetching.
+func printIssueList(config *domain.Config, issues []domain.Issue, format, sortBy string, noPager bool) {
+	if err := issue.SortIssues(issues, sortBy); err != nil {
+		fmt.Println(err)
+		return
+	}
+	out := startPager(config, os.Stdout, noPager || format != issue.FormatText)
+	err := issue.PrintIssuesFormat(out, issues, format)
+	out.Close()
+	if err != nil {
+		fmt.Printf("error on print issues: %v\n", err)
+	}
+}
+
+// searchText is the saved query called value, or value itself when no query
+// has that name, for the flags taking either.
+func searchText(config *domain.Config, value string) string {
+	if query, err := conf.Query(config, value); err == nil {
+		return query
+	}
+	return value
+}
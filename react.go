@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"git-issues/domain"
+	"git-issues/features/issue"
+	"git-issues/service/client"
+)
+
+var strReactUsage = `usage:
+  ghissues react <n> <reaction>
+  ghissues react --comment <id|url> <reaction>
+reactions: ` + strings.Join(issue.ReactionContents, ", ")
+
+// runReact reads its arguments by hand since the -1 reaction looks like a
+// flag to the flag package.
+func runReact(config *domain.Config, githubClient client.GitHubClient, args []string) {
+	var comment string
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--comment", "-comment":
+			if i+1 < len(args) {
+				comment = args[i+1]
+				i++
+			}
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+
+	react := issue.NewReact(config, githubClient)
+	var err error
+	switch {
+	case comment != "" && len(positional) == 1:
+		err = react.ReactComment(comment, positional[0])
+	case comment == "" && len(positional) == 2:
+		number, ok := issueNumber(positional)
+		if !ok {
+			return
+		}
+		err = react.React(number, positional[1])
+	default:
+		fmt.Println(strReactUsage)
+		return
+	}
+	if err != nil {
+		fmt.Printf("error on react: %v\n", err)
+		return
+	}
+	fmt.Println("reaction added")
+}
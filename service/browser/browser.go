@@ -0,0 +1,89 @@
+package browser
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+var (
+	errOpen = errors.New("could not open the browser, set $BROWSER")
+	goos    = runtime.GOOS
+)
+
+type Browser interface {
+	Open(url string) error
+}
+
+type Service struct {
+	// exec starts a command without waiting for it, as a browser may run
+	// until it is closed.
+	exec func(name string, args ...string) error
+}
+
+func New() *Service {
+	return &Service{
+		exec: start,
+	}
+}
+
+// Open shows the url in the browser of $BROWSER, a list of commands separated
+// like PATH where %s stands for the url, tried in order. Without it the
+// default opener of the OS is used: xdg-open, open on macOS or the url
+// handler on Windows.
+func (s *Service) Open(url string) error {
+	var errs []error
+	for _, command := range s.commands(url) {
+		err := s.exec(command[0], command[1:]...)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(append([]error{errOpen}, errs...)...)
+}
+
+func (s *Service) commands(url string) [][]string {
+	var commands [][]string
+	for _, entry := range filepath.SplitList(os.Getenv("BROWSER")) {
+		args := strings.Fields(entry)
+		if len(args) == 0 {
+			continue
+		}
+		replaced := false
+		for i, arg := range args {
+			if strings.Contains(arg, "%s") {
+				args[i] = strings.ReplaceAll(arg, "%s", url)
+				replaced = true
+			}
+		}
+		if !replaced {
+			args = append(args, url)
+		}
+		commands = append(commands, args)
+	}
+	if len(commands) > 0 {
+		return commands
+	}
+
+	// Default openers per OS
+	switch goos {
+	case "darwin":
+		return [][]string{{"open", url}}
+	case "windows":
+		return [][]string{{"rundll32", "url.dll,FileProtocolHandler", url}}
+	}
+	return [][]string{{"xdg-open", url}}
+}
+
+func start(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// the browser is left running; Release frees the process handle
+	return cmd.Process.Release()
+}
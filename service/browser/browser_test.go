@@ -0,0 +1,94 @@
+package browser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	const url = "https://github.com/owner/repo/issues/12"
+
+	tests := []struct {
+		name    string
+		env     string
+		goos    string
+		fail    map[string]bool
+		want    [][]string
+		wantErr bool
+	}{
+		{
+			name: "xdg-open on linux",
+			goos: "linux",
+			want: [][]string{{"xdg-open", url}},
+		},
+		{
+			name: "open on macOS",
+			goos: "darwin",
+			want: [][]string{{"open", url}},
+		},
+		{
+			name: "url handler on windows",
+			goos: "windows",
+			want: [][]string{{"rundll32", "url.dll,FileProtocolHandler", url}},
+		},
+		{
+			name: "BROWSER gets the url appended",
+			env:  "firefox --new-tab",
+			goos: "linux",
+			want: [][]string{{"firefox", "--new-tab", url}},
+		},
+		{
+			name: "BROWSER with %s",
+			env:  "lynx -dump %s",
+			goos: "linux",
+			want: [][]string{{"lynx", "-dump", url}},
+		},
+		{
+			name: "BROWSER commands are tried in order",
+			env:  "missing:w3m",
+			goos: "linux",
+			fail: map[string]bool{"missing": true},
+			want: [][]string{{"missing", url}, {"w3m", url}},
+		},
+		{
+			name:    "every command fails",
+			goos:    "linux",
+			fail:    map[string]bool{"xdg-open": true},
+			want:    [][]string{{"xdg-open", url}},
+			wantErr: true,
+		},
+	}
+
+	defaultGOOS := goos
+	defer func() { goos = defaultGOOS }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BROWSER", tt.env)
+			goos = tt.goos
+
+			var got [][]string
+			s := New()
+			s.exec = func(name string, args ...string) error {
+				got = append(got, append([]string{name}, args...))
+				if tt.fail[name] {
+					return errors.New("executable file not found in $PATH")
+				}
+				return nil
+			}
+
+			err := s.Open(url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && (!errors.Is(err, errOpen) || !strings.Contains(err.Error(), "not found")) {
+				t.Errorf("got %v, want errOpen with the cause", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
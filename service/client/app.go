@@ -0,0 +1,237 @@
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"git-issues/domain"
+)
+
+const (
+	// jwtLifetime stays below the ten minutes GitHub accepts, and jwtBackdate
+	// covers a clock running slightly ahead of GitHub's.
+	jwtLifetime = 9 * time.Minute
+	jwtBackdate = time.Minute
+	// tokenRefreshMargin renews an installation token before it expires, so
+	// a request never starts with a token about to lapse.
+	tokenRefreshMargin = time.Minute
+)
+
+var (
+	errAppKey   = errors.New("could not read the GitHub App private key")
+	errAppToken = errors.New("could not get a GitHub App installation token")
+)
+
+// TokenSource provides the token each request authenticates with.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+type staticToken string
+
+func (t staticToken) Token() (string, error) {
+	return string(t), nil
+}
+
+// AppTokenSource authenticates as a GitHub App installation. It signs a JWT
+// with the private key of the app, exchanges it for an installation token
+// and reuses that token until shortly before it expires, across runs through
+// cacheFile.
+type AppTokenSource struct {
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	apiBaseURL     string
+	httpClient     *http.Client
+	now            func() time.Time
+	readFile       func(name string) ([]byte, error)
+	writeFile      func(name string, data []byte, perm os.FileMode) error
+	// cacheFile keeps the installation token, none when empty.
+	cacheFile string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+type installationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// APIBaseURL ties a cached token to its host.
+	APIBaseURL string `json:"api_base_url,omitempty"`
+}
+
+// NewAppTokenSource reads the PEM private key of the app named by the config.
+func NewAppTokenSource(config *domain.Config, httpClient *http.Client) (*AppTokenSource, error) {
+	data, err := os.ReadFile(config.AppPrivateKey)
+	if err != nil {
+		return nil, errors.Join(errAppKey, err)
+	}
+	key, err := parseRSAKey(data)
+	if err != nil {
+		return nil, errors.Join(errAppKey, err)
+	}
+
+	cacheFile := ""
+	if dir, err := os.UserCacheDir(); err == nil {
+		cacheFile = filepath.Join(dir, "ghissues", fmt.Sprintf("app_%d_%d.json", config.AppID, config.AppInstallationID))
+	}
+
+	return &AppTokenSource{
+		appID:          config.AppID,
+		installationID: config.AppInstallationID,
+		key:            key,
+		apiBaseURL:     strings.TrimSuffix(config.APIBaseURL, "/"),
+		httpClient:     httpClient,
+		now:            time.Now,
+		readFile:       os.ReadFile,
+		writeFile:      os.WriteFile,
+		cacheFile:      cacheFile,
+	}, nil
+}
+
+// Token returns the cached installation token or requests a new one.
+func (s *AppTokenSource) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.valid() {
+		return s.token, nil
+	}
+	if s.load(); s.valid() {
+		return s.token, nil
+	}
+
+	jwt, err := s.jwt()
+	if err != nil {
+		return "", errors.Join(errAppToken, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", s.apiBaseURL, s.installationID)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return "", errors.Join(errAppToken, domain.ErrCreateRequest, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", errors.Join(errAppToken, domain.ErrRequest, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Join(errAppToken, domain.ErrRequest, err)
+	}
+	if resp.StatusCode >= 400 {
+		return "", errors.Join(errAppToken, domain.ErrApi, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body))))
+	}
+
+	var token installationToken
+	if err = json.Unmarshal(body, &token); err != nil || token.Token == "" {
+		return "", errors.Join(errAppToken, domain.ErrEncoding)
+	}
+	s.token, s.expires = token.Token, token.ExpiresAt
+	// a token that could not be saved is requested again on the next run
+	s.save()
+	return s.token, nil
+}
+
+func (s *AppTokenSource) valid() bool {
+	return s.token != "" && s.now().Add(tokenRefreshMargin).Before(s.expires)
+}
+
+// load takes the token of the cache file, when it was issued by the same host.
+func (s *AppTokenSource) load() {
+	if s.cacheFile == "" {
+		return
+	}
+	data, err := s.readFile(s.cacheFile)
+	if err != nil {
+		return
+	}
+	var token installationToken
+	if json.Unmarshal(data, &token) != nil || token.APIBaseURL != s.apiBaseURL {
+		return
+	}
+	s.token, s.expires = token.Token, token.ExpiresAt
+}
+
+func (s *AppTokenSource) save() {
+	if s.cacheFile == "" {
+		return
+	}
+	data, err := json.Marshal(installationToken{Token: s.token, ExpiresAt: s.expires, APIBaseURL: s.apiBaseURL})
+	if err != nil || os.MkdirAll(filepath.Dir(s.cacheFile), 0700) != nil {
+		return
+	}
+	_ = s.writeFile(s.cacheFile, data, 0600)
+}
+
+// jwt builds the RS256 token that authenticates as the app itself.
+func (s *AppTokenSource) jwt() (string, error) {
+	now := s.now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-jwtBackdate).Unix(),
+		"exp": now.Add(jwtLifetime).Unix(),
+		"iss": strconv.FormatInt(s.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString(header) + "." + encoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + encoding.EncodeToString(signature), nil
+}
+
+// parseRSAKey accepts the PKCS#1 keys GitHub generates and PKCS#8 ones.
+func parseRSAKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("the private key is not an RSA key")
+	}
+	return key, nil
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git-issues/domain"
+)
+
+// writeAppKey stores a new RSA key as PKCS#1 PEM, the format GitHub hands out.
+func writeAppKey(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "app.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err = os.WriteFile(file, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return key, file
+}
+
+// verifyJWT checks the RS256 signature and returns the claims.
+func verifyJWT(t *testing.T, key *rsa.PublicKey, jwt string) map[string]interface{} {
+	t.Helper()
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("malformed jwt %q", jwt)
+	}
+	header, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	if string(header) != `{"alg":"RS256","typ":"JWT"}` {
+		t.Errorf("unexpected header %s", header)
+	}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		t.Errorf("invalid signature: %v", err)
+	}
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	claims := map[string]interface{}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("invalid claims: %v", err)
+	}
+	return claims
+}
+
+func TestAppTokenSource(t *testing.T) {
+	key, keyFile := writeAppKey(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	exchanges := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app/installations/34/access_tokens":
+			exchanges++
+			if r.Method != http.MethodPost {
+				t.Errorf("unexpected method %s", r.Method)
+			}
+			claims := verifyJWT(t, &key.PublicKey, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+			if claims["iss"] != "12" || claims["iat"] != float64(now.Add(-time.Minute).Unix()) || claims["exp"] != float64(now.Add(9*time.Minute).Unix()) {
+				t.Errorf("unexpected claims %v", claims)
+			}
+			token := map[string]interface{}{"token": "ghs_" + string(rune('0'+exchanges)), "expires_at": now.Add(time.Hour)}
+			json.NewEncoder(w).Encode(token)
+		case "/repos/o/r/issues":
+			w.Write([]byte(r.Header.Get("Authorization")))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	config := &domain.Config{APIBaseURL: server.URL, AppID: 12, AppInstallationID: 34, AppPrivateKey: keyFile}
+	cacheFile := filepath.Join(t.TempDir(), "ghissues", "app_12_34.json")
+	newSource := func() (*Service, *AppTokenSource) {
+		service := New(config)
+		source := service.tokens.(*AppTokenSource)
+		source.now = func() time.Time { return now }
+		source.cacheFile = cacheFile
+		return service, source
+	}
+	service, source := newSource()
+
+	got, err := service.MakeRequest(http.MethodGet, server.URL+"/repos/o/r/issues", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != "token ghs_1" {
+		t.Errorf("request authenticated with %q", got)
+	}
+
+	info, err := os.Stat(cacheFile)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("expected the token saved with mode 0600, got %v %v", info, err)
+	}
+
+	// reused by the next run
+	_, next := newSource()
+	if token, _ := next.Token(); token != "ghs_1" || exchanges != 1 {
+		t.Errorf("expected the saved token, got %q after %d exchanges", token, exchanges)
+	}
+
+	// reused while valid
+	now = now.Add(58 * time.Minute)
+	if token, _ := source.Token(); token != "ghs_1" || exchanges != 1 {
+		t.Errorf("expected the cached token, got %q after %d exchanges", token, exchanges)
+	}
+
+	// renewed shortly before expiry, also by the next run
+	now = now.Add(90 * time.Second)
+	_, next = newSource()
+	if token, _ := next.Token(); token != "ghs_2" || exchanges != 2 {
+		t.Errorf("expected a new token, got %q after %d exchanges", token, exchanges)
+	}
+	if token, _ := source.Token(); token != "ghs_2" || exchanges != 2 {
+		t.Errorf("expected the token of the other run, got %q after %d exchanges", token, exchanges)
+	}
+
+	// a token of another host is not used
+	config.APIBaseURL = server.URL + "/other"
+	_, other := newSource()
+	if other.load(); other.token != "" {
+		t.Errorf("got the token of another host %q", other.token)
+	}
+}
+
+func TestAppTokenSourceErrors(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	_, keyFile := writeAppKey(t)
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "key.txt")
+	os.WriteFile(notPEM, []byte("not a key"), 0600)
+
+	_, ecKey := newTestCert(t, "ec", nil, 0).writePEM(t, dir, "ec")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"A JSON web token could not be decoded"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		keyFile string
+		wantErr []error
+	}{
+		{name: "missing key", keyFile: filepath.Join(dir, "missing.pem"), wantErr: []error{errAppKey}},
+		{name: "not pem", keyFile: notPEM, wantErr: []error{errAppKey}},
+		{name: "not rsa", keyFile: ecKey, wantErr: []error{errAppKey}},
+		{name: "rejected jwt", keyFile: keyFile, wantErr: []error{errAppToken, domain.ErrApi}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &domain.Config{APIBaseURL: server.URL, AppID: 1, AppInstallationID: 2, AppPrivateKey: tt.keyFile}
+			_, err := New(config).MakeRequest(http.MethodGet, server.URL+"/repos/o/r/issues", nil)
+			for _, want := range tt.wantErr {
+				if !errors.Is(err, want) {
+					t.Errorf("expected %v, got %v", want, err)
+				}
+			}
+		})
+	}
+}
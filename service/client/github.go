@@ -8,80 +8,277 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"git-issues/domain"
 )
 
+const (
+	maxAttempts    = 3
+	maxRetryDelay  = time.Minute
+	requestTimeout = 30 * time.Second
+)
+
 var (
 	errStr = "error on MakeRequest: %s"
+	sleep  = time.Sleep
+	now    = time.Now
 )
 
 type GitHubClient interface {
 	MakeRequest(method, url string, data *domain.Issue) ([]byte, error)
+	MakeJSONRequest(method, url string, payload interface{}) ([]byte, error)
+}
+
+// HeaderClient also returns the response headers, for the information GitHub
+// only sends there, like the scopes of the token.
+type HeaderClient interface {
+	MakeRequestHeader(method, url string) ([]byte, http.Header, error)
+}
+
+// ConditionalClient sends conditional GETs: etag goes in If-None-Match and a
+// 304 answer comes back as not modified, without body. GitHub does not count
+// those against the rate limit.
+type ConditionalClient interface {
+	MakeConditionalRequest(url, etag string) (body []byte, header http.Header, modified bool, err error)
 }
 
 type Service struct {
-	config *domain.Config
+	config     *domain.Config
+	httpClient *http.Client
+	tokens     TokenSource
+	// setupErr keeps a broken CA, client certificate or app key setting,
+	// reported by every request.
+	setupErr error
+	timeout  time.Duration
+	attempts int
 }
 
+// New authenticates with the token of the config, or as a GitHub App
+// installation when the config names an app.
 func New(config *domain.Config) *Service {
-	return &Service{
-		config: config,
+	httpClient, err := NewHTTPClient(config)
+	s := &Service{
+		config:     config,
+		httpClient: httpClient,
+		tokens:     staticToken(config.Token),
+		setupErr:   err,
+		timeout:    requestTimeout,
+		attempts:   maxAttempts,
 	}
+	if config.AppID != 0 && err == nil {
+		s.tokens, s.setupErr = NewAppTokenSource(config, httpClient)
+	}
+	return s
+}
+
+// NewWithTimeout is New for the requests a user waits on, like shell
+// completion: they give up after timeout, app token included, and are never
+// retried.
+func NewWithTimeout(config *domain.Config, timeout time.Duration) *Service {
+	s := New(config)
+	s.httpClient.Timeout = timeout
+	s.timeout = timeout
+	s.attempts = 1
+	return s
 }
 
 func (s *Service) MakeRequest(method, url string, data *domain.Issue) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	if data == nil {
+		return s.MakeJSONRequest(method, url, nil)
+	}
+	return s.MakeJSONRequest(method, url, newIssuePayload(data))
+}
 
+// MakeJSONRequest sends payload encoded as JSON, for the endpoints that do not
+// take an issue.
+func (s *Service) MakeJSONRequest(method, url string, payload interface{}) ([]byte, error) {
 	var reqBody []byte
-	if data != nil {
+	if payload != nil {
 		var err error
-		reqBody, err = json.Marshal(data)
+		reqBody, err = json.Marshal(payload)
 		if err != nil {
 			err = fmt.Errorf(errStr, err)
 			return nil, errors.Join(err, domain.ErrEncoding)
 		}
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(reqBody))
+	_, _, body, err := s.send(method, url, reqBody, nil, isIdempotent(method))
+	return body, err
+}
+
+// MakeRequestHeader sends a request without body and returns the response
+// headers with the body.
+func (s *Service) MakeRequestHeader(method, url string) ([]byte, http.Header, error) {
+	_, header, body, err := s.send(method, url, nil, nil, isIdempotent(method))
+	return body, header, err
+}
+
+// MakeConditionalRequest gets url unless it still matches etag. An empty etag
+// always gets it.
+func (s *Service) MakeConditionalRequest(url, etag string) ([]byte, http.Header, bool, error) {
+	var extra http.Header
+	if etag != "" {
+		extra = http.Header{"If-None-Match": {etag}}
+	}
+	status, header, body, err := s.send("GET", url, nil, extra, true)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if status == http.StatusNotModified {
+		return nil, header, false, nil
+	}
+	return body, header, true, nil
+}
+
+// send authenticates the request and retries it while GitHub answers with a
+// transient error. Both the REST and the GraphQL clients go through it; extra
+// holds headers of the request besides the authentication. Requests that are
+// not idempotent are only sent again after a rate limit, as a server error
+// may come after the change was made.
+func (s *Service) send(method, url string, reqBody []byte, extra http.Header, idempotent bool) (int, http.Header, []byte, error) {
+	for attempt := 1; ; attempt++ {
+		status, header, body, err := s.do(method, url, reqBody, extra)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+
+		if isRetryable(status, header, idempotent) && attempt < s.attempts {
+			if delay, ok := retryDelay(header, attempt); ok {
+				sleep(delay)
+				continue
+			}
+		}
+
+		if status >= 400 {
+			var errorResponse struct {
+				Message string `json:"message"`
+			}
+			err := json.Unmarshal(body, &errorResponse)
+			if err != nil {
+				err = fmt.Errorf(errStr, err)
+				return 0, nil, nil, errors.Join(err, domain.ErrRequest)
+			}
+			err = fmt.Errorf("GitHub api error Status:%d\n response error: %s", status, errorResponse.Message)
+			return 0, nil, nil, errors.Join(err, domain.ErrApi)
+		}
+
+		return status, header, body, nil
+	}
+}
+
+func (s *Service) do(method, url string, reqBody []byte, extra http.Header) (int, http.Header, []byte, error) {
+	if s.setupErr != nil {
+		return 0, nil, nil, s.setupErr
+	}
+	token, err := s.tokens.Token()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(reqBody))
 	if err != nil {
 		err = fmt.Errorf(errStr, err)
-		return nil, errors.Join(err, domain.ErrCreateRequest)
+		return 0, nil, nil, errors.Join(err, domain.ErrCreateRequest)
 	}
 
-	req.Header.Set("Authorization", "token "+s.config.Token)
+	req.Header.Set("Authorization", "token "+token)
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	if data != nil {
+	for key, values := range extra {
+		req.Header[key] = values
+	}
+	if reqBody != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := s.httpClient.Do(req)
 	if err != nil {
 		err = fmt.Errorf(errStr, err)
-		return nil, errors.Join(domain.ErrRequest, err)
+		return 0, nil, nil, errors.Join(domain.ErrRequest, err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf(errStr, err)
+		return 0, nil, nil, fmt.Errorf(errStr, err)
+	}
+
+	return resp.StatusCode, resp.Header, body, nil
+}
+
+// isIdempotent tells whether sending a request with method twice has the
+// effect of sending it once.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
 	}
+	return false
+}
 
-	if resp.StatusCode >= 400 {
-		var errorResponse struct {
-			Message string `json:"message"`
+// isRetryable tells whether a failed request may be sent again: idempotent
+// ones after a transient error, any after a rate limit, which GitHub answers
+// with 429 or 403 and Retry-After or no requests remaining.
+func isRetryable(status int, header http.Header, idempotent bool) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusForbidden:
+		if header.Get("Retry-After") != "" || header.Get("X-RateLimit-Remaining") == "0" {
+			return true
 		}
-		err := json.Unmarshal(body, &errorResponse)
+	}
+	if !idempotent {
+		return false
+	}
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay honours Retry-After, then the reset of an exhausted rate limit,
+// and otherwise backs off exponentially. A reset further than maxRetryDelay
+// is not waited for.
+func retryDelay(header http.Header, attempt int) (time.Duration, bool) {
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds >= 0 {
+		return min(time.Duration(seconds)*time.Second, maxRetryDelay), true
+	}
+	if header.Get("X-RateLimit-Remaining") == "0" {
+		reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
 		if err != nil {
-			err = fmt.Errorf(errStr, err)
-			return nil, errors.Join(err, domain.ErrRequest)
+			return 0, false
 		}
-		err = fmt.Errorf("GitHub api error Status:%d\n response error: %s", resp.StatusCode, errorResponse.Message)
-		return nil, errors.Join(err, domain.ErrApi)
+		delay := max(time.Unix(reset, 0).Sub(now()), 0)
+		return delay, delay <= maxRetryDelay
 	}
+	return time.Duration(1<<(attempt-1)) * time.Second, true
+}
+
+// issuePayload is the writable subset of an issue. The API returns labels
+// and assignees as objects but expects plain names when they are sent back.
+type issuePayload struct {
+	Title     string   `json:"title"`
+	Body      string   `json:"body,omitempty"`
+	State     string   `json:"state,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+}
 
-	return body, nil
+func newIssuePayload(issue *domain.Issue) issuePayload {
+	payload := issuePayload{
+		Title: issue.Title,
+		Body:  issue.Body,
+		State: issue.State,
+	}
+	for _, l := range issue.Labels {
+		payload.Labels = append(payload.Labels, l.Name)
+	}
+	for _, u := range issue.Assignees {
+		payload.Assignees = append(payload.Assignees, u.Login)
+	}
+	return payload
 }
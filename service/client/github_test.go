@@ -2,11 +2,14 @@ package client
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"git-issues/domain"
 )
@@ -51,6 +54,133 @@ func TestMakeGitHubRequest_Success(t *testing.T) {
 	}
 }
 
+func TestMakeGitHubRequest_IssuePayload(t *testing.T) {
+	// Arrange
+	var got map[string]interface{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	service := New(defaultConfig)
+
+	data := domain.Issue{
+		Number:    7,
+		Title:     "title",
+		State:     "open",
+		Labels:    []domain.Label{{Name: "bug"}},
+		Assignees: []domain.User{{Login: "octocat"}},
+	}
+
+	// Act
+	_, err := service.MakeRequest(http.MethodPatch, server.URL, &data)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := got["number"]; ok {
+		t.Errorf("number should not be sent, got %v", got)
+	}
+	labels, ok := got["labels"].([]interface{})
+	if !ok || len(labels) != 1 || labels[0] != "bug" {
+		t.Errorf("unexpected labels: %v", got["labels"])
+	}
+	assignees, ok := got["assignees"].([]interface{})
+	if !ok || len(assignees) != 1 || assignees[0] != "octocat" {
+		t.Errorf("unexpected assignees: %v", got["assignees"])
+	}
+}
+
+func TestMakeJSONRequest(t *testing.T) {
+	// Arrange
+	var gotBody string
+	var gotContentType string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		gotContentType = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	service := New(defaultConfig)
+
+	// Act
+	_, err := service.MakeJSONRequest(http.MethodPut, server.URL, map[string]string{"lock_reason": "spam"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotBody != `{"lock_reason":"spam"}` {
+		t.Errorf("unexpected body: %q", gotBody)
+	}
+	if gotContentType != "application/json" {
+		t.Errorf("unexpected content type: %q", gotContentType)
+	}
+
+	// Act: no payload sends no body
+	_, err = service.MakeJSONRequest(http.MethodDelete, server.URL, nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotBody != "" || gotContentType != "" {
+		t.Errorf("unexpected body %q with content type %q", gotBody, gotContentType)
+	}
+}
+
+func TestMakeConditionalRequest(t *testing.T) {
+	// Arrange
+	var gotMatch []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMatch = append(gotMatch, r.Header.Get("If-None-Match"))
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("X-Poll-Interval", "60")
+		if r.Header.Get("If-None-Match") == `"abc"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id":"1"}]`))
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	service := New(defaultConfig)
+
+	// Act: the first request has no etag
+	body, header, modified, err := service.MakeConditionalRequest(server.URL, "")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !modified || string(body) != `[{"id":"1"}]` || header.Get("ETag") != `"abc"` {
+		t.Errorf("got %q, etag %q, modified %v", body, header.Get("ETag"), modified)
+	}
+
+	// Act: the etag makes the server answer 304
+	body, header, modified, err = service.MakeConditionalRequest(server.URL, header.Get("ETag"))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if modified || body != nil || header.Get("X-Poll-Interval") != "60" {
+		t.Errorf("got %q, poll interval %q, modified %v", body, header.Get("X-Poll-Interval"), modified)
+	}
+	if len(gotMatch) != 2 || gotMatch[0] != "" || gotMatch[1] != `"abc"` {
+		t.Errorf("unexpected If-None-Match headers: %q", gotMatch)
+	}
+}
+
 func TestMakeGitHubRequest_Errors(t *testing.T) {
 	// Arrange
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -109,6 +239,153 @@ func TestMakeGitHubRequest_Errors(t *testing.T) {
 
 }
 
+func TestMakeGitHubRequest_Retry(t *testing.T) {
+	originalSleep, originalNow := sleep, now
+	t.Cleanup(func() { sleep, now = originalSleep, originalNow })
+	now = func() time.Time { return time.Unix(1000, 0) }
+
+	tests := []struct {
+		name       string
+		method     string
+		statuses   []int
+		header     map[string]string
+		wantCalls  int
+		wantDelays []time.Duration
+		wantErr    error
+	}{
+		{
+			name:       "retries transient errors",
+			method:     http.MethodPut,
+			statuses:   []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			wantCalls:  3,
+			wantDelays: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:       "honours retry after",
+			method:     http.MethodPost,
+			statuses:   []int{http.StatusTooManyRequests, http.StatusOK},
+			header:     map[string]string{"Retry-After": "5"},
+			wantCalls:  2,
+			wantDelays: []time.Duration{5 * time.Second},
+		},
+		{
+			name:       "gives up after max attempts",
+			method:     http.MethodDelete,
+			statuses:   []int{http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusGatewayTimeout},
+			wantCalls:  3,
+			wantDelays: []time.Duration{time.Second, 2 * time.Second},
+			wantErr:    domain.ErrApi,
+		},
+		{
+			name:      "does not retry client errors",
+			method:    http.MethodPut,
+			statuses:  []int{http.StatusNotFound},
+			wantCalls: 1,
+			wantErr:   domain.ErrApi,
+		},
+		{
+			name:      "does not resend a post after a server error",
+			method:    http.MethodPost,
+			statuses:  []int{http.StatusGatewayTimeout},
+			wantCalls: 1,
+			wantErr:   domain.ErrApi,
+		},
+		{
+			name:      "does not resend a patch after a bare 429",
+			method:    http.MethodPatch,
+			statuses:  []int{http.StatusTooManyRequests},
+			wantCalls: 1,
+			wantErr:   domain.ErrApi,
+		},
+		{
+			name:       "waits for the rate limit reset",
+			method:     http.MethodPost,
+			statuses:   []int{http.StatusForbidden, http.StatusCreated},
+			header:     map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1030"},
+			wantCalls:  2,
+			wantDelays: []time.Duration{30 * time.Second},
+		},
+		{
+			name:      "does not wait for a distant reset",
+			method:    http.MethodPost,
+			statuses:  []int{http.StatusForbidden},
+			header:    map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "4600"},
+			wantCalls: 1,
+			wantErr:   domain.ErrApi,
+		},
+		{
+			name:      "does not retry a forbidden request",
+			method:    http.MethodGet,
+			statuses:  []int{http.StatusForbidden},
+			wantCalls: 1,
+			wantErr:   domain.ErrApi,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var delays []time.Duration
+			sleep = func(d time.Duration) { delays = append(delays, d) }
+
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != `{"title":"t"}` {
+					t.Errorf("body not resent on retry: %q", body)
+				}
+				for key, value := range tt.header {
+					w.Header().Set(key, value)
+				}
+				w.WriteHeader(tt.statuses[calls])
+				calls++
+				_, _ = w.Write([]byte(`{"message":"msg"}`))
+			}))
+			defer server.Close()
+
+			_, err := New(defaultConfig).MakeRequest(tt.method, server.URL, &domain.Issue{Title: "t"})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error: got %v want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("unexpected calls: got %d want %d", calls, tt.wantCalls)
+			}
+			if len(delays) != len(tt.wantDelays) {
+				t.Fatalf("unexpected delays: got %v want %v", delays, tt.wantDelays)
+			}
+			for i := range delays {
+				if delays[i] != tt.wantDelays[i] {
+					t.Errorf("unexpected delays: got %v want %v", delays, tt.wantDelays)
+				}
+			}
+		})
+	}
+}
+
+func TestNewWithTimeout(t *testing.T) {
+	sleep = func(time.Duration) { t.Error("unexpected retry") }
+	defer func() { sleep = time.Sleep }()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"message":"unavailable"}`))
+	}))
+	defer server.Close()
+	service := NewWithTimeout(defaultConfig, 50*time.Millisecond)
+
+	if _, err := service.MakeRequest("GET", server.URL, nil); !errors.Is(err, domain.ErrApi) || calls != 1 {
+		t.Errorf("got %v after %d calls, want ErrApi after 1", err, calls)
+	}
+	if _, err := service.MakeRequest("GET", server.URL+"/slow", nil); !errors.Is(err, domain.ErrRequest) {
+		t.Errorf("got %v, want ErrRequest on timeout", err)
+	}
+}
+
 func TestMakeGitHubRequest_CreateRequestError(t *testing.T) {
 	service := New(defaultConfig)
 
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"git-issues/domain"
+	"git-issues/service/host"
+)
+
+// GraphQLClient runs queries and mutations against the GraphQL api, for the
+// features REST does not offer.
+type GraphQLClient interface {
+	Query(query string, variables map[string]interface{}, result interface{}) error
+}
+
+// PageInfo is the pagination object of a GraphQL connection.
+type PageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+	Type    string `json:"type,omitempty"`
+}
+
+// GraphQLURL returns the GraphQL endpoint of the host of the REST api:
+// /graphql next to it on github.com, /api/graphql on Enterprise Server.
+func GraphQLURL(apiBaseURL string) string {
+	return host.FromAPIBaseURL(apiBaseURL).GraphQL
+}
+
+// Query posts query with its variables and decodes the data object into
+// result. The errors array of the response is turned into a single error.
+func (s *Service) Query(query string, variables map[string]interface{}, result interface{}) error {
+	reqBody, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return errors.Join(fmt.Errorf(errStr, err), domain.ErrEncoding)
+	}
+
+	// queries only read, so they retry like a GET
+	idempotent := !strings.HasPrefix(strings.TrimSpace(query), "mutation")
+	_, _, response, err := s.send("POST", GraphQLURL(s.config.APIBaseURL), reqBody, nil, idempotent)
+	if err != nil {
+		return err
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if err = json.Unmarshal(response, &envelope); err != nil {
+		return errors.Join(fmt.Errorf(errStr, err), domain.ErrEncoding)
+	}
+
+	if len(envelope.Errors) > 0 {
+		messages := make([]string, 0, len(envelope.Errors))
+		for _, e := range envelope.Errors {
+			messages = append(messages, e.Message)
+		}
+		err = fmt.Errorf("GitHub graphql error: %s", strings.Join(messages, "; "))
+		return errors.Join(err, domain.ErrGraphQL)
+	}
+
+	if result == nil || len(envelope.Data) == 0 || string(envelope.Data) == "null" {
+		return nil
+	}
+	if err = json.Unmarshal(envelope.Data, result); err != nil {
+		return errors.Join(fmt.Errorf(errStr, err), domain.ErrEncoding)
+	}
+	return nil
+}
+
+// Paginate runs query once per page. The query must declare a $cursor
+// variable; page decodes each data object and returns the page info of the
+// connection being walked.
+func Paginate(c GraphQLClient, query string, variables map[string]interface{}, page func(data json.RawMessage) (PageInfo, error)) error {
+	vars := make(map[string]interface{}, len(variables)+1)
+	for k, v := range variables {
+		vars[k] = v
+	}
+	vars["cursor"] = nil
+
+	for {
+		var data json.RawMessage
+		if err := c.Query(query, vars, &data); err != nil {
+			return err
+		}
+
+		info, err := page(data)
+		if err != nil {
+			return err
+		}
+		if !info.HasNextPage || info.EndCursor == "" {
+			return nil
+		}
+		vars["cursor"] = info.EndCursor
+	}
+}
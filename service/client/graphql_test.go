@@ -0,0 +1,203 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/testdata/stubs"
+)
+
+func TestGraphQLURL(t *testing.T) {
+	tests := map[string]string{
+		"https://api.github.com":  "https://api.github.com/graphql",
+		"https://api.github.com/": "https://api.github.com/graphql",
+
+		"https://ghe.example.com/api/v3": "https://ghe.example.com/api/graphql",
+	}
+	for in, want := range tests {
+		if got := GraphQLURL(in); got != want {
+			t.Errorf("GraphQLURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGraphQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		status   int
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "decodes data",
+			response: `{"data":{"viewer":{"login":"octocat"}}}`,
+			status:   http.StatusOK,
+			want:     "octocat",
+		},
+		{
+			name:     "errors array",
+			response: `{"data":null,"errors":[{"message":"first"},{"message":"second"}]}`,
+			status:   http.StatusOK,
+			wantErr:  domain.ErrGraphQL,
+		},
+		{
+			name:     "invalid json",
+			response: `{`,
+			status:   http.StatusOK,
+			wantErr:  domain.ErrEncoding,
+		},
+		{
+			name:     "http error",
+			response: `{"message":"Bad credentials"}`,
+			status:   http.StatusUnauthorized,
+			wantErr:  domain.ErrApi,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got graphQLRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if r.Header.Get("Authorization") != "token mockToken" {
+					t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+				}
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("failed to decode request: %v", err)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			var result struct {
+				Viewer struct {
+					Login string `json:"login"`
+				} `json:"viewer"`
+			}
+			service := New(&domain.Config{Token: "mockToken", APIBaseURL: server.URL})
+			err := service.Query("query($n: Int!) { viewer { login } }", map[string]interface{}{"n": 1}, &result)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error: got %v want %v", err, tt.wantErr)
+			}
+			if result.Viewer.Login != tt.want {
+				t.Errorf("unexpected login: got %q want %q", result.Viewer.Login, tt.want)
+			}
+			if got.Query == "" || got.Variables["n"] != float64(1) {
+				t.Errorf("query and variables not sent: %+v", got)
+			}
+		})
+	}
+}
+
+func TestGraphQLRetry(t *testing.T) {
+	original := sleep
+	t.Cleanup(func() { sleep = original })
+	sleep = func(time.Duration) {}
+
+	tests := []struct {
+		name      string
+		query     string
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "query retries", query: "query { viewer { login } }", wantCalls: 2},
+		{name: "mutation does not", query: "  mutation { closeIssue(input: {}) { clientMutationId } }", wantCalls: 1, wantErr: domain.ErrApi},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls == 1 {
+					w.WriteHeader(http.StatusBadGateway)
+					_, _ = w.Write([]byte(`{"message":"bad gateway"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"data":{}}`))
+			}))
+			defer server.Close()
+
+			err := New(&domain.Config{Token: "mockToken", APIBaseURL: server.URL}).Query(tt.query, nil, &struct{}{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error: got %v want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("unexpected calls: got %d want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	pages := []string{
+		`{"repository":{"issues":{"nodes":[{"number":1},{"number":2}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}`,
+		`{"repository":{"issues":{"nodes":[{"number":3}],"pageInfo":{"hasNextPage":false,"endCursor":"c2"}}}}`,
+	}
+
+	var cursors []interface{}
+	graphqlStub := &stubs.GraphQLStub{
+		QueryFunc: func(query string, variables map[string]interface{}, result interface{}) error {
+			cursors = append(cursors, variables["cursor"])
+			if variables["owner"] != "owner" {
+				t.Errorf("variables not forwarded: %v", variables)
+			}
+			return stubs.GraphQLResponse(pages[len(cursors)-1])(query, variables, result)
+		},
+	}
+
+	var numbers []int
+	err := Paginate(graphqlStub, "query", map[string]interface{}{"owner": "owner"}, func(data json.RawMessage) (PageInfo, error) {
+		var page struct {
+			Repository struct {
+				Issues struct {
+					Nodes    []struct{ Number int } `json:"nodes"`
+					PageInfo PageInfo               `json:"pageInfo"`
+				} `json:"issues"`
+			} `json:"repository"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return PageInfo{}, err
+		}
+		for _, n := range page.Repository.Issues.Nodes {
+			numbers = append(numbers, n.Number)
+		}
+		return page.Repository.Issues.PageInfo, nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(numbers) != 3 || numbers[2] != 3 {
+		t.Errorf("unexpected numbers: %v", numbers)
+	}
+	if len(cursors) != 2 || cursors[0] != nil || cursors[1] != "c1" {
+		t.Errorf("unexpected cursors: %v", cursors)
+	}
+}
+
+func TestPaginateError(t *testing.T) {
+	graphqlStub := &stubs.GraphQLStub{
+		QueryFunc: func(query string, variables map[string]interface{}, result interface{}) error {
+			return domain.ErrGraphQL
+		},
+	}
+
+	err := Paginate(graphqlStub, "query", nil, func(data json.RawMessage) (PageInfo, error) {
+		t.Fatal("page called after an error")
+		return PageInfo{}, nil
+	})
+	if !errors.Is(err, domain.ErrGraphQL) {
+		t.Errorf("expected ErrGraphQL, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"git-issues/domain"
+)
+
+// NewHTTPClient trusts the CA bundle of the config on top of the system
+// roots and presents the client certificate, when they are set.
+func NewHTTPClient(config *domain.Config) (*http.Client, error) {
+	if config.CACert == "" && config.ClientCert == "" {
+		return &http.Client{}, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if config.CACert != "" {
+		pem, err := os.ReadFile(config.CACert)
+		if err != nil {
+			return &http.Client{}, errors.Join(domain.ErrTLS, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return &http.Client{}, errors.Join(domain.ErrTLS, fmt.Errorf("no certificates in %s", config.CACert))
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.ClientCert != "" {
+		key := config.ClientKey
+		if key == "" {
+			// the key may sit in the same PEM file as the certificate
+			key = config.ClientCert
+		}
+		cert, err := tls.LoadX509KeyPair(config.ClientCert, key)
+		if err != nil {
+			return &http.Client{}, errors.Join(domain.ErrTLS, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
@@ -87,6 +87,11 @@ func (s *Service) GetIssueContentFromEditor(issue *domain.Issue) error {
 	return nil
 }
 
+// Command is the editor the issues are edited with.
+func (s *Service) Command() string {
+	return s.getEditor()
+}
+
 func (s *Service) getEditor() string {
 	if s.config.Editor != "" {
 		return s.config.Editor
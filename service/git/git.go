@@ -0,0 +1,185 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Separators of the log format, which commit messages do not contain.
+const (
+	fieldSeparator  = "\x1f"
+	commitSeparator = "\x1e"
+	logFormat       = "--format=%H%x1f%s%x1f%b%x1e"
+)
+
+var (
+	ErrGit             = errors.New("git error")
+	errNoBranch        = errors.New("HEAD is not on a branch")
+	errEmptyName       = errors.New("branch name is empty")
+	errNoDefaultBranch = errors.New("could not find the default branch, there is no origin/HEAD, main or master")
+)
+
+// Commit is a commit of the log. Body is the message after the subject.
+type Commit struct {
+	Hash    string
+	Subject string
+	Body    string
+}
+
+// Message is the full commit message.
+func (c Commit) Message() string {
+	if c.Body == "" {
+		return c.Subject
+	}
+	return c.Subject + "\n\n" + c.Body
+}
+
+// Git runs the git commands the workflow commands need on a working tree.
+type Git interface {
+	CurrentBranch() (string, error)
+	BranchExists(name string) (bool, error)
+	CreateBranch(name string) error
+	Checkout(name string) error
+	Remotes() (string, error)
+	Log(revisions ...string) ([]Commit, error)
+	DefaultBranch() (string, error)
+	HooksDir() (string, error)
+	RefDate(ref string) (time.Time, error)
+}
+
+type Service struct {
+	dir string
+}
+
+// New works on the repository containing dir, the current directory when
+// dir is empty.
+func New(dir string) *Service {
+	return &Service{
+		dir: dir,
+	}
+}
+
+// CurrentBranch is the short name of the checked out branch.
+func (s *Service) CurrentBranch() (string, error) {
+	out, err := s.run("symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return "", errors.Join(errNoBranch, err)
+	}
+	return out, nil
+}
+
+func (s *Service) BranchExists(name string) (bool, error) {
+	if name == "" {
+		return false, errEmptyName
+	}
+	_, err := s.run("rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// CreateBranch creates the branch from HEAD and checks it out.
+func (s *Service) CreateBranch(name string) error {
+	if name == "" {
+		return errEmptyName
+	}
+	_, err := s.run("checkout", "-b", name)
+	return err
+}
+
+func (s *Service) Checkout(name string) error {
+	_, err := s.run("checkout", name)
+	return err
+}
+
+// Remotes returns the output of git remote -v.
+func (s *Service) Remotes() (string, error) {
+	return s.run("remote", "-v")
+}
+
+// Log lists the commits reachable from the revisions, HEAD when there are
+// none, newest first. Ranges like main..HEAD and --all work too.
+func (s *Service) Log(revisions ...string) ([]Commit, error) {
+	out, err := s.run(append([]string{"log", logFormat}, revisions...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(out, commitSeparator) {
+		fields := strings.SplitN(strings.TrimSpace(record), fieldSeparator, 3)
+		if len(fields) < 3 {
+			continue
+		}
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Subject: fields[1],
+			Body:    strings.TrimSpace(fields[2]),
+		})
+	}
+	return commits, nil
+}
+
+// DefaultBranch is the branch origin/HEAD points to, or else a local main
+// or master branch.
+func (s *Service) DefaultBranch() (string, error) {
+	if out, err := s.run("symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		return out, nil
+	}
+	for _, name := range []string{"main", "master"} {
+		if exists, err := s.BranchExists(name); err == nil && exists {
+			return name, nil
+		}
+	}
+	return "", errNoDefaultBranch
+}
+
+// HooksDir is where git looks for hooks, core.hooksPath included.
+func (s *Service) HooksDir() (string, error) {
+	out, err := s.run("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(s.dir, out)
+	}
+	return filepath.Abs(out)
+}
+
+// RefDate is the commit date of the commit a tag, branch or hash points to.
+func (s *Service) RefDate(ref string) (time.Time, error) {
+	out, err := s.run("log", "-1", "--format=%cI", ref+"^{commit}", "--")
+	if err != nil {
+		return time.Time{}, err
+	}
+	date, err := time.Parse(time.RFC3339, out)
+	if err != nil {
+		return time.Time{}, errors.Join(ErrGit, err)
+	}
+	return date, nil
+}
+
+// run returns the trimmed output of the command. Errors keep the
+// *exec.ExitError and the message git wrote.
+func (s *Service) run(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = s.dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", errors.Join(ErrGit, fmt.Errorf("git %s: %s (%w)", args[0], message, err))
+		}
+		return "", errors.Join(ErrGit, fmt.Errorf("git %s: %w", args[0], err))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
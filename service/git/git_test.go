@@ -0,0 +1,203 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newRepo creates a repository with one commit on main in a temp dir.
+func newRepo(t *testing.T) *Service {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch", "main"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", "initial"},
+		{"remote", "add", "origin", "git@github.com:octo/hello.git"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return New(dir)
+}
+
+// commit adds an empty commit with the message to the repository.
+func commit(t *testing.T, s *Service, message string) {
+	t.Helper()
+	if _, err := s.run("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", message); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+}
+
+func TestBranches(t *testing.T) {
+	s := newRepo(t)
+
+	branch, err := s.CurrentBranch()
+	if err != nil || branch != "main" {
+		t.Fatalf("CurrentBranch: got %q, %v want main", branch, err)
+	}
+
+	exists, err := s.BranchExists("12-fix-login")
+	if err != nil || exists {
+		t.Fatalf("BranchExists before create: got %v, %v", exists, err)
+	}
+
+	if err = s.CreateBranch("12-fix-login"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if branch, _ = s.CurrentBranch(); branch != "12-fix-login" {
+		t.Errorf("after CreateBranch got branch %q", branch)
+	}
+	if exists, err = s.BranchExists("12-fix-login"); err != nil || !exists {
+		t.Errorf("BranchExists after create: got %v, %v", exists, err)
+	}
+
+	err = s.CreateBranch("12-fix-login")
+	if !errors.Is(err, ErrGit) {
+		t.Errorf("CreateBranch of an existing branch: got %v want ErrGit", err)
+	}
+
+	if err = s.Checkout("main"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if branch, _ = s.CurrentBranch(); branch != "main" {
+		t.Errorf("after Checkout got branch %q", branch)
+	}
+}
+
+func TestRemotes(t *testing.T) {
+	s := newRepo(t)
+
+	out, err := s.Remotes()
+	if err != nil {
+		t.Fatalf("Remotes: %v", err)
+	}
+	want := "origin\tgit@github.com:octo/hello.git (fetch)\norigin\tgit@github.com:octo/hello.git (push)"
+	if out != want {
+		t.Errorf("got %q want %q", out, want)
+	}
+}
+
+func TestOutsideRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	s := New(t.TempDir())
+
+	if _, err := s.CurrentBranch(); !errors.Is(err, ErrGit) {
+		t.Errorf("CurrentBranch: got %v want ErrGit", err)
+	}
+	if _, err := s.Remotes(); !errors.Is(err, ErrGit) {
+		t.Errorf("Remotes: got %v want ErrGit", err)
+	}
+}
+
+func TestLog(t *testing.T) {
+	s := newRepo(t)
+	if err := s.CreateBranch("12-login"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	commit(t, s, "Fix the login\n\nFixes #12\nSee also #3")
+	commit(t, s, "Add a test")
+
+	commits, err := s.Log("main..HEAD")
+	if err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+
+	var got []Commit
+	for _, c := range commits {
+		if len(c.Hash) != 40 {
+			t.Errorf("unexpected hash %q", c.Hash)
+		}
+		got = append(got, Commit{Subject: c.Subject, Body: c.Body})
+	}
+	want := []Commit{
+		{Subject: "Add a test"},
+		{Subject: "Fix the login", Body: "Fixes #12\nSee also #3"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+	if msg := got[1].Message(); msg != "Fix the login\n\nFixes #12\nSee also #3" {
+		t.Errorf("Message: got %q", msg)
+	}
+
+	all, err := s.Log()
+	if err != nil || len(all) != 3 {
+		t.Errorf("Log of HEAD: got %d commits, %v want 3", len(all), err)
+	}
+}
+
+func TestDefaultBranch(t *testing.T) {
+	s := newRepo(t)
+
+	got, err := s.DefaultBranch()
+	if err != nil || got != "main" {
+		t.Errorf("without origin/HEAD: got %q, %v want main", got, err)
+	}
+
+	if _, err = s.run("update-ref", "refs/remotes/origin/trunk", "HEAD"); err != nil {
+		t.Fatalf("update-ref: %v", err)
+	}
+	if _, err = s.run("symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/trunk"); err != nil {
+		t.Fatalf("symbolic-ref: %v", err)
+	}
+	got, err = s.DefaultBranch()
+	if err != nil || got != "origin/trunk" {
+		t.Errorf("with origin/HEAD: got %q, %v want origin/trunk", got, err)
+	}
+}
+
+func TestHooksDir(t *testing.T) {
+	s := newRepo(t)
+
+	got, err := s.HooksDir()
+	if err != nil {
+		t.Fatalf("HooksDir: %v", err)
+	}
+	if want := filepath.Join(s.dir, ".git", "hooks"); got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	if _, err = s.run("config", "core.hooksPath", "githooks"); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	got, _ = s.HooksDir()
+	if want := filepath.Join(s.dir, "githooks"); got != want {
+		t.Errorf("with core.hooksPath got %q want %q", got, want)
+	}
+}
+
+func TestRefDate(t *testing.T) {
+	s := newRepo(t)
+	if _, err := s.run("-c", "user.name=test", "-c", "user.email=test@example.com", "tag", "-a", "v1.0.0", "-m", "release"); err != nil {
+		t.Fatalf("tag: %v", err)
+	}
+	head, err := s.run("log", "-1", "--format=%cI")
+	if err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	want, _ := time.Parse(time.RFC3339, head)
+
+	for _, ref := range []string{"v1.0.0", "main", "HEAD"} {
+		got, err := s.RefDate(ref)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("%s: got %v, %v want %v", ref, got, err, want)
+		}
+	}
+
+	if _, err = s.RefDate("v9.9.9"); !errors.Is(err, ErrGit) {
+		t.Errorf("unknown tag: got %v want ErrGit", err)
+	}
+}
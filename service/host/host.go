@@ -0,0 +1,103 @@
+package host
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"git-issues/domain"
+)
+
+// GitHub is the hostname of github.com, whose api lives on its own subdomain.
+const GitHub = "github.com"
+
+const (
+	enterpriseREST    = "/api/v3"
+	enterpriseGraphQL = "/api/graphql"
+)
+
+// Host holds the base urls of a GitHub instance. On github.com the apis are
+// served by api.github.com, on Enterprise Server under /api of the host.
+type Host struct {
+	Name    string
+	REST    string
+	GraphQL string
+	Web     string
+}
+
+// New maps a hostname, with or without scheme, to its urls.
+func New(name string) Host {
+	scheme := "https://"
+	if strings.HasPrefix(name, "http://") {
+		scheme = "http://"
+	}
+	name = strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(name, "https://"), "http://"), "/"))
+	if name == "" || name == GitHub || name == "api."+GitHub {
+		return FromAPIBaseURL(domain.ApiBaseUrl)
+	}
+	return FromAPIBaseURL(scheme + name + enterpriseREST)
+}
+
+// FromAPIBaseURL derives the host from the REST url of the config. Urls that
+// follow neither layout, like a local proxy, keep GraphQL next to REST and
+// have no web url.
+func FromAPIBaseURL(apiBaseURL string) Host {
+	apiBaseURL = strings.TrimSuffix(apiBaseURL, "/")
+	if apiBaseURL == "" {
+		apiBaseURL = domain.ApiBaseUrl
+	}
+
+	h := Host{REST: apiBaseURL, GraphQL: apiBaseURL + "/graphql"}
+	parsed, err := url.Parse(apiBaseURL)
+	if err != nil {
+		return h
+	}
+	h.Name = parsed.Host
+
+	switch {
+	case parsed.Host == "api."+GitHub:
+		h.Name = GitHub
+		h.Web = "https://" + GitHub
+	case strings.HasSuffix(parsed.Path, enterpriseREST):
+		root := strings.TrimSuffix(apiBaseURL, enterpriseREST)
+		h.GraphQL = root + enterpriseGraphQL
+		h.Web = root
+	}
+	return h
+}
+
+// IsEnterprise reports whether the host is not github.com.
+func (h Host) IsEnterprise() bool {
+	return h.Name != GitHub
+}
+
+// IssueURL is the web page of an issue, empty when the host has no known
+// web url.
+func (h Host) IssueURL(owner, repo string, number int) string {
+	if h.Web == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%s/issues/%d", h.Web, owner, repo, number)
+}
+
+// Settings returns the per-host settings of the config whose key names the
+// same host.
+func Settings(config *domain.Config, h Host) (domain.HostConfig, bool) {
+	for name, settings := range config.Hosts {
+		if New(name).Name == h.Name {
+			return settings, true
+		}
+	}
+	return domain.HostConfig{}, false
+}
+
+// FileName joins the parts with _ into the name of a file kept per host and
+// repository, the separators of paths and ports replaced.
+func FileName(parts ...string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' {
+			return '-'
+		}
+		return r
+	}, strings.Join(parts, "_"))
+}
@@ -0,0 +1,102 @@
+package host
+
+import (
+	"reflect"
+	"testing"
+
+	"git-issues/domain"
+)
+
+func TestNew(t *testing.T) {
+	github := Host{Name: GitHub, REST: "https://api.github.com", GraphQL: "https://api.github.com/graphql", Web: "https://github.com"}
+	ghe := Host{Name: "ghe.example.com", REST: "https://ghe.example.com/api/v3", GraphQL: "https://ghe.example.com/api/graphql", Web: "https://ghe.example.com"}
+
+	tests := []struct {
+		name string
+		want Host
+	}{
+		{name: "", want: github},
+		{name: "github.com", want: github},
+		{name: "https://api.github.com/", want: github},
+		{name: "ghe.example.com", want: ghe},
+		{name: "https://GHE.example.com/", want: ghe},
+		{
+			name: "http://localhost:8080",
+			want: Host{Name: "localhost:8080", REST: "http://localhost:8080/api/v3", GraphQL: "http://localhost:8080/api/graphql", Web: "http://localhost:8080"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.name); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New(%q) = %+v want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromAPIBaseURL(t *testing.T) {
+	tests := []struct {
+		apiBaseURL string
+		want       Host
+	}{
+		{
+			apiBaseURL: "",
+			want:       Host{Name: GitHub, REST: "https://api.github.com", GraphQL: "https://api.github.com/graphql", Web: "https://github.com"},
+		},
+		{
+			apiBaseURL: "https://ghe.example.com/api/v3/",
+			want:       Host{Name: "ghe.example.com", REST: "https://ghe.example.com/api/v3", GraphQL: "https://ghe.example.com/api/graphql", Web: "https://ghe.example.com"},
+		},
+		{
+			apiBaseURL: "http://127.0.0.1:9000",
+			want:       Host{Name: "127.0.0.1:9000", REST: "http://127.0.0.1:9000", GraphQL: "http://127.0.0.1:9000/graphql"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.apiBaseURL, func(t *testing.T) {
+			if got := FromAPIBaseURL(tt.apiBaseURL); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromAPIBaseURL(%q) = %+v want %+v", tt.apiBaseURL, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueURL(t *testing.T) {
+	if got := New("ghe.example.com").IssueURL("o", "r", 3); got != "https://ghe.example.com/o/r/issues/3" {
+		t.Errorf("got %q", got)
+	}
+	if got := New("").IssueURL("o", "r", 3); got != "https://github.com/o/r/issues/3" {
+		t.Errorf("got %q", got)
+	}
+	if got := FromAPIBaseURL("http://127.0.0.1:9000").IssueURL("o", "r", 3); got != "" {
+		t.Errorf("unknown web url should give no issue url, got %q", got)
+	}
+	if New("").IsEnterprise() || !New("ghe.example.com").IsEnterprise() {
+		t.Error("IsEnterprise mismatch")
+	}
+}
+
+func TestSettings(t *testing.T) {
+	config := &domain.Config{Hosts: map[string]domain.HostConfig{
+		"https://GHE.example.com": {Token: "ghe"},
+		"github.com":              {Token: "dotcom"},
+	}}
+
+	if s, ok := Settings(config, New("ghe.example.com")); !ok || s.Token != "ghe" {
+		t.Errorf("got %+v, %v", s, ok)
+	}
+	if s, ok := Settings(config, FromAPIBaseURL(domain.ApiBaseUrl)); !ok || s.Token != "dotcom" {
+		t.Errorf("got %+v, %v", s, ok)
+	}
+	if _, ok := Settings(config, New("other.example.com")); ok {
+		t.Error("unexpected settings for an unknown host")
+	}
+}
+
+func TestFileName(t *testing.T) {
+	if got := FileName("localhost:8080", "o", "r/x\\y", "issue"); got != "localhost-8080_o_r-x-y_issue" {
+		t.Errorf("got %q", got)
+	}
+}
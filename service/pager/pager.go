@@ -0,0 +1,129 @@
+package pager
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"git-issues/domain"
+	"git-issues/service/terminal"
+)
+
+const defaultPager = "less -R"
+
+var (
+	isTerminal = func(w io.Writer) bool {
+		f, ok := w.(*os.File)
+		return ok && terminal.IsTerminal(f)
+	}
+	// lessEnv makes less quit on output that fits one screen and keep colors.
+	lessEnv = map[string]string{"LESS": "FRX", "LV": "-c"}
+)
+
+type Pager interface {
+	Start(out io.Writer) io.WriteCloser
+}
+
+type Service struct {
+	config *domain.Config
+}
+
+func New(config *domain.Config) *Service {
+	return &Service{
+		config: config,
+	}
+}
+
+// Start returns a writer that feeds the pager. When out is not a terminal, the
+// pager is disabled or it cannot be started, the writer goes straight to out.
+func (s *Service) Start(out io.Writer) io.WriteCloser {
+	args := strings.Fields(s.command())
+	if len(args) == 0 || !isTerminal(out) {
+		return Passthrough(out)
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	for key, value := range lessEnv {
+		if _, ok := os.LookupEnv(key); !ok {
+			cmd.Env = append(cmd.Env, key+"="+value)
+		}
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return Passthrough(out)
+	}
+	if err = cmd.Start(); err != nil {
+		return Passthrough(out)
+	}
+
+	return &pipe{cmd: cmd, stdin: stdin}
+}
+
+func (s *Service) command() string {
+	if command, ok := os.LookupEnv("GHISSUES_PAGER"); ok {
+		return command
+	}
+	if s.config != nil && s.config.Pager != "" {
+		return s.config.Pager
+	}
+	if command := os.Getenv("PAGER"); command != "" {
+		return command
+	}
+	return defaultPager
+}
+
+// pipe writes to the pager and silently discards output once the user has
+// quit it, so the printers do not report a broken pipe.
+type pipe struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	closed bool
+}
+
+func (p *pipe) Write(b []byte) (int, error) {
+	if p.closed {
+		return len(b), nil
+	}
+
+	n, err := p.stdin.Write(b)
+	if err != nil && isClosedPipe(err) {
+		p.closed = true
+		return len(b), nil
+	}
+	return n, err
+}
+
+// Close ends the input and waits for the user to leave the pager.
+func (p *pipe) Close() error {
+	err := p.stdin.Close()
+	if err != nil && !isClosedPipe(err) {
+		return err
+	}
+	// the exit status of the pager is not interesting to the caller
+	_ = p.cmd.Wait()
+	return nil
+}
+
+func isClosedPipe(err error) bool {
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, os.ErrClosed)
+}
+
+// Passthrough returns a writer to out for callers that skip the pager.
+func Passthrough(out io.Writer) io.WriteCloser {
+	return nopCloser{out}
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,154 @@
+package pager
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"git-issues/domain"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *domain.Config
+		ghissuesEnv *string
+		pagerEnv    string
+		wantCommand string
+	}{
+		{
+			name:        "default",
+			config:      &domain.Config{},
+			wantCommand: defaultPager,
+		},
+		{
+			name:        "PAGER",
+			config:      &domain.Config{},
+			pagerEnv:    "more",
+			wantCommand: "more",
+		},
+		{
+			name:        "config before PAGER",
+			config:      &domain.Config{Pager: "most"},
+			pagerEnv:    "more",
+			wantCommand: "most",
+		},
+		{
+			name:        "GHISSUES_PAGER before config",
+			config:      &domain.Config{Pager: "most"},
+			ghissuesEnv: strPtr("bat"),
+			wantCommand: "bat",
+		},
+		{
+			name:        "empty GHISSUES_PAGER disables",
+			config:      &domain.Config{Pager: "most"},
+			ghissuesEnv: strPtr(""),
+			wantCommand: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PAGER", tt.pagerEnv)
+			t.Setenv("GHISSUES_PAGER", "")
+			if tt.ghissuesEnv == nil {
+				unsetenv(t, "GHISSUES_PAGER")
+			} else {
+				t.Setenv("GHISSUES_PAGER", *tt.ghissuesEnv)
+			}
+
+			if got := New(tt.config).command(); got != tt.wantCommand {
+				t.Errorf("command() = %q, want %q", got, tt.wantCommand)
+			}
+		})
+	}
+}
+
+func TestStart(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses unix commands as pagers")
+	}
+
+	tests := []struct {
+		name     string
+		pager    string
+		terminal bool
+		input    string
+		want     string
+	}{
+		{
+			name:     "pipes through the pager",
+			pager:    "tr a-z A-Z",
+			terminal: true,
+			input:    "issues\n",
+			want:     "ISSUES\n",
+		},
+		{
+			name:     "not a terminal writes directly",
+			pager:    "tr a-z A-Z",
+			terminal: false,
+			input:    "issues\n",
+			want:     "issues\n",
+		},
+		{
+			name:     "pager that cannot start writes directly",
+			pager:    "ghissues-missing-pager",
+			terminal: true,
+			input:    "issues\n",
+			want:     "issues\n",
+		},
+		{
+			name:     "pager quit early discards output",
+			pager:    "true",
+			terminal: true,
+			input:    strings.Repeat("a long line of output\n", 50000),
+			want:     "",
+		},
+	}
+
+	original := isTerminal
+	t.Cleanup(func() { isTerminal = original })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unsetenv(t, "GHISSUES_PAGER")
+			isTerminal = func(io.Writer) bool { return tt.terminal }
+			var out bytes.Buffer
+
+			w := New(&domain.Config{Pager: tt.pager}).Start(&out)
+			_, err := io.WriteString(w, tt.input)
+			if err != nil {
+				t.Fatalf("unexpected write error: %v", err)
+			}
+			if err = w.Close(); err != nil {
+				t.Fatalf("unexpected close error: %v", err)
+			}
+
+			if got := out.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", truncate(got), truncate(tt.want))
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func unsetenv(t *testing.T, key string) {
+	t.Helper()
+	t.Setenv(key, "")
+	if err := os.Unsetenv(key); err != nil {
+		t.Fatalf("failed to unset %s: %v", key, err)
+	}
+}
+
+func truncate(s string) string {
+	if len(s) > 40 {
+		return s[:40] + "..."
+	}
+	return s
+}
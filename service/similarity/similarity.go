@@ -0,0 +1,143 @@
+// Package similarity scores how alike short texts are, like issue titles and
+// bodies, without any service: word sets are compared with Jaccard and whole
+// texts with the cosine of their TF-IDF vectors.
+package similarity
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// stopWords are dropped from the tokens, being in nearly every text.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "can": true, "do": true, "does": true,
+	"for": true, "from": true, "has": true, "have": true, "i": true, "if": true,
+	"in": true, "is": true, "it": true, "its": true, "not": true, "of": true,
+	"on": true, "or": true, "that": true, "the": true, "this": true, "to": true,
+	"was": true, "we": true, "when": true, "with": true, "you": true,
+}
+
+// Match is the score of the document at Index in the slice given to Rank.
+type Match struct {
+	Index int
+	Score float64
+}
+
+// Tokens splits text into lower case words, dropping punctuation, stop words
+// and single letters. A plural s is cut so "crashes" and "crash" are alike.
+func Tokens(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len([]rune(field)) < 2 || stopWords[field] {
+			continue
+		}
+		tokens = append(tokens, stem(field))
+	}
+	return tokens
+}
+
+func stem(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "es") && strings.ContainsAny(word[len(word)-3:len(word)-2], "hsxz"):
+		return word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// Jaccard is the share of distinct tokens a and b have in common, from 0 for
+// none to 1 for the same set.
+func Jaccard(a, b string) float64 {
+	setA, setB := set(Tokens(a)), set(Tokens(b))
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0
+	}
+	common := 0
+	for token := range setA {
+		if setB[token] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(setA)+len(setB)-common)
+}
+
+// Rank scores every document against query with the cosine of their TF-IDF
+// vectors, weighing words by how rare they are among the documents. Matches
+// are returned best first, leaving out documents sharing no word.
+func Rank(query string, documents []string) []Match {
+	docs := make([]map[string]float64, len(documents))
+	frequency := map[string]int{}
+	for i, document := range documents {
+		docs[i] = counts(Tokens(document))
+		for token := range docs[i] {
+			frequency[token]++
+		}
+	}
+	q := counts(Tokens(query))
+	for token := range q {
+		frequency[token]++
+	}
+
+	// the query counts as a document, so words only it has still weigh
+	total := float64(len(documents) + 1)
+	idf := func(token string) float64 {
+		return math.Log((total+1)/float64(frequency[token]+1)) + 1
+	}
+	weigh(q, idf)
+
+	var matches []Match
+	for i, doc := range docs {
+		weigh(doc, idf)
+		if score := cosine(q, doc); score > 0 {
+			matches = append(matches, Match{Index: i, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+func set(tokens []string) map[string]bool {
+	s := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		s[token] = true
+	}
+	return s
+}
+
+func counts(tokens []string) map[string]float64 {
+	c := make(map[string]float64, len(tokens))
+	for _, token := range tokens {
+		c[token]++
+	}
+	return c
+}
+
+func weigh(vector map[string]float64, idf func(string) float64) {
+	for token, count := range vector {
+		vector[token] = count * idf(token)
+	}
+}
+
+func cosine(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for token, weight := range a {
+		dot += weight * b[token]
+		normA += weight * weight
+	}
+	for _, weight := range b {
+		normB += weight * weight
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
@@ -0,0 +1,78 @@
+package similarity
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "The app crashes on start!", want: []string{"app", "crash", "start"}},
+		{text: "Login fails with `401` (SSO)", want: []string{"login", "fail", "401", "sso"}},
+		{text: "Class access in a box", want: []string{"class", "access", "box"}},
+		{text: "a I - .", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := Tokens(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{name: "same words", a: "Crash on start", b: "start crash", want: 1},
+		{name: "half shared", a: "crash on start", b: "crash on exit", want: 1.0 / 3},
+		{name: "nothing shared", a: "crash", b: "typo in docs", want: 0},
+		{name: "empty", a: "", b: "crash", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Jaccard(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	documents := []string{
+		"Typo in the README install section",
+		"App crashes on start when the config file is missing",
+		"Dark mode for the settings page",
+		"Crash when saving the settings page",
+	}
+
+	got := Rank("crash on start without a config file", documents)
+
+	if len(got) != 2 {
+		t.Fatalf("got %+v want the two crash reports", got)
+	}
+	if got[0].Index != 1 || got[1].Index != 3 {
+		t.Errorf("got order %+v want 1 then 3", got)
+	}
+	if got[0].Score <= got[1].Score || got[0].Score > 1 {
+		t.Errorf("unexpected scores %+v", got)
+	}
+
+	same := Rank(documents[2], documents)
+	if same[0].Index != 2 || math.Abs(same[0].Score-1) > 1e-9 {
+		t.Errorf("a document should match itself with 1, got %+v", same)
+	}
+
+	if got = Rank("", documents); got != nil {
+		t.Errorf("empty query: got %+v", got)
+	}
+}
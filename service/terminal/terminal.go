@@ -0,0 +1,105 @@
+package terminal
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+var (
+	errRawMode = errors.New("could not switch terminal to raw mode")
+	errEcho    = errors.New("could not hide the terminal input")
+	runStty    = stty
+)
+
+// IsTerminal reports whether f is attached to a character device.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// IsDumb reports whether the terminal cannot handle cursor movement.
+func IsDumb() bool {
+	term := os.Getenv("TERM")
+	return term == "" || term == "dumb"
+}
+
+// Size returns the terminal width and height, falling back to $COLUMNS and
+// $LINES and then to 80x24.
+func Size() (int, int) {
+	width, height := defaultWidth, defaultHeight
+
+	if out, err := runStty("size"); err == nil {
+		fields := strings.Fields(out)
+		if len(fields) == 2 {
+			h, errH := strconv.Atoi(fields[0])
+			w, errW := strconv.Atoi(fields[1])
+			if errH == nil && errW == nil && w > 0 && h > 0 {
+				return w, h
+			}
+		}
+	}
+
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		width = w
+	}
+	if h, err := strconv.Atoi(os.Getenv("LINES")); err == nil && h > 0 {
+		height = h
+	}
+	return width, height
+}
+
+// MakeRaw puts the terminal in raw mode and returns a function that restores
+// the previous state.
+func MakeRaw() (func() error, error) {
+	restore, err := setMode("raw", "-echo")
+	if err != nil {
+		return nil, errors.Join(errRawMode, err)
+	}
+	return restore, nil
+}
+
+// DisableEcho hides what is typed, for secrets, and returns a function that
+// restores the previous state. Lines are still read as usual.
+func DisableEcho() (func() error, error) {
+	restore, err := setMode("-echo")
+	if err != nil {
+		return nil, errors.Join(errEcho, err)
+	}
+	return restore, nil
+}
+
+// setMode applies the stty settings and returns a function that goes back
+// to the saved state.
+func setMode(settings ...string) (func() error, error) {
+	state, err := runStty("-g")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = runStty(settings...); err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		_, err := runStty(strings.TrimSpace(state))
+		return err
+	}, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
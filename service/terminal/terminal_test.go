@@ -0,0 +1,153 @@
+package terminal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	defer f.Close()
+
+	if IsTerminal(f) {
+		t.Errorf("regular file reported as terminal")
+	}
+}
+
+func TestIsDumb(t *testing.T) {
+	tests := []struct {
+		term string
+		want bool
+	}{
+		{term: "", want: true},
+		{term: "dumb", want: true},
+		{term: "xterm-256color", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.term, func(t *testing.T) {
+			t.Setenv("TERM", tt.term)
+			if got := IsDumb(); got != tt.want {
+				t.Errorf("IsDumb() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		stty       func(args ...string) (string, error)
+		columns    string
+		lines      string
+		wantWidth  int
+		wantHeight int
+	}{
+		{
+			name:       "from stty",
+			stty:       func(args ...string) (string, error) { return "40 120\n", nil },
+			wantWidth:  120,
+			wantHeight: 40,
+		},
+		{
+			name:       "from environment",
+			stty:       func(args ...string) (string, error) { return "", errors.New("no tty") },
+			columns:    "100",
+			lines:      "30",
+			wantWidth:  100,
+			wantHeight: 30,
+		},
+		{
+			name:       "defaults",
+			stty:       func(args ...string) (string, error) { return "garbage", nil },
+			wantWidth:  defaultWidth,
+			wantHeight: defaultHeight,
+		},
+	}
+
+	original := runStty
+	t.Cleanup(func() { runStty = original })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runStty = tt.stty
+			t.Setenv("COLUMNS", tt.columns)
+			t.Setenv("LINES", tt.lines)
+
+			w, h := Size()
+			if w != tt.wantWidth || h != tt.wantHeight {
+				t.Errorf("Size() = %dx%d, want %dx%d", w, h, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestMakeRaw(t *testing.T) {
+	original := runStty
+	t.Cleanup(func() { runStty = original })
+
+	var calls [][]string
+	runStty = func(args ...string) (string, error) {
+		calls = append(calls, args)
+		if len(args) == 1 && args[0] == "-g" {
+			return "saved-state\n", nil
+		}
+		return "", nil
+	}
+
+	restore, err := MakeRaw()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = restore(); err != nil {
+		t.Fatalf("unexpected restore error: %v", err)
+	}
+
+	want := [][]string{{"-g"}, {"raw", "-echo"}, {"saved-state"}}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("unexpected stty calls: got %v want %v", calls, want)
+	}
+
+	runStty = func(args ...string) (string, error) { return "", errors.New("no tty") }
+	if _, err = MakeRaw(); !errors.Is(err, errRawMode) {
+		t.Errorf("expected errRawMode, got %v", err)
+	}
+}
+
+func TestDisableEcho(t *testing.T) {
+	original := runStty
+	t.Cleanup(func() { runStty = original })
+
+	var calls [][]string
+	runStty = func(args ...string) (string, error) {
+		calls = append(calls, args)
+		if len(args) == 1 && args[0] == "-g" {
+			return "saved-state\n", nil
+		}
+		return "", nil
+	}
+
+	restore, err := DisableEcho()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = restore(); err != nil {
+		t.Fatalf("unexpected restore error: %v", err)
+	}
+
+	want := [][]string{{"-g"}, {"-echo"}, {"saved-state"}}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("unexpected stty calls: got %v want %v", calls, want)
+	}
+
+	runStty = func(args ...string) (string, error) { return "", errors.New("no tty") }
+	if _, err = DisableEcho(); !errors.Is(err, errEcho) {
+		t.Errorf("expected errEcho, got %v", err)
+	}
+}
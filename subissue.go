@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"git-issues/domain"
+	"git-issues/features/issue"
+	"git-issues/service/client"
+)
+
+const strSubIssueUsage = `usage:
+  ghissues subissue add <parent> <child>
+  ghissues subissue remove <parent> <child>`
+
+func runSubIssue(config *domain.Config, graphql client.GraphQLClient, args []string) {
+	if len(args) < 3 {
+		fmt.Println(strSubIssueUsage)
+		return
+	}
+	parent, ok := issueNumber(args[1:])
+	if !ok {
+		return
+	}
+
+	subIssues := issue.NewSubIssue(config, graphql)
+	switch args[0] {
+	case "add":
+		if err := subIssues.Add(parent, args[2]); err != nil {
+			fmt.Printf("error on add sub-issue: %v\n", err)
+			return
+		}
+		fmt.Println("sub-issue added")
+
+	case "remove":
+		if err := subIssues.Remove(parent, args[2]); err != nil {
+			fmt.Printf("error on remove sub-issue: %v\n", err)
+			return
+		}
+		fmt.Println("sub-issue removed")
+
+	default:
+		fmt.Println(strSubIssueUsage)
+	}
+}
+
+func runProgress(config *domain.Config, graphql client.GraphQLClient, args []string) {
+	number, ok := issueNumber(args)
+	if !ok {
+		return
+	}
+	progress, err := issue.NewSubIssue(config, graphql).Progress(number)
+	if err != nil {
+		fmt.Printf("error on issue progress: %v\n", err)
+		return
+	}
+	if err = issue.PrintProgress(os.Stdout, progress); err != nil {
+		fmt.Printf("error on print progress: %v\n", err)
+	}
+}
@@ -0,0 +1,25 @@
+package stubs
+
+import "encoding/json"
+
+type GraphQLStub struct {
+	QueryFunc func(query string, variables map[string]interface{}, result interface{}) error
+}
+
+func (s *GraphQLStub) Query(query string, variables map[string]interface{}, result interface{}) error {
+	if s.QueryFunc != nil {
+		return s.QueryFunc(query, variables, result)
+	}
+	return nil
+}
+
+// GraphQLResponse returns a QueryFunc that decodes data into the result, the
+// way the real client decodes the data object of a response.
+func GraphQLResponse(data string) func(query string, variables map[string]interface{}, result interface{}) error {
+	return func(query string, variables map[string]interface{}, result interface{}) error {
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal([]byte(data), result)
+	}
+}
@@ -0,0 +1,12 @@
+package stubs
+
+type BrowserStub struct {
+	OpenFunc func(url string) error
+}
+
+func (s *BrowserStub) Open(url string) error {
+	if s.OpenFunc != nil {
+		return s.OpenFunc(url)
+	}
+	return nil
+}
@@ -1,9 +1,17 @@
 package stubs
 
-import "git-issues/domain"
+import (
+	"net/http"
+
+	"git-issues/domain"
+)
 
 type ClientStub struct {
-	MakeRequestFunc func(method, url string, data *domain.Issue) ([]byte, error)
+	MakeRequestFunc       func(method, url string, data *domain.Issue) ([]byte, error)
+	MakeJSONRequestFunc   func(method, url string, payload interface{}) ([]byte, error)
+	MakeRequestHeaderFunc func(method, url string) ([]byte, http.Header, error)
+
+	MakeConditionalRequestFunc func(url, etag string) ([]byte, http.Header, bool, error)
 }
 
 func (s *ClientStub) MakeRequest(method, url string, data *domain.Issue) ([]byte, error) {
@@ -12,3 +20,24 @@ func (s *ClientStub) MakeRequest(method, url string, data *domain.Issue) ([]byte
 	}
 	return nil, nil
 }
+
+func (s *ClientStub) MakeJSONRequest(method, url string, payload interface{}) ([]byte, error) {
+	if s.MakeJSONRequestFunc != nil {
+		return s.MakeJSONRequestFunc(method, url, payload)
+	}
+	return nil, nil
+}
+
+func (s *ClientStub) MakeRequestHeader(method, url string) ([]byte, http.Header, error) {
+	if s.MakeRequestHeaderFunc != nil {
+		return s.MakeRequestHeaderFunc(method, url)
+	}
+	return nil, nil, nil
+}
+
+func (s *ClientStub) MakeConditionalRequest(url, etag string) ([]byte, http.Header, bool, error) {
+	if s.MakeConditionalRequestFunc != nil {
+		return s.MakeConditionalRequestFunc(url, etag)
+	}
+	return nil, nil, false, nil
+}
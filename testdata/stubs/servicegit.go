@@ -0,0 +1,82 @@
+package stubs
+
+import (
+	"time"
+
+	"git-issues/service/git"
+)
+
+type GitStub struct {
+	CurrentBranchFunc func() (string, error)
+	BranchExistsFunc  func(name string) (bool, error)
+	CreateBranchFunc  func(name string) error
+	CheckoutFunc      func(name string) error
+	RemotesFunc       func() (string, error)
+	LogFunc           func(revisions ...string) ([]git.Commit, error)
+	DefaultBranchFunc func() (string, error)
+	HooksDirFunc      func() (string, error)
+	RefDateFunc       func(ref string) (time.Time, error)
+}
+
+func (s *GitStub) CurrentBranch() (string, error) {
+	if s.CurrentBranchFunc != nil {
+		return s.CurrentBranchFunc()
+	}
+	return "", nil
+}
+
+func (s *GitStub) BranchExists(name string) (bool, error) {
+	if s.BranchExistsFunc != nil {
+		return s.BranchExistsFunc(name)
+	}
+	return false, nil
+}
+
+func (s *GitStub) CreateBranch(name string) error {
+	if s.CreateBranchFunc != nil {
+		return s.CreateBranchFunc(name)
+	}
+	return nil
+}
+
+func (s *GitStub) Checkout(name string) error {
+	if s.CheckoutFunc != nil {
+		return s.CheckoutFunc(name)
+	}
+	return nil
+}
+
+func (s *GitStub) Remotes() (string, error) {
+	if s.RemotesFunc != nil {
+		return s.RemotesFunc()
+	}
+	return "", nil
+}
+
+func (s *GitStub) Log(revisions ...string) ([]git.Commit, error) {
+	if s.LogFunc != nil {
+		return s.LogFunc(revisions...)
+	}
+	return nil, nil
+}
+
+func (s *GitStub) DefaultBranch() (string, error) {
+	if s.DefaultBranchFunc != nil {
+		return s.DefaultBranchFunc()
+	}
+	return "main", nil
+}
+
+func (s *GitStub) HooksDir() (string, error) {
+	if s.HooksDirFunc != nil {
+		return s.HooksDirFunc()
+	}
+	return "", nil
+}
+
+func (s *GitStub) RefDate(ref string) (time.Time, error) {
+	if s.RefDateFunc != nil {
+		return s.RefDateFunc(ref)
+	}
+	return time.Time{}, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"git-issues/domain"
+	"git-issues/features/issue"
+	"git-issues/service/client"
+)
+
+var strTransferUsage = `usage:
+  ghissues transfer <n> <owner/repo>
+  ghissues transfer --query "<search>" <owner/repo> [--yes]`
+
+func runTransfer(config *domain.Config, graphql client.GraphQLClient, args []string) {
+	flags := flag.NewFlagSet("transfer", flag.ContinueOnError)
+	query := flags.String("query", "", "transfer every issue matching this search or saved query")
+	yes := flags.Bool("yes", false, "do not ask before a bulk transfer")
+	args, err := parseFlags(flags, args)
+	if err != nil {
+		return
+	}
+	transfer := issue.NewTransfer(config, graphql)
+
+	if *query == "" {
+		if len(args) != 2 {
+			fmt.Println(strTransferUsage)
+			return
+		}
+		number, ok := issueNumber(args)
+		if !ok {
+			return
+		}
+		moved, err := transfer.Transfer(number, args[1])
+		if err != nil {
+			fmt.Printf("error on transfer issue: %v\n", err)
+			return
+		}
+		fmt.Printf("Issue transferred with success!\nNumber: %v\nURL: %v\n", moved.Number, moved.URL)
+		return
+	}
+
+	if len(args) != 1 {
+		fmt.Println(strTransferUsage)
+		return
+	}
+	// a wrong target is reported before the issues are listed and confirmed
+	target, err := transfer.Target(args[0])
+	if err != nil {
+		fmt.Printf("error on transfer issues: %v\n", err)
+		return
+	}
+	issues, err := transfer.Search(searchText(config, *query))
+	if err != nil {
+		fmt.Printf("error on transfer issues: %v\n", err)
+		return
+	}
+	if len(issues) == 0 {
+		fmt.Println("no issues match the query")
+		return
+	}
+	numbers := make([]int, 0, len(issues))
+	for _, i := range issues {
+		numbers = append(numbers, i.Number)
+		fmt.Printf("#%d %s\n", i.Number, i.Title)
+	}
+	if !*yes && !confirm(os.Stdin, fmt.Sprintf("Transfer these %d issues to %s? [y/N]: ", len(issues), target.NameWithOwner)) {
+		fmt.Println("transfer canceled")
+		return
+	}
+
+	moved, err := transfer.TransferAll(numbers, target)
+	for _, m := range moved {
+		fmt.Printf("#%d -> %s#%d %s\n", m.From, m.Repo, m.Number, m.URL)
+	}
+	if err != nil {
+		fmt.Printf("error on transfer issues: %v\n", err)
+		return
+	}
+	fmt.Printf("%d issues transferred\n", len(moved))
+}
+
+// confirm asks a yes/no question, no being the answer to anything but y or
+// yes.
+func confirm(in io.Reader, question string) bool {
+	fmt.Print(question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
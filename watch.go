@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"git-issues/domain"
+	"git-issues/features/watch"
+	"git-issues/service/client"
+)
+
+func runWatch(config *domain.Config, conditional client.ConditionalClient, args []string) {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	format := flags.String("format", watch.FormatText, "output format: text or ndjson")
+	kinds := flags.String("events", "", "comma separated changes to print, all if empty")
+	labels := flags.String("label", "", "comma separated labels the issues all have")
+	interval := flags.Duration("interval", watch.DefaultInterval, "least wait between polls")
+	cursor := flags.String("cursor", "", "file keeping the position of the watch")
+	w := watch.New(config, conditional)
+	flags.StringVar(&w.Filter.Assignee, "assignee", "", "changes of the issues assigned to a login")
+	flags.StringVar(&w.Filter.Actor, "actor", "", "changes made by a login")
+	if _, err := parseFlags(flags, args); err != nil {
+		return
+	}
+	if err := watch.ValidFormat(*format); err != nil {
+		fmt.Println(err)
+		return
+	}
+	w.Filter.Kinds = splitList(*kinds)
+	if err := watch.ValidKinds(w.Filter.Kinds); err != nil {
+		fmt.Println(err)
+		return
+	}
+	w.Filter.Labels = splitList(*labels)
+	w.Interval = max(*interval, time.Second)
+	if *cursor != "" {
+		w.CursorFile = *cursor
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *format == watch.FormatText {
+		fmt.Fprintf(os.Stderr, "watching %s/%s, press Ctrl+C to stop\n", config.Owner, config.Repo)
+	}
+	err := w.Watch(ctx, func(change watch.Change) error {
+		return watch.PrintChange(os.Stdout, *format, change)
+	}, func(err error) {
+		fmt.Fprintf(os.Stderr, "error on watch: %v\n", err)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error on watch: %v\n", err)
+	}
+}